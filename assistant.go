@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
@@ -32,6 +33,11 @@ type Assistant struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (a Assistant) CreatedTime() time.Time {
+	return time.Unix(a.CreatedAt, 0)
+}
+
 type AssistantToolType string
 
 const (
@@ -127,6 +133,11 @@ type AssistantFile struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (f AssistantFile) CreatedTime() time.Time {
+	return time.Unix(f.CreatedAt, 0)
+}
+
 type AssistantFileRequest struct {
 	FileID string `json:"file_id"`
 }
@@ -139,6 +150,10 @@ type AssistantFilesList struct {
 
 // CreateAssistant creates a new assistant.
 func (c *Client) CreateAssistant(ctx context.Context, request AssistantRequest) (response Assistant, err error) {
+	if err = validateAssistantToolNames(request.Tools); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(assistantsSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion),
 		withExtraHeaders(request.ExtraHeaders),
@@ -175,6 +190,10 @@ func (c *Client) ModifyAssistant(
 	assistantID string,
 	request AssistantRequest,
 ) (response Assistant, err error) {
+	if err = validateAssistantToolNames(request.Tools); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("%s/%s", assistantsSuffix, assistantID)
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
@@ -330,3 +349,156 @@ func (c *Client) ListAssistantFiles(
 	err = c.sendRequest(req, &response)
 	return
 }
+
+// ListAssistantsWithOptions lists assistants one page at a time using pagination options.
+func (c *Client) ListAssistantsWithOptions(
+	ctx context.Context,
+	opts ...PaginationOption,
+) (AssistantsList, error) {
+	p := newPagination(opts)
+	return c.ListAssistants(ctx, p.limit, p.order, p.after, p.before)
+}
+
+// AssistantIterator walks all assistants, fetching pages lazily as they are consumed.
+type AssistantIterator struct {
+	client *Client
+	ctx    context.Context
+	page   pagination
+
+	assistants []Assistant
+	index      int
+	current    Assistant
+	done       bool
+	err        error
+}
+
+// AssistantsIterator returns an iterator over all assistants starting at the page
+// described by opts. A before cursor is only applied to the first page.
+func (c *Client) AssistantsIterator(ctx context.Context, opts ...PaginationOption) *AssistantIterator {
+	return &AssistantIterator{
+		client: c,
+		ctx:    ctx,
+		page:   newPagination(opts),
+	}
+}
+
+// Next advances to the next assistant, fetching the following page when needed.
+// It returns false once all assistants are consumed or an error occurred.
+func (it *AssistantIterator) Next() bool {
+	for it.index >= len(it.assistants) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			return false
+		}
+
+		var list AssistantsList
+		list, it.err = it.client.ListAssistants(it.ctx, it.page.limit, it.page.order, it.page.after, it.page.before)
+		if it.err != nil {
+			return false
+		}
+		it.assistants, it.index = list.Assistants, 0
+		it.done = !list.HasMore || list.LastID == nil
+		it.page.after, it.page.before = list.LastID, nil
+	}
+
+	it.current = it.assistants[it.index]
+	it.index++
+	return true
+}
+
+// Assistant returns the assistant the iterator currently points at.
+func (it *AssistantIterator) Assistant() Assistant {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *AssistantIterator) Err() error {
+	return it.err
+}
+
+// CloneAssistant creates a new assistant from the configuration of assistantID with
+// overrides applied on top. Only the fields set on overrides replace the source values;
+// a nil Tools keeps the source tools while an empty slice removes them.
+// The tools and tool resources are deep copied, so the clone shares no state with the
+// retrieved source assistant.
+func (c *Client) CloneAssistant(
+	ctx context.Context,
+	assistantID string,
+	overrides AssistantRequest,
+) (response Assistant, err error) {
+	source, err := c.RetrieveAssistant(ctx, assistantID)
+	if err != nil {
+		return
+	}
+
+	request := AssistantRequest{
+		Model:          source.Model,
+		Name:           source.Name,
+		Description:    source.Description,
+		Instructions:   source.Instructions,
+		FileIDs:        source.FileIDs,
+		Metadata:       source.Metadata,
+		ResponseFormat: source.ResponseFormat,
+		Temperature:    source.Temperature,
+		TopP:           source.TopP,
+		ExtraHeaders:   overrides.ExtraHeaders,
+		ExtraQuery:     overrides.ExtraQuery,
+		ExtraBody:      overrides.ExtraBody,
+	}
+	if err = deepCopyJSON(source.Tools, &request.Tools); err != nil {
+		return
+	}
+	if err = deepCopyJSON(source.ToolResources, &request.ToolResources); err != nil {
+		return
+	}
+	if request.Tools == nil {
+		request.Tools = []AssistantTool{}
+	}
+
+	if overrides.Model != "" {
+		request.Model = overrides.Model
+	}
+	if overrides.Name != nil {
+		request.Name = overrides.Name
+	}
+	if overrides.Description != nil {
+		request.Description = overrides.Description
+	}
+	if overrides.Instructions != nil {
+		request.Instructions = overrides.Instructions
+	}
+	if overrides.Tools != nil {
+		request.Tools = overrides.Tools
+	}
+	if overrides.ToolResources != nil {
+		request.ToolResources = overrides.ToolResources
+	}
+	if overrides.FileIDs != nil {
+		request.FileIDs = overrides.FileIDs
+	}
+	if overrides.Metadata != nil {
+		request.Metadata = overrides.Metadata
+	}
+	if overrides.ResponseFormat != nil {
+		request.ResponseFormat = overrides.ResponseFormat
+	}
+	if overrides.Temperature != nil {
+		request.Temperature = overrides.Temperature
+	}
+	if overrides.TopP != nil {
+		request.TopP = overrides.TopP
+	}
+
+	return c.CreateAssistant(ctx, request)
+}
+
+// deepCopyJSON copies src into dst through a JSON round trip.
+func deepCopyJSON(src, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
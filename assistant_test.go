@@ -445,3 +445,117 @@ When asked a question, write and run Python code to answer the question.`
 	err = client.DeleteAssistantFile(ctx, assistantID, assistantFileID)
 	checks.NoError(t, err, "DeleteAssistantFile error")
 }
+
+func TestAssistantsIterator(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	pages := map[string][]string{
+		"":       {"asst_1", "asst_2"},
+		"asst_2": {"asst_3"},
+	}
+	var requests int
+	server.RegisterHandler("/v1/assistants", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if query.Get("limit") != "2" || query.Get("order") != "asc" {
+			t.Fatalf("expected pagination options to be sent, got %s", r.URL.RawQuery)
+		}
+		list := openai.AssistantsList{}
+		for _, id := range pages[query.Get("after")] {
+			list.Assistants = append(list.Assistants, openai.Assistant{ID: id})
+		}
+		list.FirstID = &list.Assistants[0].ID
+		list.LastID = &list.Assistants[len(list.Assistants)-1].ID
+		_, list.HasMore = pages[*list.LastID]
+		resBytes, _ := json.Marshal(list)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	ctx := context.Background()
+	list, err := client.ListAssistantsWithOptions(ctx, openai.WithPageLimit(2), openai.WithPageOrder("asc"))
+	checks.NoError(t, err, "ListAssistantsWithOptions error")
+	if !list.HasMore || *list.LastID != "asst_2" {
+		t.Fatalf("unexpected first page: %+v", list)
+	}
+
+	requests = 0
+	it := client.AssistantsIterator(ctx, openai.WithPageLimit(2), openai.WithPageOrder("asc"))
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Assistant().ID)
+	}
+	checks.NoError(t, it.Err(), "AssistantsIterator error")
+	if len(ids) != 3 || ids[2] != "asst_3" {
+		t.Fatalf("unexpected assistants: %v", ids)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestCloneAssistant(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	name := "Source"
+	instructions := "Be terse."
+	server.RegisterHandler("/v1/assistants/asst_src", func(w http.ResponseWriter, _ *http.Request) {
+		resBytes, _ := json.Marshal(openai.Assistant{
+			ID:           "asst_src",
+			Model:        openai.GPT4o,
+			Name:         &name,
+			Instructions: &instructions,
+			Tools:        []openai.AssistantTool{{Type: openai.AssistantToolTypeFileSearch}},
+			ToolResources: &openai.AssistantToolResource{
+				FileSearch: &openai.AssistantToolFileSearch{VectorStoreIDs: []string{"vs_1"}},
+			},
+		})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	var created openai.AssistantRequest
+	server.RegisterHandler("/v1/assistants", func(w http.ResponseWriter, r *http.Request) {
+		var raw struct {
+			openai.AssistantRequest
+			Tools []openai.AssistantTool `json:"tools"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&raw)
+		checks.NoError(t, err, "Decode error")
+		created = raw.AssistantRequest
+		created.Tools = raw.Tools
+		fmt.Fprintln(w, `{"id":"asst_clone","object":"assistant"}`)
+	})
+
+	newInstructions := "Be verbose."
+	clone, err := client.CloneAssistant(context.Background(), "asst_src", openai.AssistantRequest{
+		Instructions: &newInstructions,
+	})
+	checks.NoError(t, err, "CloneAssistant error")
+	if clone.ID != "asst_clone" {
+		t.Fatalf("unexpected clone id: %s", clone.ID)
+	}
+	if created.Model != openai.GPT4o || *created.Name != name || *created.Instructions != newInstructions {
+		t.Fatalf("unexpected clone request: %+v", created)
+	}
+	if len(created.Tools) != 1 || created.Tools[0].Type != openai.AssistantToolTypeFileSearch {
+		t.Fatalf("expected source tools to be copied, got %+v", created.Tools)
+	}
+	if created.ToolResources == nil || created.ToolResources.FileSearch.VectorStoreIDs[0] != "vs_1" {
+		t.Fatalf("expected source tool resources to be copied, got %+v", created.ToolResources)
+	}
+}
+
+func TestCreateAssistantDuplicateToolName(t *testing.T) {
+	client, _, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	tools := []openai.AssistantTool{
+		{Type: openai.AssistantToolTypeFunction, Function: &openai.FunctionDefinition{Name: "lookup"}},
+		{Type: openai.AssistantToolTypeFileSearch},
+		{Type: openai.AssistantToolTypeFunction, Function: &openai.FunctionDefinition{Name: "lookup"}},
+	}
+	_, err := client.CreateAssistant(context.Background(), openai.AssistantRequest{Model: openai.GPT4o, Tools: tools})
+	checks.ErrorIs(t, err, openai.ErrDuplicateToolName, "CreateAssistant should reject duplicate tools")
+	_, err = client.ModifyAssistant(context.Background(), "asst_abc123", openai.AssistantRequest{Tools: tools})
+	checks.ErrorIs(t, err, openai.ErrDuplicateToolName, "ModifyAssistant should reject duplicate tools")
+}
@@ -3,6 +3,7 @@ package openai
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,8 @@ import (
 	utils "github.com/sashabaranov/go-openai/internal"
 )
 
+var ErrAudioValueUnknown = errors.New("unknown audio parameter value")
+
 // Whisper Defines the models provided by OpenAI to use when processing audio with OpenAI.
 const (
 	Whisper1 = "whisper-1"
@@ -52,6 +55,9 @@ type AudioRequest struct {
 	ExtraHeaders           map[string]string
 	ExtraQuery             map[string]string
 	ExtraBody              map[string]any
+	// Multipart optionally customizes the encoding of the upload. By default the
+	// scalar fields are written first and the file part last.
+	Multipart *MultipartOptions
 }
 
 // AudioResponse represents a response structure for audio API.
@@ -117,6 +123,12 @@ func (c *Client) callAudioAPI(
 	request AudioRequest,
 	endpointSuffix string,
 ) (response AudioResponse, err error) {
+	if !c.config.AllowUnknownAudioValues {
+		if err = request.validate(); err != nil {
+			return AudioResponse{}, err
+		}
+	}
+
 	var formBody bytes.Buffer
 	builder := c.createFormBuilder(&formBody)
 
@@ -152,6 +164,25 @@ func (c *Client) callAudioAPI(
 	return
 }
 
+// validate rejects response formats and timestamp granularities which are not one of
+// the AudioResponseFormat and TranscriptionTimestampGranularity values.
+func (r AudioRequest) validate() error {
+	switch r.Format {
+	case "", AudioResponseFormatJSON, AudioResponseFormatText, AudioResponseFormatSRT,
+		AudioResponseFormatVerboseJSON, AudioResponseFormatVTT:
+	default:
+		return fmt.Errorf("%w: response format %q", ErrAudioValueUnknown, r.Format)
+	}
+	for _, granularity := range r.TimestampGranularities {
+		switch granularity {
+		case TranscriptionTimestampGranularityWord, TranscriptionTimestampGranularitySegment:
+		default:
+			return fmt.Errorf("%w: timestamp granularity %q", ErrAudioValueUnknown, granularity)
+		}
+	}
+	return nil
+}
+
 // HasJSONResponse returns true if the response format is JSON.
 func (r AudioRequest) HasJSONResponse() bool {
 	return r.Format == "" || r.Format == AudioResponseFormatJSON || r.Format == AudioResponseFormatVerboseJSON
@@ -160,12 +191,42 @@ func (r AudioRequest) HasJSONResponse() bool {
 // audioMultipartForm creates a form with audio file contents and the name of the model to use for
 // audio processing.
 func audioMultipartForm(request AudioRequest, b utils.FormBuilder) error {
-	err := createFileField(request, b)
+	opts := request.Multipart
+	if opts == nil {
+		opts = &MultipartOptions{}
+	}
+
+	err := setMultipartBoundary(b, opts.Boundary)
 	if err != nil {
 		return err
 	}
 
-	err = b.WriteField("model", request.Model)
+	if opts.FileFirst {
+		err = createFileField(request, b)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = audioMultipartFields(request, b)
+	if err != nil {
+		return err
+	}
+
+	if !opts.FileFirst {
+		err = createFileField(request, b)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Close the multipart writer
+	return b.Close()
+}
+
+// audioMultipartFields writes the scalar fields of an audio request.
+func audioMultipartFields(request AudioRequest, b utils.FormBuilder) error {
+	err := b.WriteField("model", request.Model)
 	if err != nil {
 		return fmt.Errorf("writing model name: %w", err)
 	}
@@ -211,8 +272,7 @@ func audioMultipartForm(request AudioRequest, b utils.FormBuilder) error {
 		}
 	}
 
-	// Close the multipart writer
-	return b.Close()
+	return nil
 }
 
 // createFileField creates the "file" form field from either an existing file or by using the reader.
@@ -158,3 +158,70 @@ func handleAudioEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func TestAudioMultipartOrder(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var parts []string
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		parts = nil
+		reader, err := r.MultipartReader()
+		checks.NoError(t, err, "MultipartReader error")
+		for {
+			part, partErr := reader.NextPart()
+			if errors.Is(partErr, io.EOF) {
+				break
+			}
+			checks.NoError(t, partErr, "NextPart error")
+			parts = append(parts, part.FormName())
+		}
+		_, err = w.Write([]byte(`{"text":"hello"}`))
+		checks.NoError(t, err, "Write error")
+	})
+
+	request := openai.AudioRequest{
+		Model:    openai.Whisper1,
+		FilePath: "fake.mp3",
+		Reader:   bytes.NewReader([]byte("audio")),
+		Prompt:   "greeting",
+		Language: "en",
+	}
+	ctx := context.Background()
+	_, err := client.CreateTranscription(ctx, request)
+	checks.NoError(t, err, "CreateTranscription error")
+	if strings.Join(parts, ",") != "model,prompt,language,file" {
+		t.Fatalf("expected the file part last, got %v", parts)
+	}
+
+	request.Reader = bytes.NewReader([]byte("audio"))
+	request.Multipart = &openai.MultipartOptions{FileFirst: true}
+	_, err = client.CreateTranscription(ctx, request)
+	checks.NoError(t, err, "CreateTranscription error")
+	if strings.Join(parts, ",") != "file,model,prompt,language" {
+		t.Fatalf("expected the file part first, got %v", parts)
+	}
+}
+
+func TestAudioUnknownValues(t *testing.T) {
+	config := openai.DefaultConfig("whatever")
+	config.BaseURL = "http://localhost/v1"
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+	reader := bytes.NewBufferString("audio")
+
+	_, err := client.CreateTranscription(ctx, openai.AudioRequest{
+		Model:    openai.Whisper1,
+		FilePath: "fake.mp3",
+		Reader:   reader,
+		Format:   "src",
+	})
+	checks.ErrorIs(t, err, openai.ErrAudioValueUnknown, "expected misspelled response format to be rejected")
+	_, err = client.CreateTranslation(ctx, openai.AudioRequest{
+		Model:                  openai.Whisper1,
+		FilePath:               "fake.mp3",
+		Reader:                 reader,
+		TimestampGranularities: []openai.TranscriptionTimestampGranularity{"sentence"},
+	})
+	checks.ErrorIs(t, err, openai.ErrAudioValueUnknown, "expected unknown timestamp granularity to be rejected")
+}
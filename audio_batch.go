@@ -0,0 +1,163 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var ErrTranscriptionBatchFailed = errors.New("transcription failed for some files")
+
+// TranscriptionResult is the outcome of transcribing one file with TranscribeFiles.
+type TranscriptionResult struct {
+	Path     string
+	Response AudioResponse
+	// Err is set when the file could not be transcribed.
+	Err error
+}
+
+// TranscriptionBatchError lists the files TranscribeFiles failed to transcribe, in
+// input order. errors.Is matches ErrTranscriptionBatchFailed as well as the error of
+// each file, such as context.Canceled.
+type TranscriptionBatchError struct {
+	Failed []TranscriptionResult
+	Total  int
+}
+
+func (e *TranscriptionBatchError) Error() string {
+	failures := make([]string, len(e.Failed))
+	for i, result := range e.Failed {
+		failures[i] = fmt.Sprintf("%s: %v", result.Path, result.Err)
+	}
+	return fmt.Sprintf("%s: %d of %d files: %s",
+		ErrTranscriptionBatchFailed, len(e.Failed), e.Total, strings.Join(failures, "; "))
+}
+
+func (e *TranscriptionBatchError) Is(target error) bool {
+	if target == ErrTranscriptionBatchFailed {
+		return true
+	}
+	for _, result := range e.Failed {
+		if errors.Is(result.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type batchOptions struct {
+	failFast       bool
+	maxConcurrency int
+}
+
+// BatchOption configures a concurrent batch helper such as TranscribeFiles.
+type BatchOption func(*batchOptions)
+
+// WithFailFast stops a batch at the first failure: work in flight is canceled through
+// the context shared by the batch, no further item is started, and the error of the
+// failed item is returned instead of a batch error. DeleteMessages still returns a
+// *MessageDeletionBatchError, which wraps the error of the failed message.
+func WithFailFast() BatchOption {
+	return func(o *batchOptions) {
+		o.failFast = true
+	}
+}
+
+// WithMaxConcurrency lets a batch helper without a concurrency argument, such as
+// CreateMessages, send up to n requests at a time.
+func WithMaxConcurrency(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// TranscribeFiles transcribes the audio files at paths with up to concurrency requests
+// in flight, using request for every file with its FilePath replaced and its Reader
+// ignored. A non-positive concurrency transcribes one file at a time.
+//
+// The results follow the order of paths. When any file fails, the results are
+// returned together with a *TranscriptionBatchError. Files not started before ctx is
+// done fail with the context error. With WithFailFast the error of the first failed
+// file is returned as soon as the files in flight have stopped.
+func (c *Client) TranscribeFiles(
+	ctx context.Context,
+	paths []string,
+	request AudioRequest,
+	concurrency int,
+	opts ...BatchOption,
+) (results []TranscriptionResult, err error) {
+	var options batchOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(paths) {
+		concurrency = len(paths)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var firstErr error
+	var once sync.Once
+
+	results = make([]TranscriptionResult, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = c.transcribeFile(ctx, paths[i], request)
+				if results[i].Err != nil && options.failFast {
+					once.Do(func() {
+						firstErr = fmt.Errorf("%s: %w", paths[i], results[i].Err)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	dispatched := 0
+	for ; dispatched < len(paths); dispatched++ {
+		if options.failFast && ctx.Err() != nil {
+			break
+		}
+		indexes <- dispatched
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := dispatched; i < len(paths); i++ {
+		results[i] = TranscriptionResult{Path: paths[i], Err: ctx.Err()}
+	}
+	if firstErr != nil {
+		err = firstErr
+		return
+	}
+
+	var failed []TranscriptionResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) > 0 {
+		err = &TranscriptionBatchError{Failed: failed, Total: len(paths)}
+	}
+	return
+}
+
+func (c *Client) transcribeFile(ctx context.Context, path string, request AudioRequest) TranscriptionResult {
+	result := TranscriptionResult{Path: path}
+	if result.Err = ctx.Err(); result.Err != nil {
+		return result
+	}
+	request.FilePath, request.Reader = path, nil
+	result.Response, result.Err = c.CreateTranscription(ctx, request)
+	return result
+}
@@ -0,0 +1,147 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestTranscribeFiles(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		resBytes, _ := json.Marshal(openai.AudioResponse{Text: "transcript of " + header.Filename})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("recording%d.mp3", i))
+		test.CreateTestFile(t, path)
+		paths = append(paths, path)
+	}
+	missing := filepath.Join(dir, "missing.mp3")
+	paths = append(paths[:2], append([]string{missing}, paths[2:]...)...)
+
+	ctx := context.Background()
+	request := openai.AudioRequest{Model: openai.Whisper1}
+	results, err := client.TranscribeFiles(ctx, paths, request, 2)
+	var batchErr *openai.TranscriptionBatchError
+	if !errors.As(err, &batchErr) || !errors.Is(err, openai.ErrTranscriptionBatchFailed) {
+		t.Fatalf("expected a TranscriptionBatchError, got %v", err)
+	}
+	if len(batchErr.Failed) != 1 || batchErr.Failed[0].Path != missing || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected only the missing file to fail, got %v", err)
+	}
+	if len(results) != len(paths) || maxInFlight > 2 {
+		t.Fatalf("expected %d results with at most 2 requests in flight, got %d and %d",
+			len(paths), len(results), maxInFlight)
+	}
+	for i, result := range results {
+		if result.Path != paths[i] {
+			t.Fatalf("result %d is out of order: %s", i, result.Path)
+		}
+		if result.Err == nil && !strings.HasSuffix(result.Response.Text, filepath.Base(paths[i])) {
+			t.Fatalf("unexpected transcript for %s: %q", paths[i], result.Response.Text)
+		}
+	}
+
+	results, err = client.TranscribeFiles(ctx, paths[:2], request, 0)
+	checks.NoError(t, err, "TranscribeFiles error")
+	if len(results) != 2 || results[1].Response.Text != "transcript of recording1.mp3" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.TranscribeFiles(cancelled, paths, request, 2)
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != len(paths) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected every file to fail with the context error, got %v", err)
+	}
+}
+
+func TestTranscribeFilesFailFast(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var mu sync.Mutex
+	requests := 0
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		fmt.Fprintln(w, `{"text":"too late"}`)
+	})
+
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.mp3")
+	paths := []string{filepath.Join(dir, "slow.mp3"), missing}
+	for i := 0; i < 5; i++ {
+		paths = append(paths, filepath.Join(dir, fmt.Sprintf("recording%d.mp3", i)))
+	}
+	for _, path := range paths {
+		if path != missing {
+			test.CreateTestFile(t, path)
+		}
+	}
+
+	start := time.Now()
+	results, err := client.TranscribeFiles(context.Background(), paths, openai.AudioRequest{Model: openai.Whisper1}, 2,
+		openai.WithFailFast())
+	if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), missing) {
+		t.Fatalf("expected the error of the missing file, got %v", err)
+	}
+	var batchErr *openai.TranscriptionBatchError
+	if errors.As(err, &batchErr) {
+		t.Fatalf("expected the first error instead of a batch error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the in-flight request to be canceled, took %s", elapsed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests > 2 {
+		t.Fatalf("expected no files to be started after the failure, got %d requests", requests)
+	}
+	if len(results) != len(paths) || !errors.Is(results[len(paths)-1].Err, context.Canceled) {
+		t.Fatalf("expected files not started to fail with context.Canceled, got %+v", results)
+	}
+}
@@ -34,6 +34,9 @@ func TestAudioWithFailingFormBuilder(t *testing.T) {
 
 	mockFailedErr := fmt.Errorf("mock form builder fail")
 	mockBuilder := &mockFormBuilder{}
+	mockBuilder.mockWriteField = func(string, string) error {
+		return nil
+	}
 
 	mockBuilder.mockCreateFormFile = func(string, *os.File) error {
 		return mockFailedErr
@@ -140,9 +143,7 @@ type failingAudioRequestBuilder struct{ err error }
 
 func (f *failingAudioRequestBuilder) Build(
 	_ context.Context,
-	_, _ string,
-	_ any,
-	_ http.Header,
+	_ *utils.Request,
 ) (*http.Request, error) {
 	return nil, f.err
 }
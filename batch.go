@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const batchesSuffix = "/batches"
@@ -90,6 +91,11 @@ type Batch struct {
 	Metadata         map[string]any     `json:"metadata"`
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (b Batch) CreatedTime() time.Time {
+	return time.Unix(int64(b.CreatedAt), 0)
+}
+
 type BatchRequestCounts struct {
 	Total     int `json:"total"`
 	Completed int `json:"completed"`
@@ -24,6 +24,15 @@ var (
 	ErrChatCompletionInvalidModel       = errors.New("this model is not supported with this method, please use CreateCompletion client method instead") //nolint:lll
 	ErrChatCompletionStreamNotSupported = errors.New("streaming is not supported with this method, please use CreateChatCompletionStream")              //nolint:lll
 	ErrContentFieldsMisused             = errors.New("can't use both Content and MultiContent properties simultaneously")
+	ErrWebSearchInvalidContextSize      = errors.New("web search context size must be one of low, medium or high")
+	ErrNoChoices                        = errors.New("chat completion response contains no choices")
+	ErrPromptCacheKeyTooLong            = errors.New("prompt cache key is too long")
+	ErrSafetyIdentifierTooLong          = errors.New("safety identifier is too long")
+)
+
+const (
+	maxPromptCacheKeyLength   = 64
+	maxSafetyIdentifierLength = 64
 )
 
 type Hate struct {
@@ -75,6 +84,16 @@ const (
 	ImageURLDetailAuto ImageURLDetail = "auto"
 )
 
+// valid reports whether the detail is empty or one of the ImageURLDetail constants.
+func (d ImageURLDetail) valid() bool {
+	switch d {
+	case "", ImageURLDetailHigh, ImageURLDetailLow, ImageURLDetailAuto:
+		return true
+	default:
+		return false
+	}
+}
+
 type ChatMessageImageURL struct {
 	URL    string         `json:"url,omitempty"`
 	Detail ImageURLDetail `json:"detail,omitempty"`
@@ -129,14 +148,21 @@ const (
 	ChatMessagePartTypeImageURL ChatMessagePartType = "image_url"
 	ChatMessagePartTypeVideoURL ChatMessagePartType = "video_url"
 	ChatMessagePartTypeVideo    ChatMessagePartType = "video"
+	ChatMessagePartTypeFile     ChatMessagePartType = "file"
 )
 
+// ChatMessageFile references an uploaded file from a content part.
+type ChatMessageFile struct {
+	FileID string `json:"file_id,omitempty"`
+}
+
 type ChatMessagePart struct {
 	Type     ChatMessagePartType  `json:"type,omitempty"`
 	Text     string               `json:"text,omitempty"`
 	ImageURL *ChatMessageImageURL `json:"image_url,omitempty"`
 	VideoURL *ChatMessageVideoURL `json:"video_url,omitempty"`
 	Video    *ChatMessageVideo    `json:"video,omitempty"`
+	File     *ChatMessageFile     `json:"file,omitempty"`
 }
 
 type ChatCompletionMessage struct {
@@ -164,6 +190,9 @@ type ChatCompletionMessage struct {
 
 	// For Role=tool prompts this should be set to the ID given in the assistant's prior request to call a tool.
 	ToolCallID string `json:"tool_call_id,omitempty"`
+
+	// Audio is the audio output of an assistant message when audio was requested.
+	Audio *ChatCompletionAudio `json:"audio,omitempty"`
 }
 
 func (m ChatCompletionMessage) MarshalJSON() ([]byte, error) {
@@ -172,29 +201,31 @@ func (m ChatCompletionMessage) MarshalJSON() ([]byte, error) {
 	}
 	if len(m.MultiContent) > 0 {
 		msg := struct {
-			Role             string            `json:"role"`
-			Content          string            `json:"-"`
-			Refusal          string            `json:"refusal,omitempty"`
-			MultiContent     []ChatMessagePart `json:"content,omitempty"`
-			Name             string            `json:"name,omitempty"`
-			ReasoningContent string            `json:"reasoning_content,omitempty"`
-			FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
-			ToolCalls        []ToolCall        `json:"tool_calls,omitempty"`
-			ToolCallID       string            `json:"tool_call_id,omitempty"`
+			Role             string               `json:"role"`
+			Content          string               `json:"-"`
+			Refusal          string               `json:"refusal,omitempty"`
+			MultiContent     []ChatMessagePart    `json:"content,omitempty"`
+			Name             string               `json:"name,omitempty"`
+			ReasoningContent string               `json:"reasoning_content,omitempty"`
+			FunctionCall     *FunctionCall        `json:"function_call,omitempty"`
+			ToolCalls        []ToolCall           `json:"tool_calls,omitempty"`
+			ToolCallID       string               `json:"tool_call_id,omitempty"`
+			Audio            *ChatCompletionAudio `json:"audio,omitempty"`
 		}(m)
 		return json.Marshal(msg)
 	}
 
 	msg := struct {
-		Role             string            `json:"role"`
-		Content          string            `json:"content,omitempty"`
-		Refusal          string            `json:"refusal,omitempty"`
-		MultiContent     []ChatMessagePart `json:"-"`
-		Name             string            `json:"name,omitempty"`
-		ReasoningContent string            `json:"reasoning_content,omitempty"`
-		FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
-		ToolCalls        []ToolCall        `json:"tool_calls,omitempty"`
-		ToolCallID       string            `json:"tool_call_id,omitempty"`
+		Role             string               `json:"role"`
+		Content          string               `json:"content,omitempty"`
+		Refusal          string               `json:"refusal,omitempty"`
+		MultiContent     []ChatMessagePart    `json:"-"`
+		Name             string               `json:"name,omitempty"`
+		ReasoningContent string               `json:"reasoning_content,omitempty"`
+		FunctionCall     *FunctionCall        `json:"function_call,omitempty"`
+		ToolCalls        []ToolCall           `json:"tool_calls,omitempty"`
+		ToolCallID       string               `json:"tool_call_id,omitempty"`
+		Audio            *ChatCompletionAudio `json:"audio,omitempty"`
 	}(m)
 	return json.Marshal(msg)
 }
@@ -205,11 +236,12 @@ func (m *ChatCompletionMessage) UnmarshalJSON(bs []byte) error {
 		Content          string `json:"content"`
 		Refusal          string `json:"refusal,omitempty"`
 		MultiContent     []ChatMessagePart
-		Name             string        `json:"name,omitempty"`
-		ReasoningContent string        `json:"reasoning_content,omitempty"`
-		FunctionCall     *FunctionCall `json:"function_call,omitempty"`
-		ToolCalls        []ToolCall    `json:"tool_calls,omitempty"`
-		ToolCallID       string        `json:"tool_call_id,omitempty"`
+		Name             string               `json:"name,omitempty"`
+		ReasoningContent string               `json:"reasoning_content,omitempty"`
+		FunctionCall     *FunctionCall        `json:"function_call,omitempty"`
+		ToolCalls        []ToolCall           `json:"tool_calls,omitempty"`
+		ToolCallID       string               `json:"tool_call_id,omitempty"`
+		Audio            *ChatCompletionAudio `json:"audio,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(bs, &msg); err == nil {
@@ -219,13 +251,14 @@ func (m *ChatCompletionMessage) UnmarshalJSON(bs []byte) error {
 	multiMsg := struct {
 		Role             string `json:"role"`
 		Content          string
-		Refusal          string            `json:"refusal,omitempty"`
-		MultiContent     []ChatMessagePart `json:"content"`
-		Name             string            `json:"name,omitempty"`
-		ReasoningContent string            `json:"reasoning_content,omitempty"`
-		FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
-		ToolCalls        []ToolCall        `json:"tool_calls,omitempty"`
-		ToolCallID       string            `json:"tool_call_id,omitempty"`
+		Refusal          string               `json:"refusal,omitempty"`
+		MultiContent     []ChatMessagePart    `json:"content"`
+		Name             string               `json:"name,omitempty"`
+		ReasoningContent string               `json:"reasoning_content,omitempty"`
+		FunctionCall     *FunctionCall        `json:"function_call,omitempty"`
+		ToolCalls        []ToolCall           `json:"tool_calls,omitempty"`
+		ToolCallID       string               `json:"tool_call_id,omitempty"`
+		Audio            *ChatCompletionAudio `json:"audio,omitempty"`
 	}{}
 	if err := json.Unmarshal(bs, &multiMsg); err != nil {
 		return err
@@ -284,7 +317,7 @@ type ChatCompletionRequest struct {
 	TopP                float32                       `json:"top_p,omitempty"`
 	N                   int                           `json:"n,omitempty"`
 	Stream              bool                          `json:"stream,omitempty"`
-	Stop                []string                      `json:"stop,omitempty"`
+	Stop                []string                      `json:"stop,omitempty"` // Omitted when nil, sent as [] when empty.
 	PresencePenalty     float32                       `json:"presence_penalty,omitempty"`
 	ResponseFormat      *ChatCompletionResponseFormat `json:"response_format,omitempty"`
 	Seed                *int                          `json:"seed,omitempty"`
@@ -300,13 +333,18 @@ type ChatCompletionRequest struct {
 	// TopLogProbs is an integer between 0 and 5 specifying the number of most likely tokens to return at each
 	// token position, each with an associated log probability.
 	// logprobs must be set to true if this parameter is used.
-	TopLogProbs int    `json:"top_logprobs,omitempty"`
-	User        string `json:"user,omitempty"`
+	TopLogProbs int `json:"top_logprobs,omitempty"`
+	// User identifies the end user. SafetyIdentifier supersedes it for abuse monitoring.
+	User string `json:"user,omitempty"`
+	// Functions is omitted when nil. A non-nil empty Functions is sent as [].
+	//
 	// Deprecated: use Tools instead.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
 	// Deprecated: use ToolChoice instead.
-	FunctionCall any    `json:"function_call,omitempty"`
-	Tools        []Tool `json:"tools,omitempty"`
+	FunctionCall any `json:"function_call,omitempty"`
+	// Tools is omitted when nil. A non-nil empty Tools is sent as [], which
+	// api.openai.com rejects, so leave it nil when the request has no tools.
+	Tools []Tool `json:"tools,omitempty"`
 	// This can be either a string or an ToolChoice object.
 	ToolChoice any `json:"tool_choice,omitempty"`
 	// Options for streaming response. Only set this when you set stream: true.
@@ -335,6 +373,40 @@ type ChatCompletionRequest struct {
 	ExtraQuery map[string]string `json:"extra_query,omitempty"`
 	// Thinking thinking type
 	Thinking *Thinking `json:"thinking,omitempty"`
+	// WebSearchOptions configures the web search tool for search enabled models.
+	WebSearchOptions *WebSearchOptions `json:"web_search_options,omitempty"`
+	// PromptCacheKey groups requests sharing a long common prefix so they are routed
+	// to the same prompt cache. At most 64 characters.
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
+	// SafetyIdentifier is a stable identifier of the end user, such as a hash of their
+	// username, used to detect abuse. It supersedes User. At most 64 characters.
+	SafetyIdentifier string `json:"safety_identifier,omitempty"`
+	// Modalities lists the output types to generate, such as text and audio.
+	Modalities []ChatCompletionModality `json:"modalities,omitempty"`
+	// Audio sets the voice and format of the output when Modalities includes audio.
+	Audio *ChatCompletionAudioParams `json:"audio,omitempty"`
+}
+
+// MarshalJSON omits nil Stop, Functions and Tools but sends a non-nil empty slice as [],
+// since some backends treat an explicit empty list differently from a missing field.
+func (r ChatCompletionRequest) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionRequest
+	request := struct {
+		alias
+		Stop      *[]string             `json:"stop,omitempty"`
+		Functions *[]FunctionDefinition `json:"functions,omitempty"`
+		Tools     *[]Tool               `json:"tools,omitempty"`
+	}{alias: alias(r)}
+	if r.Stop != nil {
+		request.Stop = &r.Stop
+	}
+	if r.Functions != nil {
+		request.Functions = &r.Functions
+	}
+	if r.Tools != nil {
+		request.Tools = &r.Tools
+	}
+	return json.Marshal(request)
 }
 
 type ThinkingType string
@@ -349,6 +421,66 @@ type Thinking struct {
 	Type ThinkingType `json:"type,omitempty"`
 }
 
+type WebSearchContextSize string
+
+const (
+	WebSearchContextSizeLow    WebSearchContextSize = "low"
+	WebSearchContextSizeMedium WebSearchContextSize = "medium"
+	WebSearchContextSizeHigh   WebSearchContextSize = "high"
+)
+
+type WebSearchOptions struct {
+	// SearchContextSize guides how much context is retrieved from the web. Defaults to medium.
+	SearchContextSize WebSearchContextSize `json:"search_context_size,omitempty"`
+	// UserLocation is the approximate location used to localize search results.
+	UserLocation *WebSearchUserLocation `json:"user_location,omitempty"`
+}
+
+func (o *WebSearchOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	switch o.SearchContextSize {
+	case "", WebSearchContextSizeLow, WebSearchContextSizeMedium, WebSearchContextSizeHigh:
+		return nil
+	default:
+		return ErrWebSearchInvalidContextSize
+	}
+}
+
+// WebSearchUserLocation is an approximate user location. All fields are optional.
+type WebSearchUserLocation struct {
+	// Country is the two-letter ISO country code, e.g. US.
+	Country string `json:"country,omitempty"`
+	City    string `json:"city,omitempty"`
+	Region  string `json:"region,omitempty"`
+	// Timezone is the IANA timezone, e.g. America/Los_Angeles.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+func (l WebSearchUserLocation) MarshalJSON() ([]byte, error) {
+	type location WebSearchUserLocation
+	return json.Marshal(struct {
+		Type        string   `json:"type"`
+		Approximate location `json:"approximate"`
+	}{
+		Type:        "approximate",
+		Approximate: location(l),
+	})
+}
+
+func (l *WebSearchUserLocation) UnmarshalJSON(data []byte) error {
+	type location WebSearchUserLocation
+	var wrapper struct {
+		Approximate location `json:"approximate"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	*l = WebSearchUserLocation(wrapper.Approximate)
+	return nil
+}
+
 type StreamOptions struct {
 	// If set, an additional chunk will be streamed before the data: [DONE] message.
 	// The usage field on this chunk shows the token usage statistics for the entire request,
@@ -466,6 +598,33 @@ type ChatCompletionResponse struct {
 	httpHeader
 }
 
+// FirstChoice returns the first choice of the response, or ErrNoChoices when the
+// response has none, e.g. because every choice was filtered.
+func (r ChatCompletionResponse) FirstChoice() (ChatCompletionChoice, error) {
+	if len(r.Choices) == 0 {
+		return ChatCompletionChoice{}, ErrNoChoices
+	}
+	return r.Choices[0], nil
+}
+
+// prepareChatIdentifiers fills in the configured DefaultSafetyIdentifier when the request
+// identifies its end user neither with SafetyIdentifier nor with User, then checks the
+// length of the identifiers.
+func (c *Client) prepareChatIdentifiers(request *ChatCompletionRequest) error {
+	if request.SafetyIdentifier == "" && request.User == "" {
+		request.SafetyIdentifier = c.config.DefaultSafetyIdentifier
+	}
+	if len(request.PromptCacheKey) > maxPromptCacheKeyLength {
+		return fmt.Errorf("%w: %d characters, the limit is %d",
+			ErrPromptCacheKeyTooLong, len(request.PromptCacheKey), maxPromptCacheKeyLength)
+	}
+	if len(request.SafetyIdentifier) > maxSafetyIdentifierLength {
+		return fmt.Errorf("%w: %d characters, the limit is %d",
+			ErrSafetyIdentifierTooLong, len(request.SafetyIdentifier), maxSafetyIdentifierLength)
+	}
+	return nil
+}
+
 // CreateChatCompletion — API call to Create a completion for the chat message.
 func (c *Client) CreateChatCompletion(
 	ctx context.Context,
@@ -487,6 +646,26 @@ func (c *Client) CreateChatCompletion(
 		return
 	}
 
+	if err = validateInlineImagePayload(request.Messages, c.config.MaxInlineImagePayloadBytes); err != nil {
+		return
+	}
+
+	if err = request.WebSearchOptions.validate(); err != nil {
+		return
+	}
+
+	if err = validateChatToolNames(request); err != nil {
+		return
+	}
+
+	if err = c.prepareChatIdentifiers(&request); err != nil {
+		return
+	}
+
+	if err = c.validateModelEndpoint(request.Model, urlSuffix); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -501,5 +680,15 @@ func (c *Client) CreateChatCompletion(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil && c.config.ErrorOnEmptyChoices && len(response.Choices) == 0 {
+		err = ErrNoChoices
+	}
+	if err == nil && request.Audio != nil {
+		for i := range response.Choices {
+			if audio := response.Choices[i].Message.Audio; audio != nil {
+				audio.Format = request.Audio.Format
+			}
+		}
+	}
 	return
 }
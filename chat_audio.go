@@ -0,0 +1,107 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var ErrChatMessageNoAudio = errors.New("chat completion message has no audio")
+
+// ChatCompletionModality is an output type a chat completion may generate.
+type ChatCompletionModality string
+
+const (
+	ChatCompletionModalityText  ChatCompletionModality = "text"
+	ChatCompletionModalityAudio ChatCompletionModality = "audio"
+)
+
+// ChatCompletionAudioFormat is the encoding of chat completion audio output.
+type ChatCompletionAudioFormat string
+
+const (
+	ChatCompletionAudioFormatWAV   ChatCompletionAudioFormat = "wav"
+	ChatCompletionAudioFormatMP3   ChatCompletionAudioFormat = "mp3"
+	ChatCompletionAudioFormatFLAC  ChatCompletionAudioFormat = "flac"
+	ChatCompletionAudioFormatOpus  ChatCompletionAudioFormat = "opus"
+	ChatCompletionAudioFormatAAC   ChatCompletionAudioFormat = "aac"
+	ChatCompletionAudioFormatPCM16 ChatCompletionAudioFormat = "pcm16"
+)
+
+// extension returns the file extension of the format, "" when it is unknown.
+func (f ChatCompletionAudioFormat) extension() string {
+	switch f {
+	case ChatCompletionAudioFormatWAV, ChatCompletionAudioFormatMP3, ChatCompletionAudioFormatFLAC,
+		ChatCompletionAudioFormatOpus, ChatCompletionAudioFormatAAC:
+		return "." + string(f)
+	case ChatCompletionAudioFormatPCM16:
+		return ".pcm"
+	default:
+		return ""
+	}
+}
+
+// ChatCompletionAudioParams requests audio output, together with the audio modality.
+type ChatCompletionAudioParams struct {
+	Voice  SpeechVoice               `json:"voice"`
+	Format ChatCompletionAudioFormat `json:"format"`
+}
+
+// ChatCompletionAudio is the audio output of an assistant message. To refer to it in
+// a later request only ID is needed.
+type ChatCompletionAudio struct {
+	ID string `json:"id"`
+	// Data is the base64 encoded audio.
+	Data       string `json:"data,omitempty"`
+	ExpiresAt  int64  `json:"expires_at,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	// Format is the format of the request which produced the audio, set by
+	// CreateChatCompletion since the response does not repeat it.
+	Format ChatCompletionAudioFormat `json:"-"`
+}
+
+// SaveAudio decodes the audio output of the message and writes it to path. A path
+// without an extension gets the extension of the audio format, taken from Format or
+// else detected from the data. It returns ErrChatMessageNoAudio when the message
+// carries no audio data.
+func (m ChatCompletionMessage) SaveAudio(path string) error {
+	if m.Audio == nil || m.Audio.Data == "" {
+		return ErrChatMessageNoAudio
+	}
+	data, err := base64.StdEncoding.DecodeString(m.Audio.Data)
+	if err != nil {
+		return fmt.Errorf("error, decoding audio data: %w", err)
+	}
+
+	if filepath.Ext(path) == "" {
+		format := m.Audio.Format
+		if format == "" {
+			format = detectAudioFormat(data)
+		}
+		path += format.extension()
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// detectAudioFormat recognizes the container formats from their leading bytes. Raw
+// pcm16 audio has no header and is not recognized.
+func detectAudioFormat(data []byte) ChatCompletionAudioFormat {
+	switch {
+	case bytes.HasPrefix(data, []byte("RIFF")):
+		return ChatCompletionAudioFormatWAV
+	case bytes.HasPrefix(data, []byte("ID3")),
+		len(data) > 1 && data[0] == 0xFF && data[1]&0xF6 == 0xF2:
+		return ChatCompletionAudioFormatMP3
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return ChatCompletionAudioFormatFLAC
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return ChatCompletionAudioFormatOpus
+	case len(data) > 1 && data[0] == 0xFF && data[1]&0xF6 == 0xF0:
+		return ChatCompletionAudioFormatAAC
+	default:
+		return ""
+	}
+}
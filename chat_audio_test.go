@@ -0,0 +1,68 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestChatCompletionMessageSaveAudio(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt ")
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		err := json.NewDecoder(r.Body).Decode(&body)
+		checks.NoError(t, err, "Decode error")
+		if fmt.Sprint(body["modalities"]) != "[text audio]" ||
+			fmt.Sprint(body["audio"]) != "map[format:wav voice:alloy]" {
+			t.Errorf("unexpected audio request: %v", body)
+		}
+		fmt.Fprintf(w, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":null,`+
+			`"audio":{"id":"audio_abc123","data":%q,"expires_at":1729018505,"transcript":"Hello!"}}}]}`,
+			base64.StdEncoding.EncodeToString(wav))
+	})
+
+	res, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:      "gpt-4o-audio-preview",
+		Messages:   []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Say hello"}},
+		Modalities: []openai.ChatCompletionModality{openai.ChatCompletionModalityText, openai.ChatCompletionModalityAudio},
+		Audio:      &openai.ChatCompletionAudioParams{Voice: openai.VoiceAlloy, Format: openai.ChatCompletionAudioFormatWAV},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	message := res.Choices[0].Message
+	if message.Audio == nil || message.Audio.Transcript != "Hello!" || message.Audio.ID != "audio_abc123" {
+		t.Fatalf("unexpected audio output: %+v", message.Audio)
+	}
+
+	dir := t.TempDir()
+	checks.NoError(t, message.SaveAudio(filepath.Join(dir, "reply")), "SaveAudio error")
+	data, err := os.ReadFile(filepath.Join(dir, "reply.wav"))
+	checks.NoError(t, err, "expected the wav extension to be added")
+	if !bytes.Equal(data, wav) {
+		t.Fatalf("unexpected audio data: %q", data)
+	}
+	checks.NoError(t, message.SaveAudio(filepath.Join(dir, "reply.bin")), "SaveAudio error")
+	_, err = os.Stat(filepath.Join(dir, "reply.bin"))
+	checks.NoError(t, err, "expected an explicit extension to be kept")
+
+	detected := openai.ChatCompletionMessage{Audio: &openai.ChatCompletionAudio{
+		Data: base64.StdEncoding.EncodeToString([]byte("ID3\x04\x00")),
+	}}
+	checks.NoError(t, detected.SaveAudio(filepath.Join(dir, "detected")), "SaveAudio error")
+	_, err = os.Stat(filepath.Join(dir, "detected.mp3"))
+	checks.NoError(t, err, "expected the mp3 extension to be detected")
+
+	err = openai.ChatCompletionMessage{Content: "text only"}.SaveAudio(filepath.Join(dir, "none"))
+	checks.ErrorIs(t, err, openai.ErrChatMessageNoAudio, "expected a message without audio to fail")
+}
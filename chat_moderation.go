@@ -0,0 +1,127 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const defaultModerationInterval = 20
+
+var ErrContentFlagged = errors.New("streamed content was flagged by moderation")
+
+// ContentFlaggedError carries the moderation result which stopped a moderated stream.
+type ContentFlaggedError struct {
+	// Text is the accumulated output which was flagged.
+	Text   string
+	Result Result
+}
+
+func (e *ContentFlaggedError) Error() string {
+	return fmt.Sprintf("%s after %d characters", ErrContentFlagged, len(e.Text))
+}
+
+func (e *ContentFlaggedError) Unwrap() error {
+	return ErrContentFlagged
+}
+
+// ModerationGateOptions configures CreateModeratedChatCompletionStream.
+type ModerationGateOptions struct {
+	// Interval is the number of streamed content chunks, roughly one token each,
+	// between two moderation checks. Defaults to 20.
+	Interval int
+	// Model is the moderation model. Defaults to omni-moderation-latest.
+	Model string
+}
+
+// ModeratedChatCompletionStream is a chat completion stream whose accumulated output
+// is checked by the moderation endpoint while it is received.
+type ModeratedChatCompletionStream struct {
+	*ChatCompletionStream
+
+	ctx     context.Context
+	client  *Client
+	options ModerationGateOptions
+
+	text    strings.Builder
+	pending int
+}
+
+// CreateModeratedChatCompletionStream streams a chat completion and moderates the text of
+// the first choice every options.Interval chunks and once more at the end of the stream.
+// When the text is flagged the stream is closed and Recv returns a *ContentFlaggedError
+// wrapping ErrContentFlagged, so the caller can withdraw what was already shown.
+func (c *Client) CreateModeratedChatCompletionStream(
+	ctx context.Context,
+	request ChatCompletionRequest,
+	options ModerationGateOptions,
+) (*ModeratedChatCompletionStream, error) {
+	if options.Interval <= 0 {
+		options.Interval = defaultModerationInterval
+	}
+	if options.Model == "" {
+		options.Model = ModerationOmniLatest
+	}
+
+	stream, err := c.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &ModeratedChatCompletionStream{
+		ChatCompletionStream: stream,
+		ctx:                  ctx,
+		client:               c,
+		options:              options,
+	}, nil
+}
+
+// Recv returns the next response of the stream. Moderation runs after every Interval
+// chunks, so up to Interval chunks may be delivered before a flag is detected.
+func (s *ModeratedChatCompletionStream) Recv() (response ChatCompletionStreamResponse, err error) {
+	response, err = s.ChatCompletionStream.Recv()
+	if errors.Is(err, io.EOF) {
+		if s.pending > 0 {
+			if modErr := s.moderate(); modErr != nil {
+				return response, modErr
+			}
+		}
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	if len(response.Choices) > 0 && response.Choices[0].Delta.Content != "" {
+		s.text.WriteString(response.Choices[0].Delta.Content)
+		s.pending++
+	}
+	if s.pending >= s.options.Interval {
+		err = s.moderate()
+	}
+	return
+}
+
+// Text returns the output accumulated so far.
+func (s *ModeratedChatCompletionStream) Text() string {
+	return s.text.String()
+}
+
+func (s *ModeratedChatCompletionStream) moderate() error {
+	s.pending = 0
+	response, err := s.client.Moderations(s.ctx, ModerationRequest{
+		Model: s.options.Model,
+		Input: s.text.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, result := range response.Results {
+		if result.Flagged {
+			s.Close()
+			return &ContentFlaggedError{Text: s.text.String(), Result: result}
+		}
+	}
+	return nil
+}
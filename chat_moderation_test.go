@@ -0,0 +1,72 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestModeratedChatCompletionStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var moderations int
+	server.RegisterHandler("/v1/moderations", func(w http.ResponseWriter, r *http.Request) {
+		moderations++
+		request, err := getModerationBody(r)
+		checks.NoError(t, err, "getModerationBody error")
+		flagged := strings.Contains(request.Input, "hate")
+		fmt.Fprintf(w, `{"results":[{"flagged":%t,"categories":{"hate":%t}}]}`, flagged, flagged)
+	})
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		body, _ := io.ReadAll(r.Body)
+		words := []string{"I ", "like ", "you"}
+		if strings.Contains(string(body), "Provoke") {
+			words = []string{"I ", "hate ", "you"}
+		}
+		for i, word := range words {
+			fmt.Fprintf(w, "data: {\"id\":\"%d\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", i, word)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello"}},
+	}
+	ctx := context.Background()
+	stream, err := client.CreateModeratedChatCompletionStream(ctx, request, openai.ModerationGateOptions{Interval: 2})
+	checks.NoError(t, err, "CreateModeratedChatCompletionStream error")
+	for {
+		_, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "stream.Recv() failed")
+	}
+	stream.Close()
+	if stream.Text() != "I like you" || moderations != 2 {
+		t.Fatalf("unexpected text %q after %d moderations", stream.Text(), moderations)
+	}
+
+	request.Messages[0].Content = "Provoke"
+	stream, err = client.CreateModeratedChatCompletionStream(ctx, request, openai.ModerationGateOptions{Interval: 2})
+	checks.NoError(t, err, "CreateModeratedChatCompletionStream error")
+	defer stream.Close()
+	_, err = stream.Recv()
+	checks.NoError(t, err, "stream.Recv() failed")
+	_, err = stream.Recv()
+	checks.ErrorIs(t, err, openai.ErrContentFlagged, "expected the stream to be flagged")
+	var flaggedErr *openai.ContentFlaggedError
+	if !errors.As(err, &flaggedErr) || !flaggedErr.Result.Categories.Hate {
+		t.Fatalf("expected a ContentFlaggedError for hate, got %v", err)
+	}
+}
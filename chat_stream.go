@@ -2,9 +2,16 @@ package openai
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 )
 
+// ChatCompletionStreamChoiceDelta is the part of a choice carried by one stream chunk.
+// Each entry of ToolCalls is a fragment of the tool call at position *Index: the first
+// fragment of a call carries its ID, type and function name, and the following ones
+// append to Function.Arguments. StreamChatCollectToolCalls reassembles them.
 type ChatCompletionStreamChoiceDelta struct {
 	Content      string        `json:"content,omitempty"`
 	Role         string        `json:"role,omitempty"`
@@ -71,6 +78,17 @@ type ChatCompletionStream struct {
 	*streamReader[ChatCompletionStreamResponse]
 }
 
+// NewChatCompletionStreamFromReader returns a ChatCompletionStream reading a raw
+// server-sent events stream from r, such as one saved with WithStreamCapture,
+// without making a network call. Every event is a "data: <json chunk>" line, events
+// are separated by a blank line and the stream ends with "data: [DONE]"; other
+// lines such as "event: message" are ignored.
+func NewChatCompletionStreamFromReader(r io.Reader) *ChatCompletionStream {
+	return &ChatCompletionStream{
+		streamReader: newStreamReaderFromReader[ChatCompletionStreamResponse](r),
+	}
+}
+
 // CreateChatCompletionStream — API call to create a chat completion w/ streaming
 // support. It sets whether to stream back partial progress. If set, tokens will be
 // sent as data-only server-sent events as they become available, with the
@@ -78,6 +96,7 @@ type ChatCompletionStream struct {
 func (c *Client) CreateChatCompletionStream(
 	ctx context.Context,
 	request ChatCompletionRequest,
+	opts ...StreamOption,
 ) (stream *ChatCompletionStream, err error) {
 	urlSuffix := chatCompletionsSuffix
 	if !checkEndpointSupportsModel(urlSuffix, request.Model) {
@@ -91,6 +110,26 @@ func (c *Client) CreateChatCompletionStream(
 		return
 	}
 
+	if err = validateInlineImagePayload(request.Messages, c.config.MaxInlineImagePayloadBytes); err != nil {
+		return
+	}
+
+	if err = request.WebSearchOptions.validate(); err != nil {
+		return
+	}
+
+	if err = validateChatToolNames(request); err != nil {
+		return
+	}
+
+	if err = c.prepareChatIdentifiers(&request); err != nil {
+		return
+	}
+
+	if err = c.validateModelEndpoint(request.Model, urlSuffix); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -101,7 +140,7 @@ func (c *Client) CreateChatCompletionStream(
 		return nil, err
 	}
 
-	resp, err := sendRequestStream[ChatCompletionStreamResponse](c, req)
+	resp, err := sendRequestStream[ChatCompletionStreamResponse](c, req, opts...)
 	if err != nil {
 		return
 	}
@@ -110,3 +149,76 @@ func (c *Client) CreateChatCompletionStream(
 	}
 	return
 }
+
+// StreamChatCollectToolCalls streams a chat completion to the end and returns the fully
+// assembled tool calls together with the text content of the first choice.
+// Tool call fragments are merged by their index, so arguments split across chunks are
+// concatenated in the order they were received.
+func (c *Client) StreamChatCollectToolCalls(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (toolCalls []ToolCall, content string, err error) {
+	stream, err := c.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return
+	}
+	defer stream.Close()
+
+	var builder strings.Builder
+	positions := make(map[int]int)
+	for {
+		response, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		if recvErr != nil {
+			err = recvErr
+			return
+		}
+
+		for _, choice := range response.Choices {
+			if choice.Index != 0 {
+				continue
+			}
+			builder.WriteString(choice.Delta.Content)
+			for _, delta := range choice.Delta.ToolCalls {
+				toolCalls = mergeToolCallDelta(toolCalls, positions, delta)
+			}
+		}
+	}
+
+	content = builder.String()
+	return
+}
+
+// mergeToolCallDelta folds a streamed tool call fragment into calls. Fragments without an
+// index start a new call when they carry an ID and extend the latest call otherwise.
+func mergeToolCallDelta(calls []ToolCall, positions map[int]int, delta ToolCall) []ToolCall {
+	pos, ok := -1, false
+	if delta.Index != nil {
+		pos, ok = positions[*delta.Index]
+	} else if delta.ID == "" && len(calls) > 0 {
+		pos, ok = len(calls)-1, true
+	}
+
+	if !ok {
+		if delta.Index != nil {
+			positions[*delta.Index] = len(calls)
+		}
+		delta.Index = nil
+		return append(calls, delta)
+	}
+
+	call := &calls[pos]
+	if delta.ID != "" {
+		call.ID = delta.ID
+	}
+	if delta.Type != "" {
+		call.Type = delta.Type
+	}
+	if delta.Function.Name != "" {
+		call.Function.Name += delta.Function.Name
+	}
+	call.Function.Arguments += delta.Function.Arguments
+	return calls
+}
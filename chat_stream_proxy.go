@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ProxyChatCompletionStream streams a chat completion and forwards every chunk unchanged
+// to w as a server-sent event, flushing after each, and ends with "data: [DONE]" once
+// the upstream stream has. Pass the context of the incoming request so a client which
+// disconnects also cancels the upstream request.
+//
+// Errors creating the stream are returned before anything is written, so the caller
+// can still reply with an error status. Once the event stream has started, a failure
+// is returned without the final [DONE]; a stream the API ended without [DONE] fails
+// with io.ErrUnexpectedEOF.
+func (c *Client) ProxyChatCompletionStream(
+	ctx context.Context,
+	request ChatCompletionRequest,
+	w http.ResponseWriter,
+	opts ...StreamOption,
+) error {
+	stream, err := c.CreateChatCompletionStream(ctx, request, opts...)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		data, recvErr := stream.RecvRaw()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		if recvErr != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return recvErr
+		}
+		if err = writeEvent(w, flusher, data); err != nil {
+			return err
+		}
+	}
+	if !stream.Done() {
+		return io.ErrUnexpectedEOF
+	}
+	return writeEvent(w, flusher, []byte("[DONE]"))
+}
+
+// writeEvent writes data as one server-sent event and flushes it when w supports it.
+func writeEvent(w io.Writer, flusher http.Flusher, data []byte) error {
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return nil
+}
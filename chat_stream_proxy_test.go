@@ -0,0 +1,112 @@
+package openai_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestProxyChatCompletionStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	chunk1 := `{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hel"}}],"x_extra":1}`
+	chunk2 := `{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"lo"}}]}`
+	done := true
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n: keep-alive\n\ndata: %s\n\n", chunk1, chunk2)
+		if done {
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}
+	})
+
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	}
+	rec := httptest.NewRecorder()
+	err := client.ProxyChatCompletionStream(context.Background(), request, rec)
+	checks.NoError(t, err, "ProxyChatCompletionStream error")
+	if rec.Header().Get("Content-Type") != "text/event-stream" || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+	want := "data: " + chunk1 + "\n\ndata: " + chunk2 + "\n\ndata: [DONE]\n\n"
+	if rec.Body.String() != want || !rec.Flushed {
+		t.Fatalf("expected the chunks to be forwarded unchanged, got %q", rec.Body.String())
+	}
+
+	done = false
+	rec = httptest.NewRecorder()
+	err = client.ProxyChatCompletionStream(context.Background(), request, rec)
+	checks.ErrorIs(t, err, io.ErrUnexpectedEOF, "expected a truncated stream to fail")
+	if strings.Contains(rec.Body.String(), "[DONE]") {
+		t.Fatalf("a truncated stream should not be terminated with [DONE]")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec = httptest.NewRecorder()
+	err = client.ProxyChatCompletionStream(cancelled, request, rec)
+	checks.ErrorIs(t, err, context.Canceled, "expected the stream to fail before starting")
+	if rec.Header().Get("Content-Type") != "" || rec.Body.Len() != 0 {
+		t.Fatalf("nothing should be written when the stream cannot start")
+	}
+}
+
+func TestProxyChatCompletionStreamClientDisconnect(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	upstreamDone := make(chan struct{})
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		defer close(upstreamDone)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hel"}}]}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	proxyErr := make(chan error, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyErr <- client.ProxyChatCompletionStream(r.Context(), openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		}, w)
+	}))
+	defer proxy.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxy.URL, nil)
+	checks.NoError(t, err, "NewRequest error")
+	resp, err := http.DefaultClient.Do(req)
+	checks.NoError(t, err, "proxy request error")
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	checks.NoError(t, err, "ReadString error")
+	if !strings.HasPrefix(line, "data: ") {
+		t.Fatalf("expected the first chunk from the proxy, got %q", line)
+	}
+	cancel()
+	resp.Body.Close()
+
+	select {
+	case err = <-proxyErr:
+		checks.ErrorIs(t, err, context.Canceled, "expected the proxy to stop on disconnect")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the proxy did not stop after the client disconnected")
+	}
+	select {
+	case <-upstreamDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the upstream request was not canceled")
+	}
+}
@@ -1,6 +1,7 @@
 package openai_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,7 +9,9 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/sashabaranov/go-openai/internal/test/checks"
@@ -132,6 +135,73 @@ func TestCreateChatCompletionStream(t *testing.T) {
 	}
 }
 
+func TestCreateChatCompletionStreamCapture(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	//nolint:lll
+	raw := "data: {\"id\":\"1\",\"object\":\"completion\",\"model\":\"gpt-3.5-turbo\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n"
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, err := w.Write([]byte(raw))
+		checks.NoError(t, err, "Write error")
+	})
+
+	var captured bytes.Buffer
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model: openai.GPT3Dot5Turbo,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: "Hello!"},
+		},
+	}, openai.WithStreamCapture(&captured))
+	checks.NoError(t, err, "CreateChatCompletionStream returned error")
+
+	response, err := stream.Recv()
+	checks.NoError(t, err, "stream.Recv() failed")
+	if response.Choices[0].Delta.Content != "hi" {
+		t.Fatalf("unexpected delta: %+v", response)
+	}
+	_, err = stream.Recv()
+	checks.ErrorIs(t, err, io.EOF, "stream.Recv() did not return EOF")
+	stream.Close()
+
+	if captured.String() != raw {
+		t.Fatalf("expected the raw stream to be captured, got %q", captured.String())
+	}
+}
+
+func TestCreateChatCompletionStreamIdleTimeout(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	release := make(chan struct{})
+	defer close(release)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model: openai.GPT3Dot5Turbo,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: "Hello!"},
+		},
+	}, openai.WithStreamIdleTimeout(50*time.Millisecond))
+	checks.NoError(t, err, "CreateChatCompletionStream returned error")
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	checks.NoError(t, err, "stream.Recv() failed")
+	time.Sleep(100 * time.Millisecond) // a slow consumer must not trip the idle timer
+	_, err = stream.Recv()
+	checks.ErrorIs(t, err, openai.ErrStreamIdleTimeout, "expected the hung stream to time out")
+}
+
 func TestCreateChatCompletionStreamError(t *testing.T) {
 	client, server, teardown := setupOpenAITestServer()
 	defer teardown()
@@ -1021,3 +1091,130 @@ func compareChatStreamResponseChoices(c1, c2 openai.ChatCompletionStreamChoice)
 	}
 	return true
 }
+
+func TestStreamChatCollectToolCalls(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		chunks := []string{
+			`{"id":"1","choices":[{"index":0,"delta":{"role":"assistant","content":"Checking "}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"content":"weather"}}]}`,
+			//nolint:lll
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]}`,
+			//nolint:lll
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		}
+		dataBytes := []byte{}
+		for _, chunk := range chunks {
+			dataBytes = append(dataBytes, []byte("data: "+chunk+"\n\n")...)
+		}
+		dataBytes = append(dataBytes, []byte("data: [DONE]\n\n")...)
+
+		_, err := w.Write(dataBytes)
+		checks.NoError(t, err, "Write error")
+	})
+
+	toolCalls, content, err := client.StreamChatCollectToolCalls(context.Background(), openai.ChatCompletionRequest{
+		Model: openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: "What's the weather in Paris?"},
+		},
+	})
+	checks.NoError(t, err, "StreamChatCollectToolCalls error")
+	if content != "Checking weather" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+	if len(toolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(toolCalls))
+	}
+	if toolCalls[0].ID != "call_1" || toolCalls[0].Function.Name != "get_weather" ||
+		toolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected first tool call: %+v", toolCalls[0])
+	}
+	if toolCalls[1].ID != "call_2" || toolCalls[1].Function.Arguments != "{}" {
+		t.Fatalf("unexpected second tool call: %+v", toolCalls[1])
+	}
+}
+
+func TestNewChatCompletionStreamFromReader(t *testing.T) {
+	//nolint:lll
+	raw := "event: message\ndata: {\"id\":\"1\",\"object\":\"completion\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n"
+	stream := openai.NewChatCompletionStreamFromReader(strings.NewReader(raw))
+	defer stream.Close()
+
+	response, err := stream.Recv()
+	checks.NoError(t, err, "stream.Recv() failed")
+	if response.ID != "1" || response.Choices[0].Delta.Content != "hi" {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+	_, err = stream.Recv()
+	checks.ErrorIs(t, err, io.EOF, "stream.Recv() did not return EOF")
+}
+
+func TestCreateChatCompletionStreamToolCallDeltas(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []string{
+			`{"id":"1","choices":[{"index":0,"delta":{"role":"assistant","content":null,"refusal":null}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function",` +
+				`"function":{"name":"get_weather","arguments":""}}]}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function",` +
+				`"function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"refusal":"I can't share that."},"finish_reason":"tool_calls"}]}`,
+		}
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather and time?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var deltas []openai.ChatCompletionStreamChoiceDelta
+	for {
+		var response openai.ChatCompletionStreamResponse
+		response, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "stream.Recv() failed")
+		deltas = append(deltas, response.Choices[0].Delta)
+	}
+	if len(deltas) != 6 || deltas[0].Role != openai.ChatMessageRoleAssistant ||
+		deltas[5].Refusal != "I can't share that." {
+		t.Fatalf("unexpected deltas: %+v", deltas)
+	}
+
+	arguments := map[int]string{}
+	names := map[int]string{}
+	for _, delta := range deltas {
+		for _, call := range delta.ToolCalls {
+			if call.Index == nil {
+				t.Fatalf("expected every tool call delta to carry its index: %+v", call)
+			}
+			arguments[*call.Index] += call.Function.Arguments
+			if call.Function.Name != "" {
+				names[*call.Index] = call.Function.Name
+			}
+		}
+	}
+	if names[0] != "get_weather" || arguments[0] != `{"city":"Paris"}` || names[1] != "get_time" || arguments[1] != "{}" {
+		t.Fatalf("unexpected reassembled tool calls: %v %v", names, arguments)
+	}
+	if deltas[1].ToolCalls[0].ID != "call_1" || deltas[1].ToolCalls[0].Type != openai.ToolTypeFunction {
+		t.Fatalf("unexpected first tool call delta: %+v", deltas[1].ToolCalls[0])
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
 	"github.com/sashabaranov/go-openai/internal/test/checks"
 	"github.com/sashabaranov/go-openai/jsonschema"
 )
@@ -479,6 +480,12 @@ func TestChatCompletionsWithRateLimitHeaders(t *testing.T) {
 	if resetRequestsTime.Before(time.Now()) {
 		t.Errorf("unexpected reset requests: %v", resetRequestsTime)
 	}
+	if d := headers.ResetTokens.Duration(); d != 6*time.Minute {
+		t.Errorf("expected reset tokens duration 6m0s, got %s", d)
+	}
+	if d := openai.ResetTime("").Duration(); d != 0 {
+		t.Errorf("expected a missing reset header to be zero, got %s", d)
+	}
 
 	bs1, _ := json.Marshal(headers)
 	bs2, _ := json.Marshal(rateLimitHeaders)
@@ -685,6 +692,120 @@ func TestMultipartChatCompletions(t *testing.T) {
 	checks.NoError(t, err, "CreateAzureChatCompletion error")
 }
 
+func TestChatCompletionsInlineImagePayloadTooLarge(t *testing.T) {
+	config := openai.DefaultConfig("whatever")
+	config.BaseURL = "http://localhost/v1"
+	config.MaxInlineImagePayloadBytes = 64
+	client := openai.NewClientWithConfig(config)
+
+	dataURL := "data:image/png;base64," + strings.Repeat("A", 40)
+	req := openai.ChatCompletionRequest{
+		Model: openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role: openai.ChatMessageRoleUser,
+				MultiContent: []openai.ChatMessagePart{
+					{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: dataURL}},
+					{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: "https://x/y.png"}},
+					{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: dataURL}},
+				},
+			},
+		},
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrImagePayloadTooLarge, "CreateChatCompletion should reject oversized inline images")
+	var payloadErr *openai.ImagePayloadTooLargeError
+	if !errors.As(err, &payloadErr) {
+		t.Fatalf("expected ImagePayloadTooLargeError, got %T", err)
+	}
+	if payloadErr.MessageIndex != 0 || payloadErr.PartIndex != 2 {
+		t.Fatalf("unexpected offending block: messages[%d].content[%d]", payloadErr.MessageIndex, payloadErr.PartIndex)
+	}
+
+	_, err = client.CreateChatCompletionStream(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrImagePayloadTooLarge,
+		"CreateChatCompletionStream should reject oversized inline images")
+}
+
+func TestChatCompletionsWebSearchOptions(t *testing.T) {
+	data, err := json.Marshal(openai.ChatCompletionRequest{
+		Model: "gpt-4o-search-preview",
+		WebSearchOptions: &openai.WebSearchOptions{
+			SearchContextSize: openai.WebSearchContextSizeLow,
+			UserLocation:      &openai.WebSearchUserLocation{Country: "GB", City: "London"},
+		},
+	})
+	checks.NoError(t, err)
+	const expected = `{"model":"gpt-4o-search-preview","messages":null,"web_search_options":` +
+		`{"search_context_size":"low","user_location":{"type":"approximate","approximate":{"country":"GB","city":"London"}}}}`
+	if string(data) != expected {
+		t.Errorf("expected %v but was %v", expected, string(data))
+	}
+
+	config := openai.DefaultConfig("whatever")
+	config.BaseURL = "http://localhost/v1"
+	client := openai.NewClientWithConfig(config)
+	req := openai.ChatCompletionRequest{
+		Model:            openai.GPT4o,
+		WebSearchOptions: &openai.WebSearchOptions{SearchContextSize: "huge"},
+	}
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrWebSearchInvalidContextSize,
+		"CreateChatCompletion should reject unknown context sizes")
+	_, err = client.CreateChatCompletionStream(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrWebSearchInvalidContextSize,
+		"CreateChatCompletionStream should reject unknown context sizes")
+}
+
+func TestChatCompletionsDuplicateToolName(t *testing.T) {
+	config := openai.DefaultConfig("whatever")
+	config.BaseURL = "http://localhost/v1"
+	client := openai.NewClientWithConfig(config)
+	req := openai.ChatCompletionRequest{
+		Model: openai.GPT4o,
+		Tools: []openai.Tool{
+			{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "get_weather"}},
+			{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "get_time"}},
+			{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "get_weather"}},
+		},
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrDuplicateToolName, "CreateChatCompletion should reject duplicate tools")
+	if !strings.Contains(err.Error(), "get_weather") {
+		t.Fatalf("expected the error to name the duplicate tool, got %v", err)
+	}
+	_, err = client.CreateChatCompletionStream(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrDuplicateToolName, "CreateChatCompletionStream should reject duplicate tools")
+}
+
+func TestChatCompletionsEmptyChoices(t *testing.T) {
+	_, err := openai.ChatCompletionResponse{}.FirstChoice()
+	checks.ErrorIs(t, err, openai.ErrNoChoices, "FirstChoice should report missing choices")
+
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"id":"chatcmpl-1","object":"chat.completion","choices":[]}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	req := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	}
+	_, err = openai.NewClientWithConfig(config).CreateChatCompletion(context.Background(), req)
+	checks.NoError(t, err, "empty choices should be accepted by default")
+
+	config.ErrorOnEmptyChoices = true
+	_, err = openai.NewClientWithConfig(config).CreateChatCompletion(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrNoChoices, "expected empty choices to be reported")
+}
+
 func TestMultipartChatMessageSerialization(t *testing.T) {
 	jsonText := `[{"role":"system","content":"system-message"},` +
 		`{"role":"user","content":[{"type":"text","text":"nice-text"},` +
@@ -947,3 +1068,102 @@ func TestFinishReason(t *testing.T) {
 		}
 	}
 }
+
+func TestChatCompletionRequestEmptySlices(t *testing.T) {
+	request := openai.ChatCompletionRequest{Model: openai.GPT4oMini}
+	data, err := json.Marshal(request)
+	checks.NoError(t, err, "Marshal error")
+	for _, field := range []string{`"stop"`, `"functions"`, `"tools"`} {
+		if strings.Contains(string(data), field) {
+			t.Fatalf("expected nil %s to be omitted: %s", field, data)
+		}
+	}
+
+	request.Stop = []string{}
+	request.Tools = []openai.Tool{}
+	data, err = json.Marshal(request)
+	checks.NoError(t, err, "Marshal error")
+	if !strings.Contains(string(data), `"stop":[]`) || !strings.Contains(string(data), `"tools":[]`) ||
+		strings.Contains(string(data), `"functions"`) {
+		t.Fatalf("expected empty slices to be sent as []: %s", data)
+	}
+
+	request.Stop = []string{"\n"}
+	data, err = json.Marshal(request)
+	checks.NoError(t, err, "Marshal error")
+	var decoded openai.ChatCompletionRequest
+	checks.NoError(t, json.Unmarshal(data, &decoded), "Unmarshal error")
+	if len(decoded.Stop) != 1 || decoded.Stop[0] != "\n" || decoded.Model != openai.GPT4oMini {
+		t.Fatalf("unexpected round trip: %+v", decoded)
+	}
+}
+
+func TestUsagePromptCaching(t *testing.T) {
+	var usage openai.Usage
+	if usage.CachedTokenRatio() != 0 || usage.EffectivePromptTokens() != 0 {
+		t.Fatalf("expected zero usage to report no caching")
+	}
+
+	err := json.Unmarshal([]byte(`{"prompt_tokens":2000,"completion_tokens":10,"total_tokens":2010,
+		"prompt_tokens_details":{"cached_tokens":1500}}`), &usage)
+	checks.NoError(t, err, "Unmarshal error")
+	if usage.CachedTokens() != 1500 || usage.CachedTokenRatio() != 0.75 || usage.EffectivePromptTokens() != 500 {
+		t.Fatalf("unexpected caching stats: cached %d, ratio %v, effective %d",
+			usage.CachedTokens(), usage.CachedTokenRatio(), usage.EffectivePromptTokens())
+	}
+}
+
+func TestChatCompletionsSafetyIdentifier(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	var sent map[string]any
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		sent = nil
+		err := json.NewDecoder(r.Body).Decode(&sent)
+		checks.NoError(t, err, "Decode error")
+		fmt.Fprintln(w, `{"id":"chatcmpl-1","object":"chat.completion","choices":[{"index":0}]}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.DefaultSafetyIdentifier = "user_default"
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+	req := openai.ChatCompletionRequest{
+		Model:          openai.GPT4o,
+		Messages:       []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		PromptCacheKey: "support-bot-v2",
+	}
+
+	_, err := client.CreateChatCompletion(ctx, req)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if sent["safety_identifier"] != "user_default" || sent["prompt_cache_key"] != "support-bot-v2" {
+		t.Fatalf("expected the default safety identifier and the cache key, got %v", sent)
+	}
+
+	req.SafetyIdentifier = "user_abc123"
+	_, err = client.CreateChatCompletion(ctx, req)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if sent["safety_identifier"] != "user_abc123" {
+		t.Fatalf("expected the request safety identifier, got %v", sent["safety_identifier"])
+	}
+
+	req.SafetyIdentifier, req.User, req.PromptCacheKey = "", "legacy_user", ""
+	_, err = client.CreateChatCompletion(ctx, req)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if _, ok := sent["safety_identifier"]; ok || sent["user"] != "legacy_user" {
+		t.Fatalf("expected no default safety identifier next to user, got %v", sent)
+	}
+	if _, ok := sent["prompt_cache_key"]; ok {
+		t.Fatalf("expected an empty prompt cache key to be omitted, got %v", sent)
+	}
+
+	req.SafetyIdentifier = strings.Repeat("a", 65)
+	_, err = client.CreateChatCompletion(ctx, req)
+	checks.ErrorIs(t, err, openai.ErrSafetyIdentifierTooLong, "expected safety identifier length error")
+	req.SafetyIdentifier, req.PromptCacheKey = "", strings.Repeat("a", 65)
+	_, err = client.CreateChatCompletionStream(ctx, req)
+	checks.ErrorIs(t, err, openai.ErrPromptCacheKeyTooLong, "expected prompt cache key length error")
+}
@@ -0,0 +1,128 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrChatCompletionInvalid = errors.New("chat completion failed validation")
+	ErrEmptyContent          = errors.New("chat completion content is empty")
+)
+
+// ChatCompletionValidationError is returned by CreateChatCompletionWithValidation when
+// no response passed the validator. It wraps the validator error of the last attempt
+// and matches ErrChatCompletionInvalid.
+type ChatCompletionValidationError struct {
+	// Attempts is the number of completions requested.
+	Attempts int
+	Err      error
+}
+
+func (e *ChatCompletionValidationError) Error() string {
+	return fmt.Sprintf("%s after %d attempts: %v", ErrChatCompletionInvalid, e.Attempts, e.Err)
+}
+
+func (e *ChatCompletionValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ChatCompletionValidationError) Is(target error) bool {
+	return target == ErrChatCompletionInvalid
+}
+
+type chatValidationOptions struct {
+	nudge string
+}
+
+// ChatValidationOption configures CreateChatCompletionWithValidation.
+type ChatValidationOption func(*chatValidationOptions)
+
+// WithValidationNudge appends the rejected reply and a user message made of nudge and
+// the validator error to the conversation before every new attempt, instead of sending
+// the original request unchanged.
+func WithValidationNudge(nudge string) ChatValidationOption {
+	return func(o *chatValidationOptions) {
+		o.nudge = nudge
+	}
+}
+
+// ValidateJSONContent is a validator for CreateChatCompletionWithValidation which
+// requires the content of the first choice to be a non-empty JSON document.
+func ValidateJSONContent(response ChatCompletionResponse) error {
+	choice, err := response.FirstChoice()
+	if err != nil {
+		return err
+	}
+	content := strings.TrimSpace(choice.Message.Content)
+	if content == "" {
+		return ErrEmptyContent
+	}
+	if !json.Valid([]byte(content)) {
+		return fmt.Errorf("content is not valid JSON: %.64q", content)
+	}
+	return nil
+}
+
+// CreateChatCompletionWithValidation creates a chat completion and passes the response to
+// validate, requesting a new completion while validate fails, up to maxAttempts requests
+// in total. A maxAttempts below one sends a single request.
+//
+// Request errors are returned as they are, without further attempts. When every response
+// fails validation the last response is returned with a *ChatCompletionValidationError.
+func (c *Client) CreateChatCompletionWithValidation(
+	ctx context.Context,
+	request ChatCompletionRequest,
+	validate func(ChatCompletionResponse) error,
+	maxAttempts int,
+	opts ...ChatValidationOption,
+) (response ChatCompletionResponse, err error) {
+	var options chatValidationOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	messages := request.Messages
+	for attempt := 1; ; attempt++ {
+		request.Messages = messages
+		response, err = c.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return
+		}
+		invalid := validate(response)
+		if invalid == nil {
+			return
+		}
+		if attempt >= maxAttempts {
+			err = &ChatCompletionValidationError{Attempts: attempt, Err: invalid}
+			return
+		}
+
+		if options.nudge != "" {
+			messages = append(messages[:len(messages):len(messages)], rejectedReply(response), ChatCompletionMessage{
+				Role:    ChatMessageRoleUser,
+				Content: fmt.Sprintf("%s\n%v", options.nudge, invalid),
+			})
+		}
+	}
+}
+
+// rejectedReply returns the assistant message of the first choice of response, or an
+// empty assistant message when there is none.
+func rejectedReply(response ChatCompletionResponse) ChatCompletionMessage {
+	choice, err := response.FirstChoice()
+	if err != nil {
+		return ChatCompletionMessage{Role: ChatMessageRoleAssistant}
+	}
+	reply := choice.Message
+	if reply.Role == "" {
+		reply.Role = ChatMessageRoleAssistant
+	}
+	return reply
+}
@@ -0,0 +1,66 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateChatCompletionWithValidation(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	replies := []string{"Sure! {\"a\":", "", `{"a":1}`}
+	var requests []openai.ChatCompletionRequest
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var req openai.ChatCompletionRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		checks.NoError(t, err, "Decode error")
+		reply := replies[len(requests)%len(replies)]
+		requests = append(requests, req)
+		resBytes, _ := json.Marshal(openai.ChatCompletionResponse{
+			ID: fmt.Sprintf("chatcmpl-%d", len(requests)),
+			Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: reply},
+			}},
+		})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	ctx := context.Background()
+	req := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Reply in JSON."}},
+	}
+	response, err := client.CreateChatCompletionWithValidation(ctx, req, openai.ValidateJSONContent, 3,
+		openai.WithValidationNudge("Reply with valid JSON only."))
+	checks.NoError(t, err, "CreateChatCompletionWithValidation error")
+	if response.ID != "chatcmpl-3" || len(requests) != 3 {
+		t.Fatalf("expected the third response to pass, got %s after %d requests", response.ID, len(requests))
+	}
+	last := requests[2].Messages
+	if len(last) != 5 || last[1].Content != "Sure! {\"a\":" || last[4].Role != openai.ChatMessageRoleUser {
+		t.Fatalf("expected both rejected replies and nudges in the last request, got %+v", last)
+	}
+	if len(req.Messages) != 1 {
+		t.Fatalf("the caller's messages should not be modified, got %+v", req.Messages)
+	}
+
+	requests = nil
+	response, err = client.CreateChatCompletionWithValidation(ctx, req, openai.ValidateJSONContent, 2)
+	checks.ErrorIs(t, err, openai.ErrChatCompletionInvalid, "expected exhausted validation")
+	checks.ErrorIs(t, err, openai.ErrEmptyContent, "expected the last validator error to be wrapped")
+	var validationErr *openai.ChatCompletionValidationError
+	if !errors.As(err, &validationErr) || validationErr.Attempts != 2 || response.ID != "chatcmpl-2" {
+		t.Fatalf("expected the last response and 2 attempts, got %s and %v", response.ID, err)
+	}
+	if len(requests[1].Messages) != 1 {
+		t.Fatalf("expected the request to be resent unchanged without a nudge, got %+v", requests[1].Messages)
+	}
+}
@@ -2,23 +2,78 @@ package openai
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	utils "github.com/sashabaranov/go-openai/internal"
 )
 
+// ErrNotModified is returned for a 304 response to a conditional request, sent for
+// instance with WithIfNoneMatch. The response value keeps the headers only.
+var ErrNotModified = errors.New("resource not modified")
+
+// ErrUnexpectedObjectType is returned when the object field of a decoded response is
+// not the type the endpoint returns, for instance an unrelated body sent by a gateway
+// with a success status. Responses without an object field are not checked.
+var ErrUnexpectedObjectType = errors.New("unexpected object type in response")
+
+// ErrResponseTooLarge is returned when a response body is larger than
+// ClientConfig.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body exceeds the size limit")
+
+// objectTyped is implemented by responses whose object field has a known value.
+type objectTyped interface {
+	objectType() (object, expected string)
+}
+
+// checkObjectType checks the object field of v when v is objectTyped.
+func checkObjectType(v any) error {
+	typed, ok := v.(objectTyped)
+	if !ok {
+		return nil
+	}
+	if object, expected := typed.objectType(); object != "" && object != expected {
+		return fmt.Errorf("%w: expected %s, got %q", ErrUnexpectedObjectType, expected, object)
+	}
+	return nil
+}
+
 // Client is OpenAI GPT-3 API client.
+//
+// A Client is safe for concurrent use by multiple goroutines. It keeps a copy of its
+// configuration, including DefaultMetadata, so changing a ClientConfig after creating
+// a client has no effect on it. Requests are only read, so a request value and its
+// maps and slices may be shared between concurrent calls as long as none modifies them.
 type Client struct {
 	config ClientConfig
 
 	requestBuilder    utils.RequestBuilder
 	createFormBuilder func(io.Writer) utils.FormBuilder
+
+	// assistantVersionErr is returned by the assistants API calls of a client created
+	// with an invalid AssistantVersion.
+	assistantVersionErr error
+
+	*clientState
+}
+
+// clientState is the state a client shares with the clients derived from it by
+// WithAssistantVersion, so they warn once, share the models cache and know the
+// threads of the runs seen by either.
+type clientState struct {
+	fileIDsWarning sync.Once
+	modelsCache    modelsCache
+	runThreads     runThreadRegistry
 }
 
 type Response interface {
@@ -35,6 +90,19 @@ func (h *httpHeader) Header() http.Header {
 	return http.Header(*h)
 }
 
+const requestIDHeader = "X-Request-Id"
+
+// ETag returns the entity tag of the response, or "" when the server sent none.
+func (h *httpHeader) ETag() string {
+	return h.Header().Get("ETag")
+}
+
+// RequestID returns the x-request-id header of the response, the ID OpenAI support
+// asks for when investigating a request.
+func (h *httpHeader) RequestID() string {
+	return h.Header().Get(requestIDHeader)
+}
+
 func (h *httpHeader) GetRateLimitHeaders() RateLimitHeaders {
 	return newRateLimitHeaders(h.Header())
 }
@@ -52,14 +120,59 @@ func NewClient(authToken string) *Client {
 }
 
 // NewClientWithConfig creates new OpenAI API client for specified config.
+// An empty AssistantVersion defaults to v2. NewClientWithConfig cannot return an error,
+// so a client with any other value than v1 or v2 is still created and its assistants
+// API calls fail with ErrAssistantVersionInvalid before a request is sent; use
+// NewClientWithConfigE to have the version rejected here.
 func NewClientWithConfig(config ClientConfig) *Client {
+	if config.AssistantVersion == "" {
+		config.AssistantVersion = defaultAssistantVersion
+	}
+	if config.DefaultMetadata != nil {
+		metadata := make(map[string]any, len(config.DefaultMetadata))
+		for k, v := range config.DefaultMetadata {
+			metadata[k] = v
+		}
+		config.DefaultMetadata = metadata
+	}
 	return &Client{
 		config:         config,
 		requestBuilder: utils.NewRequestBuilder(),
 		createFormBuilder: func(body io.Writer) utils.FormBuilder {
 			return utils.NewFormBuilder(body)
 		},
+		assistantVersionErr: validateAssistantVersion(config.AssistantVersion),
+		clientState:         new(clientState),
+	}
+}
+
+// NewClientWithConfigE is NewClientWithConfig rejecting an invalid configuration: it
+// fails with ErrAssistantVersionInvalid when AssistantVersion is not empty, v1 or v2.
+func NewClientWithConfigE(config ClientConfig) (*Client, error) {
+	client := NewClientWithConfig(config)
+	if client.assistantVersionErr != nil {
+		return nil, client.assistantVersionErr
 	}
+	return client, nil
+}
+
+// WithAssistantVersion returns a client which sends version, v1 or v2, as the version of
+// the assistants API instead of the configured AssistantVersion. The new client shares
+// the configuration, HTTP client and request builder of c, as well as its models cache
+// and the threads of the runs it has seen, so CheckRunThreads works across both. Other
+// versions return ErrAssistantVersionInvalid.
+func (c *Client) WithAssistantVersion(version string) (*Client, error) {
+	if err := validateAssistantVersion(version); err != nil {
+		return nil, err
+	}
+	config := c.config
+	config.AssistantVersion = version
+	return &Client{
+		config:            config,
+		requestBuilder:    c.requestBuilder,
+		createFormBuilder: c.createFormBuilder,
+		clientState:       c.clientState,
+	}, nil
 }
 
 // NewOrgClient creates new OpenAI API client for specified Organization ID.
@@ -77,6 +190,13 @@ type requestOptions struct {
 	extraHeaders map[string]string
 	extraQuery   map[string]string
 	extraBody    map[string]any
+	// assistants is set for requests to the assistants API, which fail when the client
+	// was created with an invalid AssistantVersion.
+	assistants bool
+	// onBody receives the encoded body of the built request, see WithRawBody.
+	onBody func(body []byte)
+	// apiType overrides ClientConfig.APIType for the headers, see WithAPIType.
+	apiType APIType
 }
 
 type requestOption func(*requestOptions)
@@ -119,10 +239,21 @@ func withContentType(contentType string) requestOption {
 
 func withBetaAssistantVersion(version string) requestOption {
 	return func(args *requestOptions) {
+		args.assistants = true
 		args.header.Set("OpenAI-Beta", fmt.Sprintf("assistants=%s", version))
 	}
 }
 
+// withIdempotencyKey sets the Idempotency-Key header, which the retries of the request
+// reuse since they send the same *http.Request. An empty key sets nothing.
+func withIdempotencyKey(key string) requestOption {
+	return func(args *requestOptions) {
+		if key != "" {
+			args.header.Set("Idempotency-Key", key)
+		}
+	}
+}
+
 func (c *Client) newRequest(ctx context.Context, method, url string, setters ...requestOption) (*http.Request, error) {
 	// Default Options
 	args := &requestOptions{
@@ -132,6 +263,16 @@ func (c *Client) newRequest(ctx context.Context, method, url string, setters ...
 	for _, setter := range setters {
 		setter(args)
 	}
+	if args.assistants && c.assistantVersionErr != nil {
+		return nil, c.assistantVersionErr
+	}
+	apiType := c.config.APIType
+	if args.apiType != "" {
+		apiType = args.apiType
+		if (apiType == APITypeAzure || apiType == APITypeAzureAD) && c.config.APIVersion == "" {
+			return nil, ErrAzureAPIVersionRequired
+		}
+	}
 	req, err := c.requestBuilder.Build(ctx, &utils.Request{
 		Method:       method,
 		URL:          url,
@@ -144,10 +285,21 @@ func (c *Client) newRequest(ctx context.Context, method, url string, setters ...
 	if err != nil {
 		return nil, err
 	}
-	c.setCommonHeaders(req)
+	c.setAPITypeHeaders(req, apiType)
+	if args.onBody != nil {
+		args.onBody(requestBodyBytes(req))
+	}
 	return req, nil
 }
 
+// now returns the current time by ClientConfig.Now, or time.Now when it is unset.
+func (c *Client) now() time.Time {
+	if c.config.Now != nil {
+		return c.config.Now()
+	}
+	return time.Now()
+}
+
 func (c *Client) sendRequest(req *http.Request, v Response) error {
 	req.Header.Set("Accept", "application/json")
 
@@ -158,8 +310,19 @@ func (c *Client) sendRequest(req *http.Request, v Response) error {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	res, err := c.config.HTTPClient.Do(req)
+	res, attempts, err := c.doRequest(req)
 	if err != nil {
+		return retryFailure(err, attempts, nil)
+	}
+	storeRawHTTPResponse(req.Context(), res)
+	if err = gunzipResponse(res); err != nil {
+		res.Body.Close()
+		return err
+	}
+	if limit := c.config.MaxResponseBytes; limit > 0 {
+		res.Body = &maxBytesBody{ReadCloser: res.Body, remaining: int64(limit)}
+	}
+	if err = c.logRequest(req, res, false); err != nil {
 		return err
 	}
 
@@ -169,21 +332,64 @@ func (c *Client) sendRequest(req *http.Request, v Response) error {
 		v.SetHeader(res.Header)
 	}
 
+	if res.StatusCode == http.StatusNotModified {
+		return ErrNotModified
+	}
 	if isFailureStatusCode(res) {
-		return c.handleErrorResp(res)
+		return retryFailure(c.handleErrorResp(res), attempts, res)
 	}
 
-	return decodeResponse(res.Body, v)
-}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error, reading response body: %w", err)
+	}
 
-func (c *Client) sendRequestRaw(req *http.Request) (response RawResponse, err error) {
-	resp, err := c.config.HTTPClient.Do(req) //nolint:bodyclose // body should be closed by outer function
+	if apiErr := embeddedAPIError(res.Header, body); apiErr != nil {
+		apiErr.HTTPStatus = res.Status
+		apiErr.HTTPStatusCode = res.StatusCode
+		apiErr.RequestID = res.Header.Get(requestIDHeader)
+		return apiErr
+	}
+
+	if c.config.ResponseDecoder != nil && v != nil && !isTextResponse(v) {
+		err = c.config.ResponseDecoder(body, v)
+	} else {
+		err = decodeResponse(bytes.NewReader(body), v, c.config.UseJSONNumber)
+		if err == nil && c.config.UseJSONNumber {
+			err = decodeMessageMetadataNumbers(body, v)
+		}
+	}
 	if err != nil {
-		return
+		return err
 	}
+	return checkObjectType(v)
+}
 
-	if isFailureStatusCode(resp) {
-		err = c.handleErrorResp(resp)
+// maxBytesBody is a response body failing with ErrResponseTooLarge once more than
+// remaining bytes are read from it.
+type maxBytesBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// One byte past the limit is read to tell a body of exactly the limit from a
+	// larger one.
+	n, err := io.LimitReader(b.ReadCloser, b.remaining+1).Read(p)
+	if int64(n) > b.remaining {
+		n, b.remaining = int(b.remaining), -1
+		return n, ErrResponseTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (c *Client) sendRequestRaw(req *http.Request) (response RawResponse, err error) {
+	resp, err := c.sendRequestBody(req) //nolint:bodyclose // body should be closed by outer function
+	if err != nil {
 		return
 	}
 
@@ -192,32 +398,94 @@ func (c *Client) sendRequestRaw(req *http.Request) (response RawResponse, err er
 	return
 }
 
-func sendRequestStream[T streamable](client *Client, req *http.Request) (*streamReader[T], error) {
-	req.Header.Set("Content-Type", "application/json")
+// sendRequestBody sends req through the same hooks as sendRequest and returns the
+// response of a successful status, whose body the caller must read and close. The
+// body is not buffered for the RequestLogger then; the body of an error status is
+// read into the returned error and closed.
+func (c *Client) sendRequestBody(req *http.Request) (*http.Response, error) {
+	resp, attempts, err := c.doRequest(req)
+	if err != nil {
+		return nil, retryFailure(err, attempts, nil)
+	}
+	storeRawHTTPResponse(req.Context(), resp)
+	if err = c.logRequest(req, resp, !isFailureStatusCode(resp)); err != nil {
+		return nil, err
+	}
+
+	if isFailureStatusCode(resp) {
+		defer resp.Body.Close()
+		return nil, retryFailure(c.handleErrorResp(resp), attempts, resp)
+	}
+	return resp, nil
+}
+
+type rawHTTPResponseKey struct{}
+
+// WithRawHTTPResponse returns a context which makes API calls made with it store their
+// final *http.Response in target, for inspecting trailers, TLS state or the protocol.
+// It is stored for error statuses too. The body of a decoded response has already been
+// read and closed by the time the call returns, so it must not be used; for raw calls
+// such as file downloads and for streams it is the body returned to the caller, which
+// is read by the stream and closed by its Close method.
+func WithRawHTTPResponse(ctx context.Context, target **http.Response) context.Context {
+	return context.WithValue(ctx, rawHTTPResponseKey{}, target)
+}
+
+func storeRawHTTPResponse(ctx context.Context, res *http.Response) {
+	if target, ok := ctx.Value(rawHTTPResponseKey{}).(**http.Response); ok && target != nil {
+		*target = res
+	}
+}
+
+func sendRequestStream[T streamable](
+	client *Client,
+	req *http.Request,
+	opts ...StreamOption,
+) (*streamReader[T], error) {
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
 
-	resp, err := client.config.HTTPClient.Do(req) //nolint:bodyclose // body is closed in stream.Close()
+	resp, attempts, err := client.doRequest(req) //nolint:bodyclose // body is closed in stream.Close()
 	if err != nil {
+		return new(streamReader[T]), retryFailure(err, attempts, nil)
+	}
+	storeRawHTTPResponse(req.Context(), resp)
+	if err = client.logRequest(req, resp, !isFailureStatusCode(resp)); err != nil {
 		return new(streamReader[T]), err
 	}
 	if isFailureStatusCode(resp) {
-		return new(streamReader[T]), client.handleErrorResp(resp)
+		return new(streamReader[T]), retryFailure(client.handleErrorResp(resp), attempts, resp)
 	}
-	return &streamReader[T]{
+
+	stream := &streamReader[T]{
 		emptyMessagesLimit: client.config.EmptyMessagesLimit,
 		reader:             bufio.NewReader(resp.Body),
 		response:           resp,
 		errAccumulator:     utils.NewErrorAccumulator(),
 		unmarshaler:        &utils.JSONUnmarshaler{},
 		httpHeader:         httpHeader(resp.Header),
-	}, nil
+	}
+	options := newStreamOptions(opts)
+	if options.responseDecoder && client.config.ResponseDecoder != nil {
+		stream.unmarshaler = responseDecoderUnmarshaler(client.config.ResponseDecoder)
+	}
+	stream.applyOptions(options)
+	return stream, nil
 }
 
 func (c *Client) setCommonHeaders(req *http.Request) {
+	c.setAPITypeHeaders(req, c.config.APIType)
+}
+
+// setAPITypeHeaders sets the credentials and organization headers the way apiType
+// expects them.
+func (c *Client) setAPITypeHeaders(req *http.Request, apiType APIType) {
 	// https://learn.microsoft.com/en-us/azure/cognitive-services/openai/reference#authentication
-	switch c.config.APIType {
+	switch apiType {
 	case APITypeAzure, APITypeCloudflareAzure:
 		// Azure API Key authentication
 		req.Header.Set(AzureAPIKeyHeader, c.config.authToken)
@@ -241,7 +509,20 @@ func isFailureStatusCode(resp *http.Response) bool {
 	return resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest
 }
 
-func decodeResponse(body io.Reader, v any) error {
+// ResponseDecoder decodes the JSON body data of a response into v, like
+// json.Unmarshal, see ClientConfig.ResponseDecoder.
+type ResponseDecoder func(data []byte, v any) error
+
+// responseDecoderUnmarshaler decodes stream events with a ResponseDecoder.
+type responseDecoderUnmarshaler ResponseDecoder
+
+func (d responseDecoderUnmarshaler) Unmarshal(data []byte, v any) error {
+	return d(data, v)
+}
+
+// decodeResponse decodes body into v. With useNumber, numbers in untyped fields
+// are decoded as json.Number instead of float64.
+func decodeResponse(body io.Reader, v any, useNumber bool) error {
 	if v == nil {
 		return nil
 	}
@@ -252,7 +533,21 @@ func decodeResponse(body io.Reader, v any) error {
 	case *audioTextResponse:
 		return decodeString(body, &o.Text)
 	default:
-		return json.NewDecoder(body).Decode(v)
+		decoder := json.NewDecoder(body)
+		if useNumber {
+			decoder.UseNumber()
+		}
+		return decoder.Decode(v)
+	}
+}
+
+// isTextResponse reports whether v receives a plain text body rather than JSON.
+func isTextResponse(v any) bool {
+	switch v.(type) {
+	case *string, *audioTextResponse:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -267,6 +562,8 @@ func decodeString(body io.Reader, output *string) error {
 
 type fullURLOptions struct {
 	model string
+	// apiType overrides ClientConfig.APIType, see WithAPIType.
+	apiType APIType
 }
 
 type fullURLOption func(*fullURLOptions)
@@ -295,7 +592,11 @@ func (c *Client) fullURL(suffix string, setters ...fullURLOption) string {
 		setter(&args)
 	}
 
-	if c.config.APIType == APITypeAzure || c.config.APIType == APITypeAzureAD {
+	apiType := c.config.APIType
+	if args.apiType != "" {
+		apiType = args.apiType
+	}
+	if apiType == APITypeAzure || apiType == APITypeAzureAD {
 		baseURL = c.baseURLWithAzureDeployment(baseURL, suffix, args.model)
 	}
 
@@ -340,6 +641,7 @@ func (c *Client) handleErrorResp(resp *http.Response) error {
 			HTTPStatusCode: resp.StatusCode,
 			Err:            err,
 			Body:           body,
+			RequestID:      resp.Header.Get(requestIDHeader),
 		}
 		if errRes.Error != nil {
 			reqErr.Err = errRes.Error
@@ -349,9 +651,37 @@ func (c *Client) handleErrorResp(resp *http.Response) error {
 
 	errRes.Error.HTTPStatus = resp.Status
 	errRes.Error.HTTPStatusCode = resp.StatusCode
+	errRes.Error.RequestID = resp.Header.Get(requestIDHeader)
 	return errRes.Error
 }
 
+// embeddedAPIError extracts an error object that some proxies and gateways return
+// with a 2xx status code in a JSON body. API resources carry an object field, so their
+// own error attribute, such as the error of a failed fine-tuning job, is never treated
+// as a failure. Only the error and object fields are decoded, the others are skipped.
+func embeddedAPIError(header http.Header, body []byte) *APIError {
+	if !isJSONContentType(header.Get("Content-Type")) {
+		return nil
+	}
+	var envelope struct {
+		Error  *APIError `json:"error"`
+		Object string    `json:"object"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Object != "" {
+		return nil
+	}
+	if envelope.Error == nil || envelope.Error.Message == "" {
+		return nil
+	}
+	return envelope.Error
+}
+
+// isJSONContentType reports whether contentType is application/json or a +json type.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
 func containsSubstr(s []string, e string) bool {
 	for _, v := range s {
 		if strings.Contains(e, v) {
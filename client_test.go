@@ -3,11 +3,14 @@ package openai //nolint:testpackage // testing private field
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 
 	utils "github.com/sashabaranov/go-openai/internal"
@@ -114,7 +117,7 @@ func TestDecodeResponse(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := decodeResponse(tc.body, tc.value)
+			err := decodeResponse(tc.body, tc.value, false)
 			if tc.hasError {
 				checks.HasError(t, err, "Unexpected nil error")
 				return
@@ -388,7 +391,7 @@ func TestClientReturnsRequestBuilderErrors(t *testing.T) {
 			return nil, client.DeleteAssistantFile(ctx, "", "")
 		}},
 		{"CreateMessage", func() (any, error) {
-			return client.CreateMessage(ctx, "", MessageRequest{})
+			return client.CreateMessage(ctx, "", MessageRequest{Role: "user", Content: "Hello"})
 		}},
 		{"ListMessage", func() (any, error) {
 			return client.ListMessage(ctx, "", nil, nil, nil, nil, nil)
@@ -399,6 +402,9 @@ func TestClientReturnsRequestBuilderErrors(t *testing.T) {
 		{"ModifyMessage", func() (any, error) {
 			return client.ModifyMessage(ctx, "", "", nil)
 		}},
+		{"ModifyMessageWithMetadata", func() (any, error) {
+			return client.ModifyMessageWithMetadata(ctx, "", "", nil)
+		}},
 		{"DeleteMessage", func() (any, error) {
 			return client.DeleteMessage(ctx, "", "")
 		}},
@@ -587,3 +593,396 @@ func TestClient_baseURLWithAzureDeployment(t *testing.T) {
 		})
 	}
 }
+
+type staticResponseDoer struct {
+	statusCode int
+	body       string
+	// contentType defaults to application/json.
+	contentType string
+	requests    int
+	lastURL     string
+}
+
+func (d *staticResponseDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests++
+	d.lastURL = req.URL.String()
+	contentType := d.contentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return &http.Response{
+		Status:     http.StatusText(d.statusCode),
+		StatusCode: d.statusCode,
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(bytes.NewBufferString(d.body)),
+	}, nil
+}
+
+func TestSendRequestEmbeddedError(t *testing.T) {
+	testCases := []struct {
+		name        string
+		body        string
+		contentType string
+		wantErr     bool
+	}{
+		{
+			name:    "gateway error with 200",
+			body:    `{"error":{"message":"upstream unavailable","type":"server_error"}}`,
+			wantErr: true,
+		},
+		{
+			name: "resource with an error attribute",
+			body: `{"id":"ftjob-abc","object":"fine_tuning.job","error":{"message":"training failed"}}`,
+		},
+		{
+			name: "error field which is not an object",
+			body: `{"error":"nope"}`,
+		},
+		{
+			name: "regular response",
+			body: `{"data":[]}`,
+		},
+		{
+			name:        "error body which is not JSON",
+			body:        `{"error":{"message":"upstream unavailable"}}`,
+			contentType: "text/plain",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultConfig(test.GetTestToken())
+			config.HTTPClient = &staticResponseDoer{statusCode: http.StatusOK, body: tc.body, contentType: tc.contentType}
+			client := NewClientWithConfig(config)
+
+			_, err := client.ListModels(context.Background())
+			if !tc.wantErr {
+				checks.NoError(t, err, "unexpected embedded error")
+				return
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if apiErr.HTTPStatusCode != http.StatusOK || apiErr.Message != "upstream unavailable" {
+				t.Fatalf("unexpected APIError: %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestWithRawHTTPResponse(t *testing.T) {
+	config := DefaultConfig(test.GetTestToken())
+	config.HTTPClient = &staticResponseDoer{statusCode: http.StatusOK, body: `{"data":[]}`}
+	client := NewClientWithConfig(config)
+
+	var raw *http.Response
+	_, err := client.ListModels(WithRawHTTPResponse(context.Background(), &raw))
+	checks.NoError(t, err, "ListModels error")
+	if raw == nil || raw.StatusCode != http.StatusOK {
+		t.Fatalf("expected the raw response to be stored, got %+v", raw)
+	}
+
+	config.HTTPClient = &staticResponseDoer{statusCode: http.StatusBadRequest, body: `{"error":{"message":"bad"}}`}
+	client = NewClientWithConfig(config)
+	raw = nil
+	_, err = client.ListModels(WithRawHTTPResponse(context.Background(), &raw))
+	checks.HasError(t, err, "expected error status")
+	if raw == nil || raw.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected the raw error response to be stored, got %+v", raw)
+	}
+
+	config.HTTPClient = &staticResponseDoer{
+		statusCode: http.StatusOK,
+		body:       "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n",
+	}
+	client = NewClientWithConfig(config)
+	raw = nil
+	stream, err := client.CreateChatCompletionStream(WithRawHTTPResponse(context.Background(), &raw),
+		ChatCompletionRequest{Model: GPT4oMini, Messages: []ChatCompletionMessage{{Role: ChatMessageRoleUser}}})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	if raw == nil || raw.StatusCode != http.StatusOK {
+		t.Fatalf("expected the raw stream response to be stored, got %+v", raw)
+	}
+	response, err := stream.Recv()
+	checks.NoError(t, err, "expected the stream body to stay readable")
+	if response.Choices[0].Delta.Content != "hi" {
+		t.Fatalf("unexpected stream content %q", response.Choices[0].Delta.Content)
+	}
+}
+
+// closeRecordingBody records whether the response body was closed.
+type closeRecordingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeRecordingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type closeRecordingDoer struct {
+	statusCode int
+	body       *closeRecordingBody
+}
+
+func (d *closeRecordingDoer) Do(*http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:        http.StatusText(d.statusCode),
+		StatusCode:    d.statusCode,
+		Header:        http.Header{"Content-Type": []string{"application/jsonl"}},
+		Body:          d.body,
+		ContentLength: -1,
+	}, nil
+}
+
+func TestDownloadFileContentHooks(t *testing.T) {
+	var logs []RequestLog
+	config := DefaultConfig(test.GetTestToken())
+	config.RequestLogger = func(entry RequestLog) {
+		logs = append(logs, entry)
+	}
+	doer := &closeRecordingDoer{statusCode: http.StatusOK, body: &closeRecordingBody{Reader: strings.NewReader("{}\n")}}
+	config.HTTPClient = doer
+	client := NewClientWithConfig(config)
+
+	var raw *http.Response
+	content, err := client.DownloadFileContent(WithRawHTTPResponse(context.Background(), &raw), "file_abc123")
+	checks.NoError(t, err, "DownloadFileContent error")
+	if raw == nil || len(logs) != 1 || logs[0].ResponseBody != nil {
+		t.Fatalf("expected the download to be stored and logged without its body, got %+v and %+v", raw, logs)
+	}
+	if data, _ := io.ReadAll(content.Reader); string(data) != "{}\n" || doer.body.closed {
+		t.Fatalf("expected the body to be left to the caller, got %q", data)
+	}
+	content.Reader.Close()
+
+	doer.statusCode = http.StatusNotFound
+	doer.body = &closeRecordingBody{Reader: strings.NewReader(`{"error":{"message":"No such File object"}}`)}
+	raw = nil
+	_, err = client.DownloadFileContent(WithRawHTTPResponse(context.Background(), &raw), "file_abc123")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+	if raw == nil || len(logs) != 2 || string(logs[1].ResponseBody) != `{"error":{"message":"No such File object"}}` {
+		t.Fatalf("expected the error response to be stored and logged, got %+v and %+v", raw, logs)
+	}
+
+	// Without a RequestLogger the body of the error response is only read by the client.
+	config.RequestLogger = nil
+	doer.body = &closeRecordingBody{Reader: strings.NewReader(`{"error":{"message":"No such File object"}}`)}
+	_, err = NewClientWithConfig(config).DownloadFileContent(context.Background(), "file_abc123")
+	checks.HasError(t, err, "expected error status")
+	if !doer.body.closed {
+		t.Fatal("expected the body of an error response to be closed")
+	}
+}
+
+func TestWithAPIType(t *testing.T) {
+	doer := &staticResponseDoer{statusCode: http.StatusOK, body: `{"id":"msg_abc123","object":"thread.message"}`}
+	config := DefaultConfig("token")
+	config.BaseURL = "https://gateway.example.com"
+	config.HTTPClient = doer
+	client := NewClientWithConfig(config)
+
+	ctx := context.Background()
+	_, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123", WithAPIType(APITypeAzure))
+	checks.ErrorIs(t, err, ErrAzureAPIVersionRequired, "expected Azure override without APIVersion to fail")
+	if doer.requests != 0 {
+		t.Fatalf("expected no request to be sent, got %d", doer.requests)
+	}
+
+	config.APIVersion = "2024-06-01"
+	client = NewClientWithConfig(config)
+	_, err = client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123", WithAPIType(APITypeAzure))
+	checks.NoError(t, err, "RetrieveMessage error")
+	expected := "https://gateway.example.com/openai/threads/thread_abc123/messages/msg_abc123?api-version=2024-06-01"
+	if doer.lastURL != expected {
+		t.Fatalf("expected %s, got %s", expected, doer.lastURL)
+	}
+
+	err = client.PostRaw(ctx, "/vector_stores", map[string]any{"name": "docs"}, nil, WithAPIType(APITypeAzure))
+	checks.NoError(t, err, "PostRaw error")
+	if doer.lastURL != "https://gateway.example.com/openai/vector_stores?api-version=2024-06-01" {
+		t.Fatalf("unexpected Azure URL %s", doer.lastURL)
+	}
+
+	_, urlOption, extra := applyRequestOptions("/threads", []RequestOption{WithAPIType(APITypeAzure)})
+	req, err := client.newRequest(ctx, http.MethodPost, client.fullURL("/threads", urlOption), extra)
+	checks.NoError(t, err, "newRequest error")
+	if req.Header.Get(AzureAPIKeyHeader) != "token" || req.Header.Get("Authorization") != "" {
+		t.Fatalf("expected Azure authentication headers, got %v", req.Header)
+	}
+
+	// Calls without the option keep the configured API type.
+	_, err = client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "RetrieveMessage error")
+	if doer.lastURL != "https://gateway.example.com/threads/thread_abc123/messages/msg_abc123?api-version=2024-06-01" {
+		t.Fatalf("expected the configured API type without the option, got %s", doer.lastURL)
+	}
+	req, err = client.newRequest(ctx, http.MethodPost, client.fullURL(chatCompletionsSuffix))
+	checks.NoError(t, err, "newRequest error")
+	if req.Header.Get("Authorization") != "Bearer token" || req.Header.Get(AzureAPIKeyHeader) != "" {
+		t.Fatalf("expected bearer authentication without the option, got %v", req.Header)
+	}
+}
+
+func TestMessagesURL(t *testing.T) {
+	azureConfig := DefaultAzureConfig("token", "https://example.openai.azure.com/")
+	azureConfig.APIVersion = "2024-05-01-preview"
+	testCases := []struct {
+		name       string
+		config     ClientConfig
+		createURL  string
+		listPrefix string
+	}{
+		{
+			name:       "OpenAI",
+			config:     DefaultConfig("token"),
+			createURL:  "https://api.openai.com/v1/threads/thread_abc123/messages",
+			listPrefix: "https://api.openai.com/v1/threads/thread_abc123/messages?",
+		},
+		{
+			name:       "Azure",
+			config:     azureConfig,
+			createURL:  "https://example.openai.azure.com/openai/threads/thread_abc123/messages?api-version=2024-05-01-preview",
+			listPrefix: "https://example.openai.azure.com/openai/threads/thread_abc123/messages?",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doer := &staticResponseDoer{statusCode: http.StatusOK, body: `{"id":"msg_abc123","object":"thread.message"}`}
+			tc.config.HTTPClient = doer
+			client := NewClientWithConfig(tc.config)
+
+			_, err := client.CreateMessage(context.Background(), "thread_abc123",
+				MessageRequest{Role: "user", Content: "Hello"})
+			checks.NoError(t, err, "CreateMessage error")
+			if doer.lastURL != tc.createURL {
+				t.Fatalf("unexpected CreateMessage URL %s", doer.lastURL)
+			}
+
+			doer.body = `{"object":"list","data":[]}`
+			limit := 5
+			_, err = client.ListMessage(context.Background(), "thread_abc123", &limit, nil, nil, nil, nil)
+			checks.NoError(t, err, "ListMessage error")
+			if !strings.HasPrefix(doer.lastURL, tc.listPrefix) {
+				t.Fatalf("unexpected ListMessage URL %s", doer.lastURL)
+			}
+			query, err := url.ParseQuery(strings.TrimPrefix(doer.lastURL, tc.listPrefix))
+			checks.NoError(t, err, "ParseQuery error")
+			if query.Get("limit") != "5" || query.Get("api-version") != tc.config.APIVersion {
+				t.Fatalf("unexpected ListMessage query %v", query)
+			}
+		})
+	}
+}
+
+func TestAssistantVersion(t *testing.T) {
+	doer := &staticResponseDoer{statusCode: http.StatusOK, body: `{"id":"asst_abc123","object":"assistant"}`}
+	config := DefaultAzureConfig("token", "https://example.openai.azure.com")
+	config.HTTPClient = doer
+	client := NewClientWithConfig(config)
+	if client.config.AssistantVersion != "v2" {
+		t.Fatalf("expected an empty assistant version to default to v2, got %q", client.config.AssistantVersion)
+	}
+
+	config = DefaultConfig("token")
+	config.HTTPClient = doer
+	for _, version := range []string{"", "v1", "v2"} {
+		config.AssistantVersion = version
+		_, err := NewClientWithConfigE(config)
+		checks.NoError(t, err, "NewClientWithConfigE error")
+	}
+	config.AssistantVersion = "v3"
+	rejected, err := NewClientWithConfigE(config)
+	checks.ErrorIs(t, err, ErrAssistantVersionInvalid, "expected NewClientWithConfigE to reject v3")
+	if rejected != nil {
+		t.Fatal("expected no client for an invalid assistant version")
+	}
+
+	client = NewClientWithConfig(config)
+	_, err = client.RetrieveAssistant(context.Background(), "asst_abc123")
+	checks.ErrorIs(t, err, ErrAssistantVersionInvalid, "expected an invalid assistant version to be rejected")
+	if doer.requests != 0 {
+		t.Fatalf("expected no request to be sent, got %d", doer.requests)
+	}
+	_, err = client.ListModels(context.Background())
+	checks.NoError(t, err, "calls outside the assistants API should not check the version")
+
+	_, err = client.WithAssistantVersion("beta")
+	checks.ErrorIs(t, err, ErrAssistantVersionInvalid, "expected WithAssistantVersion to reject beta")
+	v1, err := client.WithAssistantVersion("v1")
+	checks.NoError(t, err, "WithAssistantVersion error")
+	req, err := v1.newRequest(context.Background(), http.MethodGet, v1.fullURL("/assistants"),
+		withBetaAssistantVersion(v1.config.AssistantVersion))
+	checks.NoError(t, err, "newRequest error")
+	if req.Header.Get("OpenAI-Beta") != "assistants=v1" {
+		t.Fatalf("expected the v1 beta header, got %q", req.Header.Get("OpenAI-Beta"))
+	}
+	if client.config.AssistantVersion != "v3" {
+		t.Fatalf("expected the original client to keep its version, got %q", client.config.AssistantVersion)
+	}
+
+	config.AssistantVersion = "v2"
+	config.CheckRunThreads = true
+	client = NewClientWithConfig(config)
+	v1, err = client.WithAssistantVersion("v1")
+	checks.NoError(t, err, "WithAssistantVersion error")
+	client.rememberRunThread(Run{ID: "run_abc123", ThreadID: "thread_abc123"})
+	checks.ErrorIs(t, v1.checkRunThread("thread_other", "run_abc123"), ErrRunThreadMismatch,
+		"expected the derived client to know the runs seen by its parent")
+	if v1.clientState != client.clientState {
+		t.Fatal("expected the derived client to share the state of its parent")
+	}
+}
+
+func TestUseJSONNumber(t *testing.T) {
+	body := `{"id":"asst_abc123","object":"assistant","metadata":{"external_id":9007199254740993}}`
+	config := DefaultConfig(test.GetTestToken())
+	config.HTTPClient = &staticResponseDoer{statusCode: http.StatusOK, body: body}
+
+	assistant, err := NewClientWithConfig(config).RetrieveAssistant(context.Background(), "asst_abc123")
+	checks.NoError(t, err, "RetrieveAssistant error")
+	if _, ok := assistant.Metadata["external_id"].(float64); !ok {
+		t.Fatalf("expected float64 by default, got %T", assistant.Metadata["external_id"])
+	}
+
+	config.UseJSONNumber = true
+	assistant, err = NewClientWithConfig(config).RetrieveAssistant(context.Background(), "asst_abc123")
+	checks.NoError(t, err, "RetrieveAssistant error")
+	number, ok := assistant.Metadata["external_id"].(json.Number)
+	if !ok || number.String() != "9007199254740993" {
+		t.Fatalf("expected a precise json.Number, got %#v", assistant.Metadata["external_id"])
+	}
+	config.HTTPClient = &staticResponseDoer{
+		statusCode: http.StatusOK,
+		body: `{"id":"msg_abc123","object":"thread.message","content":"hi",` +
+			`"metadata":{"external_id":9007199254740993}}`,
+	}
+	message, err := NewClientWithConfig(config).RetrieveMessage(context.Background(), "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "RetrieveMessage error")
+	number, ok = message.Metadata["external_id"].(json.Number)
+	if !ok || number.String() != "9007199254740993" || len(message.Content) != 1 {
+		t.Fatalf("expected a precise json.Number in the message metadata, got %#v", message.Metadata["external_id"])
+	}
+
+	config.HTTPClient = &staticResponseDoer{
+		statusCode: http.StatusOK,
+		body:       `{"object":"list","data":[{"id":"msg_abc123","metadata":{"external_id":9007199254740993}}]}`,
+	}
+	messages, err := NewClientWithConfig(config).ListMessagesWithParams(context.Background(), "thread_abc123",
+		ListMessagesParams{})
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	if len(messages.Messages) != 1 {
+		t.Fatalf("expected a message, got %+v", messages)
+	}
+	metadata := messages.Messages[0].Metadata
+	if number, ok = metadata["external_id"].(json.Number); !ok || number.String() != "9007199254740993" {
+		t.Fatalf("expected a precise json.Number in the listed metadata, got %#v", metadata["external_id"])
+	}
+}
@@ -11,6 +11,28 @@ type Usage struct {
 	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details"`
 }
 
+// CachedTokens returns the number of prompt tokens served from the prompt cache.
+func (u Usage) CachedTokens() int {
+	if u.PromptTokensDetails == nil {
+		return 0
+	}
+	return u.PromptTokensDetails.CachedTokens
+}
+
+// CachedTokenRatio returns the fraction of prompt tokens served from the prompt cache,
+// or 0 when the usage has no prompt tokens.
+func (u Usage) CachedTokenRatio() float64 {
+	if u.PromptTokens == 0 {
+		return 0
+	}
+	return float64(u.CachedTokens()) / float64(u.PromptTokens)
+}
+
+// EffectivePromptTokens returns the prompt tokens which were not served from the cache.
+func (u Usage) EffectivePromptTokens() int {
+	return u.PromptTokens - u.CachedTokens()
+}
+
 // CompletionTokensDetails Breakdown of tokens used in a completion.
 type CompletionTokensDetails struct {
 	AudioTokens              int `json:"audio_tokens"`
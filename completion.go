@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 )
 
@@ -209,7 +210,7 @@ type CompletionRequest struct {
 	N               int               `json:"n,omitempty"`
 	PresencePenalty float32           `json:"presence_penalty,omitempty"`
 	Seed            *int              `json:"seed,omitempty"`
-	Stop            []string          `json:"stop,omitempty"`
+	Stop            []string          `json:"stop,omitempty"` // Omitted when nil, sent as [] when empty.
 	Stream          bool              `json:"stream,omitempty"`
 	Suffix          string            `json:"suffix,omitempty"`
 	Temperature     float32           `json:"temperature,omitempty"`
@@ -219,6 +220,19 @@ type CompletionRequest struct {
 	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 }
 
+// MarshalJSON omits a nil Stop but sends a non-nil empty Stop as [].
+func (r CompletionRequest) MarshalJSON() ([]byte, error) {
+	type alias CompletionRequest
+	request := struct {
+		alias
+		Stop *[]string `json:"stop,omitempty"`
+	}{alias: alias(r)}
+	if r.Stop != nil {
+		request.Stop = &r.Stop
+	}
+	return json.Marshal(request)
+}
+
 // CompletionChoice represents one of possible completions.
 type CompletionChoice struct {
 	Text         string        `json:"text"`
@@ -300,3 +300,19 @@ func TestCompletionWithGPT4oModels(t *testing.T) {
 		})
 	}
 }
+
+func TestCompletionRequestEmptyStop(t *testing.T) {
+	request := openai.CompletionRequest{Model: "gpt-3.5-turbo-instruct", Prompt: "Hello"}
+	data, err := json.Marshal(request)
+	checks.NoError(t, err, "Marshal error")
+	if strings.Contains(string(data), `"stop"`) {
+		t.Fatalf("expected nil stop to be omitted: %s", data)
+	}
+
+	request.Stop = []string{}
+	data, err = json.Marshal(request)
+	checks.NoError(t, err, "Marshal error")
+	if !strings.Contains(string(data), `"stop":[]`) || !strings.Contains(string(data), `"prompt":"Hello"`) {
+		t.Fatalf("expected empty stop to be sent as []: %s", data)
+	}
+}
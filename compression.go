@@ -0,0 +1,79 @@
+package openai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const gzipEncoding = "gzip"
+
+// gzipRequestBody compresses the body of req with gzip when it is larger than threshold
+// bytes and sets the Content-Encoding header. A non-positive threshold, or a body which
+// cannot be replayed, leaves the request unchanged. Retries resend the compressed body.
+func gzipRequestBody(req *http.Request, threshold int) error {
+	if threshold <= 0 {
+		return nil
+	}
+	body := requestBodyBytes(req)
+	if len(body) <= threshold {
+		return nil
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	data := compressed.Bytes()
+
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Encoding", gzipEncoding)
+	return nil
+}
+
+// gunzipResponse decompresses a gzip encoded response body which the transport left
+// compressed, as it does when Accept-Encoding is set by the caller or for an HTTPClient
+// which is not an *http.Client.
+func gunzipResponse(res *http.Response) error {
+	if res.Uncompressed || !strings.EqualFold(res.Header.Get("Content-Encoding"), gzipEncoding) {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(res.Body)
+	if errors.Is(err, io.EOF) {
+		// An empty body, such as the one of a 304, is left as is.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error, decompressing response body: %w", err)
+	}
+	res.Body = &gzipResponseBody{Reader: reader, body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+	return nil
+}
+
+type gzipResponseBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b *gzipResponseBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
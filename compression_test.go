@@ -0,0 +1,46 @@
+package openai
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// seedText returns about size bytes of text made of random words, closer to prose than
+// a repeated sentence, which would compress far better.
+func seedText(size int) string {
+	words := strings.Fields("the report shows revenue growth in every region while costs " +
+		"rose faster than expected during the second quarter of the year and the team " +
+		"proposes new pricing for enterprise customers with a review planned next month")
+	random := rand.New(rand.NewSource(1))
+	var text strings.Builder
+	for text.Len() < size {
+		text.WriteString(words[random.Intn(len(words))])
+		text.WriteByte(' ')
+	}
+	return text.String()
+}
+
+func BenchmarkGzipMessageRequest(b *testing.B) {
+	for _, size := range []int{4 << 10, 64 << 10, 1 << 20} {
+		body, err := messageRequestBody{NewMessageRequest("user", seedText(size))}.MarshalJSON()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("%dKB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			var compressed int64
+			for i := 0; i < b.N; i++ {
+				req, _ := http.NewRequest(http.MethodPost, "https://example.com", bytes.NewReader(body))
+				if err = gzipRequestBody(req, 1); err != nil {
+					b.Fatal(err)
+				}
+				compressed = req.ContentLength
+			}
+			b.ReportMetric(float64(compressed)/float64(len(body)), "ratio")
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package openai
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strings"
+	"time"
 )
 
 const (
@@ -27,12 +32,38 @@ const (
 
 const AzureAPIKeyHeader = "api-key"
 
-const defaultAssistantVersion = "v2" // upgrade to v2 to support vector store
+const azureHostSuffix = ".openai.azure.com"
+
+var (
+	ErrAzureAPIVersionRequired = errors.New("azure base url requires an APIVersion to be configured")
+	ErrAssistantVersionInvalid = errors.New("assistant version must be v1 or v2")
+)
+
+const (
+	defaultAssistantVersion = "v2" // upgrade to v2 to support vector store
+	assistantVersionV1      = "v1"
+)
+
+// validateAssistantVersion checks a version sent in the OpenAI-Beta assistants header.
+func validateAssistantVersion(version string) error {
+	switch version {
+	case assistantVersionV1, defaultAssistantVersion:
+		return nil
+	default:
+		return fmt.Errorf("%w, got %q", ErrAssistantVersionInvalid, version)
+	}
+}
 
 type HTTPDoer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Logger receives diagnostic messages from the client, such as deprecation warnings.
+// *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
 // ClientConfig is a configuration of a client.
 type ClientConfig struct {
 	authToken string
@@ -44,8 +75,93 @@ type ClientConfig struct {
 	AssistantVersion     string
 	AzureModelMapperFunc func(model string) string // replace model to azure deployment name func
 	HTTPClient           HTTPDoer
+	Retry                RetryConfig // automatic retries of transient failures, disabled by default
 
 	EmptyMessagesLimit uint
+	// ErrorOnEmptyChoices makes CreateChatCompletion return ErrNoChoices when the
+	// response comes back without any choices.
+	ErrorOnEmptyChoices bool
+	// ValidateModelEndpoints rejects requests sending a known model to an endpoint
+	// which cannot serve it, see ValidateModelForEndpoint.
+	ValidateModelEndpoints bool
+	// ModelsCacheTTL caches the result of ListModels for the given duration.
+	// Zero disables the cache.
+	ModelsCacheTTL time.Duration
+	// CheckRunThreads remembers the thread of every run the client sees and makes run
+	// methods fail with ErrRunThreadMismatch, before sending the request, when a known
+	// run is used with another thread. Runs the client has not seen are not checked.
+	CheckRunThreads bool
+	// UseJSONNumber decodes numbers in untyped response fields, such as metadata
+	// values, as json.Number instead of float64, so large integers keep their
+	// precision. Code reading those fields must then handle json.Number values.
+	// Streamed responses are not affected.
+	UseJSONNumber bool
+	// ResponseDecoder is optional. When set, it decodes the JSON response bodies in
+	// place of json.Unmarshal, and UseJSONNumber is ignored, so bodies can be
+	// transformed or measured before they are decoded. Error bodies are still decoded
+	// by the client. Streams use it only with WithStreamResponseDecoder.
+	ResponseDecoder ResponseDecoder
+
+	// Now is optional. When set, it replaces time.Now as the clock of the client, used
+	// for the expiry of the models cache and the windows of ListMessagesWithin, so
+	// tests can freeze or advance time. Context deadlines and poll intervals still
+	// follow the system clock.
+	Now func() time.Time
+
+	// Logger is optional. When set, it receives one-time deprecation warnings.
+	Logger Logger
+	// RequestLogger is optional. When set, it is called with the request and response
+	// bodies of every API call, for debugging. Credential headers are redacted and
+	// streamed responses are passed without their body.
+	RequestLogger RequestLogger
+
+	// FileIDsAttachmentTool is the tool attached to files converted from the deprecated
+	// MessageRequest.FileIds field. Defaults to file_search when empty.
+	FileIDsAttachmentTool AssistantToolType
+	// LegacyFileIDs sends MessageRequest.FileIds unchanged instead of converting them
+	// to attachments on v2, for servers which still accept the v1 field. Clients
+	// using AssistantVersion v1 always send the file ids unchanged.
+	LegacyFileIDs bool
+
+	// ValidateMessageContent checks the content parts of created messages with
+	// MessageContent.Validate before sending them.
+	ValidateMessageContent bool
+	// ValidateMetadata checks the metadata of created and modified messages against
+	// the API limits before sending them: at most 16 keys, keys of at most 64
+	// characters and string values of at most 512 characters.
+	ValidateMetadata bool
+
+	// DefaultMetadata is merged into the metadata of every created thread, message and run.
+	// Keys set on the request take precedence over the defaults.
+	DefaultMetadata map[string]any
+
+	// AllowUnknownAudioValues sends speech voices and audio response formats or timestamp
+	// granularities which are not one of the constants of this package, for values the
+	// API added after this release. By default they fail with ErrAudioValueUnknown.
+	AllowUnknownAudioValues bool
+
+	// DefaultSafetyIdentifier is sent as the safety_identifier of chat completion requests
+	// which set neither SafetyIdentifier nor User.
+	DefaultSafetyIdentifier string
+
+	// MaxResponseBytes limits the size of the response bodies the client reads, after
+	// decompression, so a faulty server cannot exhaust memory, for instance with a huge
+	// messages list. Larger responses fail with ErrResponseTooLarge. Streams and raw
+	// responses, such as file contents, are not limited. Zero disables the limit.
+	MaxResponseBytes int
+
+	// GzipMessageRequestsOver compresses the body of CreateMessage requests larger than
+	// this many bytes with gzip, sent with Content-Encoding: gzip, for large messages
+	// over slow links. Only set it for servers which accept compressed requests. Zero
+	// disables compression. Gzip encoded responses are always decompressed.
+	GzipMessageRequestsOver int
+
+	// MaxInlineImagePayloadBytes limits the total size of base64 data URL images
+	// embedded in a single chat request. Zero disables the check.
+	MaxInlineImagePayloadBytes int
+	// MaxImageUploadBytes limits the size of the images CreateMessageWithImage
+	// uploads. Zero disables the check.
+	MaxImageUploadBytes int
 }
 
 func DefaultConfig(authToken string) ClientConfig {
@@ -59,6 +175,9 @@ func DefaultConfig(authToken string) ClientConfig {
 		HTTPClient: &http.Client{},
 
 		EmptyMessagesLimit: defaultEmptyMessagesLimit,
+
+		MaxInlineImagePayloadBytes: DefaultMaxInlineImagePayloadBytes,
+		MaxImageUploadBytes:        DefaultMaxImageUploadBytes,
 	}
 }
 
@@ -76,6 +195,9 @@ func DefaultAzureConfig(apiKey, baseURL string) ClientConfig {
 		HTTPClient: &http.Client{},
 
 		EmptyMessagesLimit: defaultEmptyMessagesLimit,
+
+		MaxInlineImagePayloadBytes: DefaultMaxInlineImagePayloadBytes,
+		MaxImageUploadBytes:        DefaultMaxImageUploadBytes,
 	}
 }
 
@@ -93,7 +215,38 @@ func DefaultAnthropicConfig(apiKey, baseURL string) ClientConfig {
 		HTTPClient: &http.Client{},
 
 		EmptyMessagesLimit: defaultEmptyMessagesLimit,
+
+		MaxInlineImagePayloadBytes: DefaultMaxInlineImagePayloadBytes,
+		MaxImageUploadBytes:        DefaultMaxImageUploadBytes,
+	}
+}
+
+// WithInferredAPIType returns a copy of the config with APIType inferred from BaseURL.
+// A host matching *.openai.azure.com switches the default APITypeOpenAI to APITypeAzure
+// and installs the default deployment mapping unless one is set. Any other APIType is
+// treated as an explicit choice and left untouched.
+// An Azure base URL without an APIVersion returns ErrAzureAPIVersionRequired.
+func (c ClientConfig) WithInferredAPIType() (ClientConfig, error) {
+	if c.APIType != "" && c.APIType != APITypeOpenAI {
+		return c, nil
+	}
+
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return c, err
+	}
+	if !strings.HasSuffix(strings.ToLower(u.Hostname()), azureHostSuffix) {
+		return c, nil
+	}
+	if c.APIVersion == "" {
+		return c, ErrAzureAPIVersionRequired
+	}
+
+	c.APIType = APITypeAzure
+	if c.AzureModelMapperFunc == nil {
+		c.AzureModelMapperFunc = DefaultAzureConfig("", "").AzureModelMapperFunc
 	}
+	return c, nil
 }
 
 func (ClientConfig) String() string {
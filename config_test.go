@@ -1,6 +1,7 @@
 package openai_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/sashabaranov/go-openai"
@@ -121,3 +122,37 @@ func TestGetAzureDeploymentByModel_NoMapper(t *testing.T) {
 		t.Errorf("GetAzureDeploymentByModel(%q) = %q; want %q", model, got, model)
 	}
 }
+
+func TestWithInferredAPIType(t *testing.T) {
+	config := openai.DefaultConfig("token")
+	config.BaseURL = "https://my-resource.openai.azure.com"
+	config.APIVersion = "2024-02-01"
+	inferred, err := config.WithInferredAPIType()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inferred.APIType != openai.APITypeAzure {
+		t.Fatalf("expected azure api type, got %s", inferred.APIType)
+	}
+	if got := inferred.GetAzureDeploymentByModel("gpt-3.5-turbo"); got != "gpt-35-turbo" {
+		t.Fatalf("expected the default deployment mapping, got %s", got)
+	}
+
+	config.APIVersion = ""
+	if _, err = config.WithInferredAPIType(); !errors.Is(err, openai.ErrAzureAPIVersionRequired) {
+		t.Fatalf("expected ErrAzureAPIVersionRequired, got %v", err)
+	}
+
+	config = openai.DefaultConfig("token")
+	inferred, err = config.WithInferredAPIType()
+	if err != nil || inferred.APIType != openai.APITypeOpenAI {
+		t.Fatalf("expected the openai api type to be kept, got %s, %v", inferred.APIType, err)
+	}
+
+	config.BaseURL = "https://my-resource.openai.azure.com"
+	config.APIType = openai.APITypeAzureAD
+	inferred, _ = config.WithInferredAPIType()
+	if inferred.APIType != openai.APITypeAzureAD {
+		t.Fatalf("expected an explicit api type to be kept, got %s", inferred.APIType)
+	}
+}
@@ -0,0 +1,186 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+var ErrEmbeddingIndexModelMismatch = errors.New("embedding index was built with another model")
+
+// SearchResult is a document of an EmbeddingIndex matching a search query.
+type SearchResult struct {
+	ID    string  `json:"id"`
+	Text  string  `json:"text"`
+	Score float32 `json:"score"`
+}
+
+type embeddingIndexEntry struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+type embeddingIndexFile struct {
+	Model   EmbeddingModel        `json:"model"`
+	Entries []embeddingIndexEntry `json:"entries"`
+}
+
+// EmbeddingIndex is an in-memory store of texts searchable by cosine similarity of
+// their embeddings, meant for prototyping retrieval without a vector database.
+// Texts are embedded lazily, in batches, on the next Flush or Search.
+// An EmbeddingIndex is safe for concurrent use.
+type EmbeddingIndex struct {
+	client *Client
+	model  EmbeddingModel
+
+	mu      sync.Mutex
+	entries []embeddingIndexEntry
+	byID    map[string]int
+}
+
+// NewEmbeddingIndex returns an empty index embedding texts with model.
+func NewEmbeddingIndex(client *Client, model EmbeddingModel) *EmbeddingIndex {
+	return &EmbeddingIndex{
+		client: client,
+		model:  model,
+		byID:   make(map[string]int),
+	}
+}
+
+// Add queues text for embedding under id, replacing any document with the same id.
+func (x *EmbeddingIndex) Add(id, text string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	entry := embeddingIndexEntry{ID: id, Text: text}
+	if i, ok := x.byID[id]; ok {
+		x.entries[i] = entry
+		return
+	}
+	x.byID[id] = len(x.entries)
+	x.entries = append(x.entries, entry)
+}
+
+// Len returns the number of documents in the index, embedded or not.
+func (x *EmbeddingIndex) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.entries)
+}
+
+// Flush embeds the texts added since the last flush.
+func (x *EmbeddingIndex) Flush(ctx context.Context) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.flush(ctx)
+}
+
+func (x *EmbeddingIndex) flush(ctx context.Context) error {
+	var pending []int
+	for i, entry := range x.entries {
+		if entry.Embedding == nil {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	inputs := make([]string, len(pending))
+	for i, entry := range pending {
+		inputs[i] = x.entries[entry].Text
+	}
+	res, err := x.client.CreateEmbeddingsBatched(ctx, EmbeddingRequestStrings{Input: inputs, Model: x.model})
+	if err != nil {
+		return err
+	}
+	for i, entry := range pending {
+		x.entries[entry].Embedding = res.Data[i].Embedding
+	}
+	return nil
+}
+
+// Search embeds pending texts and query and returns the k documents most similar to
+// query, best first. Documents with the same score are ordered by id.
+func (x *EmbeddingIndex) Search(ctx context.Context, query string, k int) (results []SearchResult, err error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if err = x.flush(ctx); err != nil {
+		return
+	}
+	res, err := x.client.CreateEmbeddings(ctx, EmbeddingRequestStrings{Input: []string{query}, Model: x.model})
+	if err != nil {
+		return
+	}
+	if len(res.Data) == 0 {
+		err = fmt.Errorf("%w: query", ErrEmbeddingMissing)
+		return
+	}
+	queryEmbedding := res.Data[0]
+
+	results = make([]SearchResult, 0, len(x.entries))
+	for _, entry := range x.entries {
+		var score float32
+		score, err = queryEmbedding.CosineSimilarity(&Embedding{Embedding: entry.Embedding})
+		if err != nil {
+			return nil, fmt.Errorf("document %s: %w", entry.ID, err)
+		}
+		results = append(results, SearchResult{ID: entry.ID, Text: entry.Text, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if k >= 0 && k < len(results) {
+		results = results[:k]
+	}
+	return
+}
+
+// Save writes the index to a JSON file at path. Texts not embedded yet are saved too
+// and embedded after Load.
+func (x *EmbeddingIndex) Save(path string) error {
+	x.mu.Lock()
+	data, err := json.Marshal(embeddingIndexFile{Model: x.model, Entries: x.entries})
+	x.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Load replaces the documents of the index with those saved at path. It fails with
+// ErrEmbeddingIndexModelMismatch when the file was saved with another model, since
+// their embeddings could not be compared with queries embedded by this index.
+func (x *EmbeddingIndex) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file embeddingIndexFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.Model != x.model {
+		return fmt.Errorf("%w: %s, not %s", ErrEmbeddingIndexModelMismatch, file.Model, x.model)
+	}
+
+	byID := make(map[string]int, len(file.Entries))
+	for i, entry := range file.Entries {
+		byID[entry.ID] = i
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries, x.byID = file.Entries, byID
+	return nil
+}
@@ -0,0 +1,81 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestEmbeddingIndex(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	vectors := map[string][]float32{
+		"cats purr":       {1, 0, 0},
+		"dogs bark":       {0, 1, 0},
+		"kittens meow":    {0.9, 0.1, 0},
+		"stocks fell":     {0, 0, 1},
+		"tell me of cats": {1, 0.05, 0},
+	}
+	var batches [][]string
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var req openai.EmbeddingRequestStrings
+		err := json.NewDecoder(r.Body).Decode(&req)
+		checks.NoError(t, err, "Decode error")
+		batches = append(batches, req.Input)
+
+		res := openai.EmbeddingResponse{Model: req.Model}
+		for i, input := range req.Input {
+			res.Data = append(res.Data, openai.Embedding{Embedding: vectors[input], Index: i})
+		}
+		resBytes, _ := json.Marshal(res)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	ctx := context.Background()
+	index := openai.NewEmbeddingIndex(client, openai.SmallEmbedding3)
+	index.Add("a", "cats purr")
+	index.Add("b", "dogs bark")
+	index.Add("c", "stocks fell")
+	index.Add("c", "kittens meow")
+	if len(batches) != 0 || index.Len() != 3 {
+		t.Fatalf("expected Add to queue 3 documents without requests, got %d requests", len(batches))
+	}
+
+	results, err := index.Search(ctx, "tell me of cats", 2)
+	checks.NoError(t, err, "Search error")
+	if len(results) != 2 || results[0].ID != "a" || results[1].ID != "c" || results[1].Text != "kittens meow" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if len(batches) != 2 || len(batches[0]) != 3 {
+		t.Fatalf("expected the documents to be embedded in one batch, got %v", batches)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	index.Add("d", "stocks fell")
+	checks.NoError(t, index.Save(path), "Save error")
+
+	loaded := openai.NewEmbeddingIndex(client, openai.SmallEmbedding3)
+	checks.NoError(t, loaded.Load(path), "Load error")
+	batches = nil
+	results, err = loaded.Search(ctx, "tell me of cats", -1)
+	checks.NoError(t, err, "Search error")
+	if len(results) != 4 || results[3].ID != "d" {
+		t.Fatalf("unexpected results after Load: %+v", results)
+	}
+	if len(batches) != 2 || len(batches[0]) != 1 || batches[0][0] != "stocks fell" {
+		t.Fatalf("expected only the unsaved embedding to be computed after Load, got %v", batches)
+	}
+
+	err = openai.NewEmbeddingIndex(client, openai.LargeEmbedding3).Load(path)
+	if !errors.Is(err, openai.ErrEmbeddingIndexModelMismatch) {
+		t.Fatalf("expected ErrEmbeddingIndexModelMismatch, got %v", err)
+	}
+}
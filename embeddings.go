@@ -68,6 +68,27 @@ func (e *Embedding) DotProduct(other *Embedding) (float32, error) {
 	return dotProduct, nil
 }
 
+// CosineSimilarity calculates the cosine of the angle between the embedding vector
+// and another embedding vector, in [-1, 1]. Both vectors must have the same length;
+// otherwise, an ErrVectorLengthMismatch is returned. Zero vectors have a similarity of 0.
+func (e *Embedding) CosineSimilarity(other *Embedding) (float32, error) {
+	if len(e.Embedding) != len(other.Embedding) {
+		return 0, ErrVectorLengthMismatch
+	}
+
+	var dot, norm, otherNorm float64
+	for i := range e.Embedding {
+		dot += float64(e.Embedding[i]) * float64(other.Embedding[i])
+		norm += float64(e.Embedding[i]) * float64(e.Embedding[i])
+		otherNorm += float64(other.Embedding[i]) * float64(other.Embedding[i])
+	}
+	if norm == 0 || otherNorm == 0 {
+		return 0, nil
+	}
+
+	return float32(dot / math.Sqrt(norm*otherNorm)), nil
+}
+
 // EmbeddingResponse is the response from a Create embeddings request.
 type EmbeddingResponse struct {
 	Object string         `json:"object"`
@@ -78,6 +99,16 @@ type EmbeddingResponse struct {
 	httpHeader
 }
 
+// ByIndex returns the embeddings keyed by the index of their input, for
+// reassembling results when inputs were split across concurrent requests.
+func (r EmbeddingResponse) ByIndex() map[int]Embedding {
+	embeddings := make(map[int]Embedding, len(r.Data))
+	for _, embedding := range r.Data {
+		embeddings[embedding.Index] = embedding
+	}
+	return embeddings
+}
+
 type base64String string
 
 func (b base64String) Decode() ([]float32, error) {
@@ -130,10 +161,11 @@ func (r *EmbeddingResponseBase64) ToEmbeddingResponse() (EmbeddingResponse, erro
 	}
 
 	return EmbeddingResponse{
-		Object: r.Object,
-		Model:  r.Model,
-		Data:   data,
-		Usage:  r.Usage,
+		Object:     r.Object,
+		Model:      r.Model,
+		Data:       data,
+		Usage:      r.Usage,
+		httpHeader: r.httpHeader,
 	}, nil
 }
 
@@ -244,10 +276,14 @@ func (c *Client) CreateEmbeddings(
 	conv EmbeddingRequestConverter,
 ) (res EmbeddingResponse, err error) {
 	baseReq := conv.Convert()
+	if err = c.validateModelEndpoint(string(baseReq.Model), embeddingsSuffix); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
-		c.fullURL("/embeddings", withModel(string(baseReq.Model))),
+		c.fullURL(embeddingsSuffix, withModel(string(baseReq.Model))),
 		withBody(baseReq),
 		withExtraHeaders(baseReq.ExtraHeaders),
 		withExtraQuery(baseReq.ExtraQuery),
@@ -0,0 +1,200 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	// defaultEmbeddingBatchSize is the maximum number of inputs of one embeddings request.
+	defaultEmbeddingBatchSize = 2048
+	// defaultEmbeddingMaxTokens is the input limit of the OpenAI embedding models.
+	defaultEmbeddingMaxTokens = 8191
+)
+
+var (
+	ErrEmbeddingInputTooLong = errors.New("embedding input exceeds the token limit")
+	ErrEmbeddingMissing      = errors.New("embeddings response is missing an embedding")
+)
+
+// EmbeddingInputTooLongError names the input which exceeds the token limit of
+// CreateEmbeddingsBatched.
+type EmbeddingInputTooLongError struct {
+	// Index is the index of the offending input within the request.
+	Index int
+	// Tokens is the token count of the input.
+	Tokens int
+	// Limit is the maximum number of tokens per input.
+	Limit int
+}
+
+func (e *EmbeddingInputTooLongError) Error() string {
+	return fmt.Sprintf("%s: input[%d] has %d tokens, the limit is %d",
+		ErrEmbeddingInputTooLong, e.Index, e.Tokens, e.Limit)
+}
+
+func (e *EmbeddingInputTooLongError) Unwrap() error {
+	return ErrEmbeddingInputTooLong
+}
+
+// Tokenizer converts text to and from the tokens of a model, for instance with a
+// tiktoken implementation matching the embedding model.
+type Tokenizer interface {
+	Encode(text string) []int
+	Decode(tokens []int) string
+}
+
+type embeddingBatchOptions struct {
+	batchSize int
+	tokenizer Tokenizer
+	maxTokens int
+	truncate  bool
+	bits      int
+}
+
+// EmbeddingBatchOption configures CreateEmbeddingsBatched.
+type EmbeddingBatchOption func(*embeddingBatchOptions)
+
+// WithEmbeddingBatchSize sets the number of inputs sent per request. Defaults to 2048.
+func WithEmbeddingBatchSize(n int) EmbeddingBatchOption {
+	return func(o *embeddingBatchOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithEmbeddingTokenLimit counts the tokens of every input with tokenizer before any
+// request is sent and fails with an *EmbeddingInputTooLongError for the first input
+// longer than maxTokens. A non-positive maxTokens uses the 8191 token limit of the
+// OpenAI embedding models.
+func WithEmbeddingTokenLimit(tokenizer Tokenizer, maxTokens int) EmbeddingBatchOption {
+	return func(o *embeddingBatchOptions) {
+		o.tokenizer = tokenizer
+		o.maxTokens = maxTokens
+	}
+}
+
+// WithEmbeddingTruncation truncates inputs longer than the token limit set with
+// WithEmbeddingTokenLimit instead of failing. Truncated inputs are reported in
+// EmbeddingBatchResponse.Truncated.
+func WithEmbeddingTruncation() EmbeddingBatchOption {
+	return func(o *embeddingBatchOptions) {
+		o.truncate = true
+	}
+}
+
+// WithEmbeddingQuantization quantizes the embeddings to signed integers of bits bits,
+// 8 or 16, with QuantizeEmbedding. The quantized vectors are returned in
+// EmbeddingBatchResponse.Quantized and the float vectors of Data are dropped.
+func WithEmbeddingQuantization(bits int) EmbeddingBatchOption {
+	return func(o *embeddingBatchOptions) {
+		o.bits = bits
+	}
+}
+
+// EmbeddingBatchResponse is the merged response of CreateEmbeddingsBatched. The
+// Index of each embedding is the index of its input within the whole request, and
+// Usage sums the usage of all requests.
+type EmbeddingBatchResponse struct {
+	EmbeddingResponse
+	// Truncated lists, in ascending order, the indexes of the inputs which were
+	// truncated to the token limit.
+	Truncated []int
+	// Quantized holds the embeddings in input order when WithEmbeddingQuantization is
+	// set. Data then keeps the index and object of every embedding but no vector.
+	Quantized []QuantizedEmbedding
+}
+
+// CreateEmbeddingsBatched embeds any number of inputs, splitting them into requests
+// of at most the batch size and returning the embeddings in input order.
+func (c *Client) CreateEmbeddingsBatched(
+	ctx context.Context,
+	request EmbeddingRequestStrings,
+	opts ...EmbeddingBatchOption,
+) (res EmbeddingBatchResponse, err error) {
+	options := embeddingBatchOptions{batchSize: defaultEmbeddingBatchSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.batchSize <= 0 {
+		options.batchSize = defaultEmbeddingBatchSize
+	}
+	if options.bits != 0 && options.bits != 8 && options.bits != 16 {
+		err = fmt.Errorf("%w: got %d", ErrEmbeddingQuantizationBits, options.bits)
+		return
+	}
+
+	inputs, truncated, err := options.limitTokens(request.Input)
+	if err != nil {
+		return
+	}
+	res.Truncated = truncated
+
+	for start := 0; start < len(inputs); start += options.batchSize {
+		end := start + options.batchSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		batch := request
+		batch.Input = inputs[start:end]
+
+		var batchRes EmbeddingResponse
+		batchRes, err = c.CreateEmbeddings(ctx, batch)
+		if err != nil {
+			return
+		}
+
+		embeddings := batchRes.ByIndex()
+		for i := range batch.Input {
+			embedding, ok := embeddings[i]
+			if !ok {
+				err = fmt.Errorf("%w: input %d", ErrEmbeddingMissing, start+i)
+				return
+			}
+			embedding.Index = start + i
+			if options.bits != 0 {
+				var quantized QuantizedEmbedding
+				if quantized, err = QuantizeEmbedding(embedding.Embedding, options.bits); err != nil {
+					return
+				}
+				quantized.Index = embedding.Index
+				res.Quantized = append(res.Quantized, quantized)
+				embedding.Embedding = nil
+			}
+			res.Data = append(res.Data, embedding)
+		}
+		res.Object, res.Model, res.httpHeader = batchRes.Object, batchRes.Model, batchRes.httpHeader
+		res.Usage.PromptTokens += batchRes.Usage.PromptTokens
+		res.Usage.TotalTokens += batchRes.Usage.TotalTokens
+	}
+	return
+}
+
+// limitTokens checks inputs against the token limit, truncating them when enabled.
+// The returned slice is a copy when an input was truncated.
+func (o embeddingBatchOptions) limitTokens(inputs []string) (limited []string, truncated []int, err error) {
+	if o.tokenizer == nil {
+		return inputs, nil, nil
+	}
+	limit := o.maxTokens
+	if limit <= 0 {
+		limit = defaultEmbeddingMaxTokens
+	}
+
+	limited = inputs
+	for i, input := range inputs {
+		tokens := o.tokenizer.Encode(input)
+		if len(tokens) <= limit {
+			continue
+		}
+		if !o.truncate {
+			return nil, nil, &EmbeddingInputTooLongError{Index: i, Tokens: len(tokens), Limit: limit}
+		}
+		if truncated == nil {
+			limited = append([]string(nil), inputs...)
+		}
+		limited[i] = o.tokenizer.Decode(tokens[:limit])
+		truncated = append(truncated, i)
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// wordTokenizer treats every space separated word as one token.
+type wordTokenizer struct {
+	words []string
+}
+
+func (w *wordTokenizer) Encode(text string) []int {
+	var tokens []int
+	for _, word := range strings.Fields(text) {
+		tokens = append(tokens, len(w.words))
+		w.words = append(w.words, word)
+	}
+	return tokens
+}
+
+func (w *wordTokenizer) Decode(tokens []int) string {
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = w.words[token]
+	}
+	return strings.Join(words, " ")
+}
+
+func TestCreateEmbeddingsBatched(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var batches [][]string
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var req openai.EmbeddingRequestStrings
+		err := json.NewDecoder(r.Body).Decode(&req)
+		checks.NoError(t, err, "Decode error")
+		batches = append(batches, req.Input)
+
+		res := openai.EmbeddingResponse{Model: req.Model, Usage: openai.Usage{PromptTokens: 1, TotalTokens: 1}}
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			words := float32(len(strings.Fields(req.Input[i])))
+			res.Data = append(res.Data, openai.Embedding{Embedding: []float32{words}, Index: i})
+		}
+		resBytes, _ := json.Marshal(res)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	ctx := context.Background()
+	request := openai.EmbeddingRequestStrings{
+		Model: openai.SmallEmbedding3,
+		Input: []string{"one", "one two", "one two three", "one two three four", "one two"},
+	}
+	res, err := client.CreateEmbeddingsBatched(ctx, request, openai.WithEmbeddingBatchSize(2))
+	checks.NoError(t, err, "CreateEmbeddingsBatched error")
+	if len(batches) != 3 || len(res.Data) != 5 || res.Usage.TotalTokens != 3 {
+		t.Fatalf("expected 5 embeddings from 3 requests, got %d from %d", len(res.Data), len(batches))
+	}
+	for i, embedding := range res.Data {
+		if embedding.Index != i || embedding.Embedding[0] != float32(len(strings.Fields(request.Input[i]))) {
+			t.Fatalf("embedding %d is out of order: %+v", i, embedding)
+		}
+	}
+
+	batches = nil
+	_, err = client.CreateEmbeddingsBatched(ctx, request, openai.WithEmbeddingTokenLimit(&wordTokenizer{}, 2))
+	var tooLong *openai.EmbeddingInputTooLongError
+	if !errors.As(err, &tooLong) || !errors.Is(err, openai.ErrEmbeddingInputTooLong) {
+		t.Fatalf("expected EmbeddingInputTooLongError, got %v", err)
+	}
+	if tooLong.Index != 2 || tooLong.Tokens != 3 || tooLong.Limit != 2 || len(batches) != 0 {
+		t.Fatalf("unexpected error %+v after %d requests", tooLong, len(batches))
+	}
+
+	res, err = client.CreateEmbeddingsBatched(ctx, request,
+		openai.WithEmbeddingTokenLimit(&wordTokenizer{}, 2), openai.WithEmbeddingTruncation())
+	checks.NoError(t, err, "CreateEmbeddingsBatched error")
+	if len(res.Truncated) != 2 || res.Truncated[0] != 2 || res.Truncated[1] != 3 {
+		t.Fatalf("expected inputs 2 and 3 to be truncated, got %v", res.Truncated)
+	}
+	if batches[0][2] != "one two" || batches[0][3] != "one two" || request.Input[2] != "one two three" {
+		t.Fatalf("expected truncated inputs to be sent without changing the request, got %v", batches[0])
+	}
+}
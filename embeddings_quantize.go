@@ -0,0 +1,103 @@
+package openai
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrEmbeddingQuantizationBits = errors.New("embedding quantization supports 8 or 16 bits")
+
+// QuantizedEmbedding is an embedding vector stored as signed integers of Bits bits,
+// a quarter or half the size of the float32 vector. Dequantize restores the vector
+// as Values times Scale.
+type QuantizedEmbedding struct {
+	Index int `json:"index"`
+	// Bits is 8 or 16.
+	Bits int `json:"bits"`
+	// Scale is the value of one quantization step.
+	Scale float32 `json:"scale"`
+	// Data holds the quantized values, one byte each for 8 bits and two little endian
+	// bytes each for 16 bits.
+	Data []byte `json:"data"`
+}
+
+// QuantizeEmbedding quantizes vector to signed integers of bits bits, 8 or 16, with a
+// scale chosen so the largest absolute value maps to the largest integer.
+func QuantizeEmbedding(vector []float32, bits int) (quantized QuantizedEmbedding, err error) {
+	if bits != 8 && bits != 16 {
+		err = fmt.Errorf("%w: got %d", ErrEmbeddingQuantizationBits, bits)
+		return
+	}
+
+	var maxAbs float64
+	for _, v := range vector {
+		maxAbs = math.Max(maxAbs, math.Abs(float64(v)))
+	}
+	levels := float64(int(1)<<(bits-1) - 1)
+	quantized.Bits = bits
+	quantized.Data = make([]byte, len(vector)*bits/8)
+	if maxAbs == 0 {
+		return
+	}
+	quantized.Scale = float32(maxAbs / levels)
+
+	for i, v := range vector {
+		q := math.Round(float64(v) / maxAbs * levels)
+		if bits == 8 {
+			quantized.Data[i] = byte(int8(q))
+		} else {
+			binary.LittleEndian.PutUint16(quantized.Data[2*i:], uint16(int16(q)))
+		}
+	}
+	return
+}
+
+// Len returns the dimension of the quantized vector.
+func (q QuantizedEmbedding) Len() int {
+	if q.Bits == 16 {
+		return len(q.Data) / 2
+	}
+	return len(q.Data)
+}
+
+// value returns the quantized integer at index i.
+func (q QuantizedEmbedding) value(i int) int {
+	if q.Bits == 16 {
+		return int(int16(binary.LittleEndian.Uint16(q.Data[2*i:])))
+	}
+	return int(int8(q.Data[i]))
+}
+
+// Dequantize returns the approximate float32 embedding vector.
+func (q QuantizedEmbedding) Dequantize() []float32 {
+	vector := make([]float32, q.Len())
+	for i := range vector {
+		vector[i] = float32(q.value(i)) * q.Scale
+	}
+	return vector
+}
+
+// CosineSimilarity calculates the cosine similarity of two quantized vectors without
+// dequantizing them, since the scales do not change the angle between the vectors.
+// The vectors may use different Bits but must have the same length; otherwise an
+// ErrVectorLengthMismatch is returned. Zero vectors have a similarity of 0.
+func (q QuantizedEmbedding) CosineSimilarity(other QuantizedEmbedding) (float32, error) {
+	if q.Len() != other.Len() {
+		return 0, ErrVectorLengthMismatch
+	}
+
+	var dot, norm, otherNorm float64
+	for i := 0; i < q.Len(); i++ {
+		a, b := float64(q.value(i)), float64(other.value(i))
+		dot += a * b
+		norm += a * a
+		otherNorm += b * b
+	}
+	if norm == 0 || otherNorm == 0 {
+		return 0, nil
+	}
+
+	return float32(dot / math.Sqrt(norm*otherNorm)), nil
+}
@@ -0,0 +1,98 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestQuantizeEmbedding(t *testing.T) {
+	a := []float32{0.12, -0.5, 0.33, 0.01, -0.27, 0.9}
+	b := []float32{0.1, -0.45, 0.3, -0.02, -0.2, 0.85}
+	want, err := (&openai.Embedding{Embedding: a}).CosineSimilarity(&openai.Embedding{Embedding: b})
+	checks.NoError(t, err, "CosineSimilarity error")
+
+	for _, bits := range []int{8, 16} {
+		qa, err := openai.QuantizeEmbedding(a, bits)
+		checks.NoError(t, err, "QuantizeEmbedding error")
+		qb, err := openai.QuantizeEmbedding(b, bits)
+		checks.NoError(t, err, "QuantizeEmbedding error")
+		if qa.Len() != len(a) || len(qa.Data) != len(a)*bits/8 {
+			t.Fatalf("%d bits: unexpected size %d of %d bytes", bits, qa.Len(), len(qa.Data))
+		}
+
+		tolerance := float64(qa.Scale)
+		for i, v := range qa.Dequantize() {
+			if math.Abs(float64(v-a[i])) > tolerance {
+				t.Fatalf("%d bits: value %d dequantized to %v, want %v", bits, i, v, a[i])
+			}
+		}
+		got, err := qa.CosineSimilarity(qb)
+		checks.NoError(t, err, "CosineSimilarity error")
+		if math.Abs(float64(got-want)) > 0.01 {
+			t.Fatalf("%d bits: quantized similarity %v, want about %v", bits, got, want)
+		}
+
+		data, err := json.Marshal(qa)
+		checks.NoError(t, err, "Marshal error")
+		var decoded openai.QuantizedEmbedding
+		checks.NoError(t, json.Unmarshal(data, &decoded), "Unmarshal error")
+		if decoded.Scale != qa.Scale || string(decoded.Data) != string(qa.Data) {
+			t.Fatalf("%d bits: quantized embedding did not survive JSON", bits)
+		}
+	}
+
+	q8, _ := openai.QuantizeEmbedding(a, 8)
+	q16, _ := openai.QuantizeEmbedding(a, 16)
+	if got, _ := q8.CosineSimilarity(q16); math.Abs(float64(got)-1) > 0.001 {
+		t.Fatalf("expected vectors of different bits to compare, got %v", got)
+	}
+	_, err = q8.CosineSimilarity(openai.QuantizedEmbedding{Bits: 8, Data: []byte{1}})
+	checks.ErrorIs(t, err, openai.ErrVectorLengthMismatch, "expected a length mismatch")
+
+	zero, err := openai.QuantizeEmbedding([]float32{0, 0}, 8)
+	checks.NoError(t, err, "QuantizeEmbedding error")
+	if zero.Scale != 0 || zero.Dequantize()[1] != 0 {
+		t.Fatalf("unexpected zero vector quantization: %+v", zero)
+	}
+	_, err = openai.QuantizeEmbedding(a, 4)
+	checks.ErrorIs(t, err, openai.ErrEmbeddingQuantizationBits, "expected unsupported bits to fail")
+}
+
+func TestCreateEmbeddingsBatchedQuantization(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	requests := 0
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		resBytes, _ := json.Marshal(openai.EmbeddingResponse{Data: []openai.Embedding{
+			{Embedding: []float32{0.5, -1}, Index: 0},
+			{Embedding: []float32{1, 0.25}, Index: 1},
+		}})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	ctx := context.Background()
+	request := openai.EmbeddingRequestStrings{Model: openai.SmallEmbedding3, Input: []string{"a", "b"}}
+	res, err := client.CreateEmbeddingsBatched(ctx, request, openai.WithEmbeddingQuantization(8))
+	checks.NoError(t, err, "CreateEmbeddingsBatched error")
+	if len(res.Quantized) != 2 || res.Quantized[1].Index != 1 || res.Data[1].Embedding != nil {
+		t.Fatalf("expected quantized embeddings only, got %+v", res)
+	}
+	if got := res.Quantized[0].Dequantize(); math.Abs(float64(got[1])+1) > 0.01 {
+		t.Fatalf("unexpected dequantized embedding: %v", got)
+	}
+
+	_, err = client.CreateEmbeddingsBatched(ctx, request, openai.WithEmbeddingQuantization(12))
+	checks.ErrorIs(t, err, openai.ErrEmbeddingQuantizationBits, "expected unsupported bits to fail")
+	if requests != 1 {
+		t.Fatalf("expected unsupported bits to fail before sending, got %d requests", requests)
+	}
+}
@@ -246,6 +246,40 @@ func TestEmbeddingResponseBase64_ToEmbeddingResponse(t *testing.T) {
 	}
 }
 
+func TestEmbeddingResponseByIndex(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req_abc123")
+		resBytes, _ := json.Marshal(openai.EmbeddingResponseBase64{
+			Data: []openai.Base64Embedding{
+				{Embedding: "/1jku0G/rLvA/EI8", Index: 1},
+				{Embedding: "pHCdP4XrkUDhevxA", Index: 0},
+			},
+			Model: openai.SmallEmbedding3,
+			Usage: openai.Usage{PromptTokens: 4, TotalTokens: 4},
+		})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	res, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+		EncodingFormat: openai.EmbeddingEncodingFormatBase64,
+	})
+	checks.NoError(t, err, "CreateEmbeddings error")
+	if res.Model != openai.SmallEmbedding3 || res.Usage.TotalTokens != 4 {
+		t.Fatalf("expected model and usage to be preserved, got %s, %+v", res.Model, res.Usage)
+	}
+	if res.Header().Get("X-Request-Id") != "req_abc123" {
+		t.Fatalf("expected response headers to be preserved")
+	}
+
+	byIndex := res.ByIndex()
+	if len(byIndex) != 2 || byIndex[0].Embedding[0] != 1.23 || byIndex[1].Index != 1 {
+		t.Fatalf("unexpected embeddings by index: %+v", byIndex)
+	}
+}
+
 func TestDotProduct(t *testing.T) {
 	v1 := &openai.Embedding{Embedding: []float32{1, 2, 3}}
 	v2 := &openai.Embedding{Embedding: []float32{2, 4, 6}}
@@ -281,3 +315,26 @@ func TestDotProduct(t *testing.T) {
 		t.Errorf("Expected Vector Length Mismatch Error, but got: %v", err)
 	}
 }
+
+func TestCosineSimilarity(t *testing.T) {
+	v1 := &openai.Embedding{Embedding: []float32{1, 2, 3}}
+	v2 := &openai.Embedding{Embedding: []float32{2, 4, 6}}
+	result, err := v1.CosineSimilarity(v2)
+	checks.NoError(t, err, "CosineSimilarity error")
+	if math.Abs(float64(result-1)) > 1e-6 {
+		t.Errorf("Expected parallel vectors to have a similarity of 1, got %v", result)
+	}
+
+	v2 = &openai.Embedding{Embedding: []float32{0, 0, 0}}
+	result, err = v1.CosineSimilarity(v2)
+	checks.NoError(t, err, "CosineSimilarity error")
+	if result != 0 {
+		t.Errorf("Expected a zero vector to have a similarity of 0, got %v", result)
+	}
+
+	v2 = &openai.Embedding{Embedding: []float32{0, 1}}
+	_, err = v1.CosineSimilarity(v2)
+	if !errors.Is(err, openai.ErrVectorLengthMismatch) {
+		t.Errorf("Expected Vector Length Mismatch Error, but got: %v", err)
+	}
+}
@@ -16,6 +16,9 @@ type APIError struct {
 	HTTPStatus     string      `json:"-"`
 	HTTPStatusCode int         `json:"-"`
 	InnerError     *InnerError `json:"innererror,omitempty"`
+	// RequestID is the x-request-id header of the failed response, to quote when
+	// contacting support.
+	RequestID string `json:"-"`
 }
 
 // InnerError Azure Content filtering. Only valid for Azure OpenAI Service.
@@ -30,6 +33,8 @@ type RequestError struct {
 	HTTPStatusCode int
 	Err            error
 	Body           []byte
+	// RequestID is the x-request-id header of the failed response.
+	RequestID string
 }
 
 type ErrorResponse struct {
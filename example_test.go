@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
@@ -269,6 +270,23 @@ func ExampleClientConfig_clientWithProxy() {
 	)
 }
 
+func ExampleClientConfig_requestLogger() {
+	config := openai.DefaultConfig(os.Getenv("OPENAI_API_KEY"))
+	config.RequestLogger = func(entry openai.RequestLog) {
+		log.Printf("%s %s\n%s\n-> %d\n%s", entry.Method, entry.URL, entry.RequestBody,
+			entry.StatusCode, entry.ResponseBody)
+	}
+
+	client := openai.NewClientWithConfig(config)
+
+	client.CreateChatCompletion( //nolint:errcheck // outside of the scope of this example.
+		context.Background(),
+		openai.ChatCompletionRequest{
+			// etc...
+		},
+	)
+}
+
 func Example_chatbot() {
 	client := openai.NewClient(os.Getenv("OPENAI_API_KEY"))
 
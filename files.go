@@ -3,15 +3,34 @@ package openai
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"time"
+
+	utils "github.com/sashabaranov/go-openai/internal"
 )
 
+var ErrMultipartBoundaryUnsupported = errors.New("form builder does not support custom multipart boundaries")
+
 type FileRequest struct {
 	FileName string `json:"file"`
 	FilePath string `json:"-"`
 	Purpose  string `json:"purpose"`
+	// Multipart optionally customizes the encoding of the upload.
+	Multipart *MultipartOptions `json:"-"`
+}
+
+// MultipartOptions customizes how file and audio uploads are encoded, for gateways
+// which are strict about multipart boundaries or field order.
+type MultipartOptions struct {
+	// Boundary replaces the randomly generated multipart boundary.
+	Boundary string
+	// FileFirst writes the file part before the metadata fields.
+	// By default the metadata fields come first and the file part is written last.
+	FileFirst bool
 }
 
 // PurposeType represents the purpose of the file when uploading.
@@ -23,6 +42,7 @@ const (
 	PurposeAssistants       PurposeType = "assistants"
 	PurposeAssistantsOutput PurposeType = "assistants_output"
 	PurposeBatch            PurposeType = "batch"
+	PurposeVision           PurposeType = "vision"
 )
 
 // FileBytesRequest represents a file upload request.
@@ -33,6 +53,8 @@ type FileBytesRequest struct {
 	Bytes []byte
 	// the purpose of the file
 	Purpose PurposeType
+	// Multipart optionally customizes the encoding of the upload.
+	Multipart *MultipartOptions
 }
 
 // File struct represents an OpenAPI file.
@@ -49,6 +71,11 @@ type File struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (f File) CreatedTime() time.Time {
+	return time.Unix(f.CreatedAt, 0)
+}
+
 // FilesList is a list of files that belong to the user or organization.
 type FilesList struct {
 	Files []File `json:"data"`
@@ -62,17 +89,9 @@ func (c *Client) CreateFileBytes(ctx context.Context, request FileBytesRequest)
 	reader := bytes.NewReader(request.Bytes)
 	builder := c.createFormBuilder(&b)
 
-	err = builder.WriteField("purpose", string(request.Purpose))
-	if err != nil {
-		return
-	}
-
-	err = builder.CreateFormFileReader("file", reader, request.Name)
-	if err != nil {
-		return
-	}
-
-	err = builder.Close()
+	err = fileUploadMultipartForm(builder, string(request.Purpose), request.Multipart, func() error {
+		return builder.CreateFormFileReader("file", reader, request.Name)
+	})
 	if err != nil {
 		return
 	}
@@ -93,35 +112,78 @@ func (c *Client) CreateFile(ctx context.Context, request FileRequest) (file File
 	var b bytes.Buffer
 	builder := c.createFormBuilder(&b)
 
-	err = builder.WriteField("purpose", request.Purpose)
+	fileData, err := os.Open(request.FilePath)
 	if err != nil {
 		return
 	}
+	defer fileData.Close()
 
-	fileData, err := os.Open(request.FilePath)
+	err = fileUploadMultipartForm(builder, request.Purpose, request.Multipart, func() error {
+		return builder.CreateFormFile("file", fileData)
+	})
 	if err != nil {
 		return
 	}
-	defer fileData.Close()
 
-	err = builder.CreateFormFile("file", fileData)
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/files"),
+		withBody(&b), withContentType(builder.FormDataContentType()))
 	if err != nil {
 		return
 	}
 
-	err = builder.Close()
+	err = c.sendRequest(req, &file)
+	return
+}
+
+// fileUploadMultipartForm writes the purpose field and the file part in the order
+// requested by opts, then closes the form.
+func fileUploadMultipartForm(
+	builder utils.FormBuilder,
+	purpose string,
+	opts *MultipartOptions,
+	writeFile func() error,
+) (err error) {
+	if opts == nil {
+		opts = &MultipartOptions{}
+	}
+
+	err = setMultipartBoundary(builder, opts.Boundary)
 	if err != nil {
 		return
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/files"),
-		withBody(&b), withContentType(builder.FormDataContentType()))
+	if opts.FileFirst {
+		err = writeFile()
+		if err != nil {
+			return
+		}
+	}
+
+	err = builder.WriteField("purpose", purpose)
 	if err != nil {
 		return
 	}
 
-	err = c.sendRequest(req, &file)
-	return
+	if !opts.FileFirst {
+		err = writeFile()
+		if err != nil {
+			return
+		}
+	}
+
+	return builder.Close()
+}
+
+// setMultipartBoundary replaces the boundary of builder unless boundary is empty.
+func setMultipartBoundary(builder utils.FormBuilder, boundary string) error {
+	if boundary == "" {
+		return nil
+	}
+	setter, ok := builder.(interface{ SetBoundary(string) error })
+	if !ok {
+		return ErrMultipartBoundaryUnsupported
+	}
+	return setter.SetBoundary(boundary)
 }
 
 // DeleteFile deletes an existing file.
@@ -169,3 +231,83 @@ func (c *Client) GetFileContent(ctx context.Context, fileID string) (content Raw
 
 	return c.sendRequestRaw(req)
 }
+
+// FileContent is a streamed download of a file's content.
+// Closing Reader aborts the download if it is still in flight.
+type FileContent struct {
+	Reader io.ReadCloser
+	// Size is the Content-Length reported by the server, or -1 when unknown.
+	Size        int64
+	ContentType string
+
+	httpHeader
+}
+
+type downloadOptions struct {
+	progress func(downloaded, total int64)
+}
+
+// DownloadOption configures DownloadFileContent.
+type DownloadOption func(*downloadOptions)
+
+// WithProgress registers fn to be called after every read of the download with the
+// number of bytes read so far and the total size, which is -1 when unknown.
+func WithProgress(fn func(downloaded, total int64)) DownloadOption {
+	return func(o *downloadOptions) {
+		o.progress = fn
+	}
+}
+
+type progressReader struct {
+	io.ReadCloser
+	downloaded int64
+	total      int64
+	progress   func(downloaded, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	if n > 0 {
+		r.downloaded += int64(n)
+		r.progress(r.downloaded, r.total)
+	}
+	return
+}
+
+// DownloadFileContent streams the content of a file. The download is bound to ctx,
+// so cancelling it stops the transfer mid-way, and the returned reader must be closed.
+// The files of a message are downloaded with it too, by the ID of the MessageFile.
+func (c *Client) DownloadFileContent(
+	ctx context.Context,
+	fileID string,
+	opts ...DownloadOption,
+) (content FileContent, err error) {
+	var options downloadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	urlSuffix := fmt.Sprintf("/files/%s/content", fileID)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	resp, err := c.sendRequestBody(req) //nolint:bodyclose // body should be closed by outer function
+	if err != nil {
+		return
+	}
+
+	content.SetHeader(resp.Header)
+	content.Size = resp.ContentLength
+	content.ContentType = resp.Header.Get("Content-Type")
+	content.Reader = resp.Body
+	if options.progress != nil {
+		content.Reader = &progressReader{
+			ReadCloser: resp.Body,
+			total:      resp.ContentLength,
+			progress:   options.progress,
+		}
+	}
+	return
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -194,3 +195,83 @@ func TestGetFileContentReturnTimeoutError(t *testing.T) {
 		t.Fatal("Did not return timeout error")
 	}
 }
+
+func TestFileUploadMultipartOrder(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var parts []string
+	var contentType string
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		parts = nil
+		reader, err := r.MultipartReader()
+		checks.NoError(t, err, "MultipartReader error")
+		for {
+			part, partErr := reader.NextPart()
+			if errors.Is(partErr, io.EOF) {
+				break
+			}
+			checks.NoError(t, partErr, "NextPart error")
+			parts = append(parts, part.FormName())
+		}
+		fmt.Fprintln(w, `{"id":"file-abc123","object":"file"}`)
+	})
+
+	ctx := context.Background()
+	_, err := client.CreateFile(ctx, openai.FileRequest{
+		FilePath: "client.go",
+		Purpose:  "fine-tune",
+	})
+	checks.NoError(t, err, "CreateFile error")
+	if len(parts) != 2 || parts[0] != "purpose" || parts[1] != "file" {
+		t.Fatalf("expected the file part after purpose, got %v", parts)
+	}
+
+	_, err = client.CreateFileBytes(ctx, openai.FileBytesRequest{
+		Name:    "foo",
+		Bytes:   []byte("foo"),
+		Purpose: openai.PurposeFineTune,
+		Multipart: &openai.MultipartOptions{
+			Boundary:  "custom-boundary",
+			FileFirst: true,
+		},
+	})
+	checks.NoError(t, err, "CreateFileBytes error")
+	if len(parts) != 2 || parts[0] != "file" || parts[1] != "purpose" {
+		t.Fatalf("expected the file part first, got %v", parts)
+	}
+	if !strings.Contains(contentType, "boundary=custom-boundary") {
+		t.Fatalf("expected custom boundary, got %s", contentType)
+	}
+}
+
+func TestDownloadFileContent(t *testing.T) {
+	want := `{"prompt": "foo", "completion": "foo"}` + "\n"
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/files/deadbeef/content", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/jsonl")
+		w.Header().Set("Content-Length", strconv.Itoa(len(want)))
+		fmt.Fprint(w, want)
+	})
+
+	var downloaded, total int64
+	content, err := client.DownloadFileContent(context.Background(), "deadbeef",
+		openai.WithProgress(func(d, t int64) {
+			downloaded, total = d, t
+		}))
+	checks.NoError(t, err, "DownloadFileContent error")
+	defer content.Reader.Close()
+
+	if content.Size != int64(len(want)) || content.ContentType != "application/jsonl" {
+		t.Fatalf("unexpected content metadata: size %d, type %q", content.Size, content.ContentType)
+	}
+	actual, _ := io.ReadAll(content.Reader)
+	if string(actual) != want {
+		t.Errorf("Expected %s, got %s", want, string(actual))
+	}
+	if downloaded != int64(len(want)) || total != int64(len(want)) {
+		t.Errorf("unexpected progress: %d of %d", downloaded, total)
+	}
+}
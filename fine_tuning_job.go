@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type FineTuningJob struct {
@@ -25,6 +26,11 @@ type FineTuningJob struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (f FineTuningJob) CreatedTime() time.Time {
+	return time.Unix(f.CreatedAt, 0)
+}
+
 type Hyperparameters struct {
 	Epochs                 any `json:"n_epochs,omitempty"`
 	LearningRateMultiplier any `json:"learning_rate_multiplier,omitempty"`
@@ -57,6 +63,11 @@ type FineTuningJobEvent struct {
 	Type      string `json:"type"`
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (e FineTuningJobEvent) CreatedTime() time.Time {
+	return time.Unix(int64(e.CreatedAt), 0)
+}
+
 // CreateFineTuningJob create a fine tuning job.
 func (c *Client) CreateFineTuningJob(
 	ctx context.Context,
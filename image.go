@@ -3,11 +3,18 @@ package openai
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
+
+	utils "github.com/sashabaranov/go-openai/internal"
 )
 
+var ErrImageStreamUnsupportedModel = errors.New("image streaming is only supported by the gpt-image-1 model")
+
 // Image sizes defined by the OpenAI API.
 const (
 	CreateImageSize256x256   = "256x256"
@@ -91,11 +98,35 @@ type ImageRequest struct {
 type ImageResponse struct {
 	Created int64                    `json:"created,omitempty"`
 	Data    []ImageResponseDataInner `json:"data,omitempty"`
-	Usage   ImageResponseUsage       `json:"usage,omitempty"`
+	// Usage is reported by gpt-image models only. HasUsage tells dall-e responses,
+	// which have none, apart from a zero usage.
+	Usage ImageResponseUsage `json:"usage,omitempty"`
 
+	hasUsage bool
 	httpHeader
 }
 
+// HasUsage reports whether the response carried a usage object.
+func (r *ImageResponse) HasUsage() bool {
+	return r.hasUsage
+}
+
+func (r *ImageResponse) UnmarshalJSON(data []byte) error {
+	type alias ImageResponse
+	response := struct {
+		*alias
+		Usage *ImageResponseUsage `json:"usage"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return err
+	}
+	r.Usage, r.hasUsage = ImageResponseUsage{}, response.Usage != nil
+	if response.Usage != nil {
+		r.Usage = *response.Usage
+	}
+	return nil
+}
+
 // ImageResponseInputTokensDetails represents the token breakdown for input tokens.
 type ImageResponseInputTokensDetails struct {
 	TextTokens  int `json:"text_tokens,omitempty"`
@@ -145,6 +176,52 @@ type ImageEditRequest struct {
 	ResponseFormat string   `json:"response_format,omitempty"`
 	Quality        string   `json:"quality,omitempty"`
 	User           string   `json:"user,omitempty"`
+	// PartialImages is the number of partial previews (0-3) to emit when streaming. gpt-image-1 only.
+	PartialImages int `json:"partial_images,omitempty"`
+}
+
+// ImageStreamEventType is the type of an event emitted by a streamed image request.
+type ImageStreamEventType string
+
+const (
+	ImageStreamEventTypeGenerationPartialImage ImageStreamEventType = "image_generation.partial_image"
+	ImageStreamEventTypeGenerationCompleted    ImageStreamEventType = "image_generation.completed"
+	ImageStreamEventTypeEditPartialImage       ImageStreamEventType = "image_edit.partial_image"
+	ImageStreamEventTypeEditCompleted          ImageStreamEventType = "image_edit.completed"
+)
+
+// ImageStreamEvent represents a partial preview or the final result of a streamed image request.
+type ImageStreamEvent struct {
+	Type              ImageStreamEventType `json:"type"`
+	B64JSON           string               `json:"b64_json"`
+	CreatedAt         int64                `json:"created_at"`
+	Size              string               `json:"size,omitempty"`
+	Quality           string               `json:"quality,omitempty"`
+	Background        string               `json:"background,omitempty"`
+	OutputFormat      string               `json:"output_format,omitempty"`
+	PartialImageIndex int                  `json:"partial_image_index"`
+	// Usage is only present on the completed event.
+	Usage *ImageResponseUsage `json:"usage,omitempty"`
+}
+
+// CreatedTime returns CreatedAt as a time.Time.
+func (e ImageStreamEvent) CreatedTime() time.Time {
+	return time.Unix(e.CreatedAt, 0)
+}
+
+// IsPartial reports whether the event carries a partial preview.
+func (e ImageStreamEvent) IsPartial() bool {
+	return e.Type == ImageStreamEventTypeGenerationPartialImage || e.Type == ImageStreamEventTypeEditPartialImage
+}
+
+// IsCompleted reports whether the event carries the final image.
+func (e ImageStreamEvent) IsCompleted() bool {
+	return e.Type == ImageStreamEventTypeGenerationCompleted || e.Type == ImageStreamEventTypeEditCompleted
+}
+
+// ImageStream streams partial image previews followed by the final image.
+type ImageStream struct {
+	*streamReader[ImageStreamEvent]
 }
 
 // CreateEditImage - API call to create an image. This is the main endpoint of the DALL-E API.
@@ -152,40 +229,69 @@ func (c *Client) CreateEditImage(ctx context.Context, request ImageEditRequest)
 	body := &bytes.Buffer{}
 	builder := c.createFormBuilder(body)
 
-	// image
-	err = builder.CreateFormFile("image", request.Image)
+	err = imageEditMultipartForm(request, builder)
 	if err != nil {
 		return
 	}
 
-	// mask, it is optional
-	if request.Mask != nil {
-		err = builder.CreateFormFile("mask", request.Mask)
-		if err != nil {
-			return
-		}
+	err = builder.Close()
+	if err != nil {
+		return
 	}
 
-	err = builder.WriteField("prompt", request.Prompt)
+	req, err := c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL("/images/edits", withModel(request.Model)),
+		withBody(body),
+		withContentType(builder.FormDataContentType()),
+	)
 	if err != nil {
 		return
 	}
 
-	err = builder.WriteField("n", strconv.Itoa(request.N))
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// CreateEditImageStream - API call to edit an image with streaming support. Partial previews
+// are sent as they become available, followed by an event carrying the final image.
+// Streaming is only supported by the gpt-image-1 model.
+func (c *Client) CreateEditImageStream(
+	ctx context.Context,
+	request ImageEditRequest,
+	opts ...StreamOption,
+) (stream *ImageStream, err error) {
+	if request.Model != CreateImageModelGptImage1 {
+		err = ErrImageStreamUnsupportedModel
+		return
+	}
+
+	body := &bytes.Buffer{}
+	builder := c.createFormBuilder(body)
+
+	err = imageEditMultipartForm(request, builder)
 	if err != nil {
 		return
 	}
 
-	err = builder.WriteField("size", request.Size)
+	err = builder.WriteField("model", request.Model)
 	if err != nil {
 		return
 	}
 
-	err = builder.WriteField("response_format", request.ResponseFormat)
+	err = builder.WriteField("stream", "true")
 	if err != nil {
 		return
 	}
 
+	if request.PartialImages > 0 {
+		err = builder.WriteField("partial_images", strconv.Itoa(request.PartialImages))
+		if err != nil {
+			return
+		}
+	}
+
 	err = builder.Close()
 	if err != nil {
 		return
@@ -202,7 +308,48 @@ func (c *Client) CreateEditImage(ctx context.Context, request ImageEditRequest)
 		return
 	}
 
-	err = c.sendRequest(req, &response)
+	resp, err := sendRequestStream[ImageStreamEvent](c, req, opts...)
+	if err != nil {
+		return
+	}
+	stream = &ImageStream{
+		streamReader: resp,
+	}
+	return
+}
+
+// imageEditMultipartForm writes the image edit fields shared by the plain and streaming calls.
+func imageEditMultipartForm(request ImageEditRequest, builder utils.FormBuilder) (err error) {
+	// image
+	err = builder.CreateFormFile("image", request.Image)
+	if err != nil {
+		return
+	}
+
+	// mask, it is optional
+	if request.Mask != nil {
+		err = builder.CreateFormFile("mask", request.Mask)
+		if err != nil {
+			return
+		}
+	}
+
+	err = builder.WriteField("prompt", request.Prompt)
+	if err != nil {
+		return
+	}
+
+	err = builder.WriteField("n", strconv.Itoa(request.N))
+	if err != nil {
+		return
+	}
+
+	err = builder.WriteField("size", request.Size)
+	if err != nil {
+		return
+	}
+
+	err = builder.WriteField("response_format", request.ResponseFormat)
 	return
 }
 
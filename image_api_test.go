@@ -1,13 +1,16 @@
 package openai_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,6 +35,45 @@ func TestImages(t *testing.T) {
 	checks.NoError(t, err, "CreateImage error")
 }
 
+func TestImagesUsage(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/images/generations", func(w http.ResponseWriter, r *http.Request) {
+		imageReq, err := getImageBody(r)
+		checks.NoError(t, err, "getImageBody error")
+		switch imageReq.Model {
+		case openai.CreateImageModelGptImage1:
+			fmt.Fprintln(w, `{"created":1,"data":[{"b64_json":"e30K"}],"usage":{"total_tokens":110,`+
+				`"input_tokens":50,"output_tokens":60,"input_tokens_details":{"text_tokens":40,"image_tokens":10}}}`)
+		case openai.CreateImageModelDallE2:
+			fmt.Fprintln(w, `{"created":1,"data":[{"url":"https://example.com/image.png"}],"usage":null}`)
+		default:
+			fmt.Fprintln(w, `{"created":1,"data":[{"url":"https://example.com/image.png"}]}`)
+		}
+	})
+
+	for _, model := range []string{openai.CreateImageModelDallE3, openai.CreateImageModelDallE2} {
+		res, err := client.CreateImage(context.Background(), openai.ImageRequest{Prompt: "Lorem ipsum", Model: model})
+		checks.NoError(t, err, "CreateImage error")
+		if res.HasUsage() || res.Usage != (openai.ImageResponseUsage{}) || len(res.Data) != 1 {
+			t.Fatalf("expected no usage for %s, got %+v", model, res)
+		}
+	}
+
+	res, err := client.CreateImage(context.Background(), openai.ImageRequest{
+		Prompt: "Lorem ipsum",
+		Model:  openai.CreateImageModelGptImage1,
+	})
+	checks.NoError(t, err, "CreateImage error")
+	if !res.HasUsage() || res.Usage.TotalTokens != 110 || res.Usage.OutputTokens != 60 ||
+		res.Usage.InputTokensDetails.ImageTokens != 10 || res.Data[0].B64JSON != "e30K" {
+		t.Fatalf("unexpected gpt-image-1 usage: %+v", res)
+	}
+	if res.Header() == nil {
+		t.Fatal("expected the response headers to be kept")
+	}
+}
+
 // handleImageEndpoint Handles the images endpoint by the test server.
 func handleImageEndpoint(w http.ResponseWriter, r *http.Request) {
 	var err error
@@ -212,3 +254,81 @@ func handleVariateImageEndpoint(w http.ResponseWriter, r *http.Request) {
 	resBytes, _ = json.Marshal(responses)
 	fmt.Fprintln(w, string(resBytes))
 }
+
+func TestImageEditStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/images/edits", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			t.Fatalf("expected multipart request, got %s", r.Header.Get("Content-Type"))
+		}
+		if r.FormValue("stream") != "true" || r.FormValue("partial_images") != "2" {
+			t.Fatalf("expected streaming fields, got stream=%q partial_images=%q",
+				r.FormValue("stream"), r.FormValue("partial_images"))
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		dataBytes := []byte{}
+		for i := 0; i < 2; i++ {
+			dataBytes = append(dataBytes, []byte("event: image_edit.partial_image\n")...)
+			data := fmt.Sprintf(`{"type":"image_edit.partial_image","b64_json":"partial%d","partial_image_index":%d}`, i, i)
+			dataBytes = append(dataBytes, []byte("data: "+data+"\n\n")...)
+		}
+		dataBytes = append(dataBytes, []byte("event: image_edit.completed\n")...)
+		data := `{"type":"image_edit.completed","b64_json":"final","usage":{"total_tokens":10}}`
+		dataBytes = append(dataBytes, []byte("data: "+data+"\n\n")...)
+
+		_, err := w.Write(dataBytes)
+		checks.NoError(t, err, "Write error")
+	})
+
+	origin, err := os.Create(filepath.Join(t.TempDir(), "image.png"))
+	if err != nil {
+		t.Fatalf("open origin file error: %v", err)
+	}
+	defer origin.Close()
+
+	var captured bytes.Buffer
+	stream, err := client.CreateEditImageStream(context.Background(), openai.ImageEditRequest{
+		Image:         origin,
+		Prompt:        "There is a turtle in the pool",
+		Model:         openai.CreateImageModelGptImage1,
+		PartialImages: 2,
+	}, openai.WithStreamCapture(&captured))
+	checks.NoError(t, err, "CreateEditImageStream error")
+	defer stream.Close()
+
+	var partials int
+	var final openai.ImageStreamEvent
+	for {
+		event, streamErr := stream.Recv()
+		if errors.Is(streamErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, streamErr, "stream.Recv() failed")
+		if event.IsPartial() {
+			partials++
+		}
+		if event.IsCompleted() {
+			final = event
+		}
+	}
+	if partials != 2 {
+		t.Fatalf("expected 2 partial images, got %d", partials)
+	}
+	if final.B64JSON != "final" || final.Usage == nil || final.Usage.TotalTokens != 10 {
+		t.Fatalf("unexpected final event: %+v", final)
+	}
+	stream.Close()
+	if !strings.Contains(captured.String(), "event: image_edit.completed") {
+		t.Fatalf("expected the stream options to be applied, captured %q", captured.String())
+	}
+}
+
+func TestImageEditStreamUnsupportedModel(t *testing.T) {
+	client := openai.NewClient("whatever")
+	_, err := client.CreateEditImageStream(context.Background(), openai.ImageEditRequest{
+		Model: openai.CreateImageModelDallE2,
+	})
+	checks.ErrorIs(t, err, openai.ErrImageStreamUnsupportedModel, "CreateEditImageStream should reject dall-e-2")
+}
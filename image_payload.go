@@ -0,0 +1,72 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxInlineImagePayloadBytes is the default upper bound for the total size of
+// base64 data URL images embedded in a single request. It mirrors the 20MB request
+// limit enforced by the OpenAI API.
+const DefaultMaxInlineImagePayloadBytes = 20 * 1024 * 1024
+
+var ErrImagePayloadTooLarge = errors.New("inline image payload too large, upload the image and reference it by file ID instead") //nolint:lll
+
+// ImagePayloadTooLargeError names the content block which pushed the inline image
+// payload of a request over the configured limit.
+type ImagePayloadTooLargeError struct {
+	// MessageIndex is the index of the offending message within the request.
+	MessageIndex int
+	// PartIndex is the index of the offending content part within the message.
+	PartIndex int
+	// Size is the encoded size in bytes of the offending data URL.
+	Size int
+	// Total is the accumulated inline image payload, including the offending block.
+	Total int
+	// Limit is the configured maximum payload size in bytes.
+	Limit int
+}
+
+func (e *ImagePayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%s: messages[%d].content[%d] is %d bytes, total %d bytes exceeds limit of %d bytes",
+		ErrImagePayloadTooLarge, e.MessageIndex, e.PartIndex, e.Size, e.Total, e.Limit)
+}
+
+func (e *ImagePayloadTooLargeError) Unwrap() error {
+	return ErrImagePayloadTooLarge
+}
+
+// isDataURL reports whether url carries an inline base64 payload.
+func isDataURL(url string) bool {
+	return strings.HasPrefix(url, "data:") && strings.Contains(url, ";base64,")
+}
+
+// validateInlineImagePayload checks the accumulated size of all base64 data URL images
+// in messages against limit. A non-positive limit disables the check.
+func validateInlineImagePayload(messages []ChatCompletionMessage, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	var total int
+	for i, message := range messages {
+		for j, part := range message.MultiContent {
+			if part.ImageURL == nil || !isDataURL(part.ImageURL.URL) {
+				continue
+			}
+			size := len(part.ImageURL.URL)
+			total += size
+			if total > limit {
+				return &ImagePayloadTooLargeError{
+					MessageIndex: i,
+					PartIndex:    j,
+					Size:         size,
+					Total:        total,
+					Limit:        limit,
+				}
+			}
+		}
+	}
+	return nil
+}
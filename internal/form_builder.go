@@ -52,6 +52,12 @@ func (fb *DefaultFormBuilder) createFormFile(fieldname string, r io.Reader, file
 	return nil
 }
 
+// SetBoundary overrides the randomly generated multipart boundary.
+// It must be called before any part is written.
+func (fb *DefaultFormBuilder) SetBoundary(boundary string) error {
+	return fb.writer.SetBoundary(boundary)
+}
+
 func (fb *DefaultFormBuilder) WriteField(fieldname, value string) error {
 	return fb.writer.WriteField(fieldname, value)
 }
@@ -20,6 +20,13 @@ type Request struct {
 	ExtraQuery   map[string]string
 }
 
+// BodyWriter is a request body which encodes itself into the request buffer, for
+// large bodies which should not be marshaled into an intermediate slice first.
+// It is ignored when the request has ExtraBody fields to merge.
+type BodyWriter interface {
+	WriteBody(buf *bytes.Buffer) error
+}
+
 type RequestBuilder interface {
 	Build(ctx context.Context, request *Request) (*http.Request, error)
 }
@@ -41,7 +48,16 @@ func (b *HTTPRequestBuilder) Build(
 	var bodyReader io.Reader
 	if request.Body != nil {
 		if v, ok := request.Body.(io.Reader); ok {
-			bodyReader = v
+			bodyReader, err = replayableBody(v)
+			if err != nil {
+				return
+			}
+		} else if v, ok := request.Body.(BodyWriter); ok && request.ExtraBody == nil {
+			buf := new(bytes.Buffer)
+			if err = v.WriteBody(buf); err != nil {
+				return
+			}
+			bodyReader = buf
 		} else {
 			var reqBytes []byte
 			reqBytes, err = b.marshaller.Marshal(request.Body)
@@ -94,3 +110,17 @@ func (b *HTTPRequestBuilder) Build(
 
 	return
 }
+
+// replayableBody buffers readers whose content net/http cannot read again, so that
+// requests can be retried.
+func replayableBody(body io.Reader) (io.Reader, error) {
+	switch body.(type) {
+	case *bytes.Buffer, *bytes.Reader, *strings.Reader:
+		return body, nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
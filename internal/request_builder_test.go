@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -84,3 +86,65 @@ func TestRequestBuilderReturnsRequestWhenRequestOfArgsIsNil(t *testing.T) {
 		t.Errorf("Build() got = %v, want %v", got, want)
 	}
 }
+
+func TestRequestBuilderBuffersReaderBodies(t *testing.T) {
+	b := NewRequestBuilder()
+	got, err := b.Build(context.Background(), &Request{
+		Method: http.MethodPost,
+		URL:    "/foo",
+		Body:   io.MultiReader(strings.NewReader("hello")),
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got.GetBody == nil {
+		t.Fatal("expected the body to be replayable")
+	}
+	for i := 0; i < 2; i++ {
+		body, _ := got.GetBody()
+		data, _ := io.ReadAll(body)
+		if string(data) != "hello" {
+			t.Fatalf("GetBody() read %q, want %q", data, "hello")
+		}
+	}
+}
+
+type testBodyWriter struct{}
+
+func (testBodyWriter) WriteBody(buf *bytes.Buffer) error {
+	_, err := buf.WriteString(`{"written":true}`)
+	return err
+}
+
+func (testBodyWriter) MarshalJSON() ([]byte, error) {
+	return []byte(`{"marshaled":true}`), nil
+}
+
+func TestRequestBuilderWritesBodyWriters(t *testing.T) {
+	b := NewRequestBuilder()
+	got, err := b.Build(context.Background(), &Request{Method: http.MethodPost, URL: "/foo", Body: testBodyWriter{}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got.GetBody == nil {
+		t.Fatal("expected the body to be replayable")
+	}
+	data, _ := io.ReadAll(got.Body)
+	if string(data) != `{"written":true}` {
+		t.Fatalf("expected the body to be written by WriteBody, got %s", data)
+	}
+
+	got, err = b.Build(context.Background(), &Request{
+		Method:    http.MethodPost,
+		URL:       "/foo",
+		Body:      testBodyWriter{},
+		ExtraBody: map[string]any{"extra": 1},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	data, _ = io.ReadAll(got.Body)
+	if string(data) != `{"extra":1,"marshaled":true}` {
+		t.Fatalf("expected extra body fields to be merged into the marshaled body, got %s", data)
+	}
+}
@@ -0,0 +1,77 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// messageImageTokens is the cost of a 1024x1024 image at high or auto detail.
+	messageImageTokens = 765
+	// messageLowDetailImageTokens is the cost of an image at low detail, whatever its size.
+	messageLowDetailImageTokens = 85
+)
+
+var ErrTokenizerNotRegistered = errors.New("no tokenizer registered for model")
+
+var tokenizers = struct {
+	sync.RWMutex
+	byModel map[string]Tokenizer
+}{byModel: map[string]Tokenizer{}}
+
+// RegisterTokenizer sets the tokenizer MessageRequest.EstimateTokens uses for model.
+// A tokenizer registered for the empty model is used for models without their own.
+// A nil tokenizer removes the registration.
+func RegisterTokenizer(model string, tokenizer Tokenizer) {
+	tokenizers.Lock()
+	defer tokenizers.Unlock()
+	if tokenizer == nil {
+		delete(tokenizers.byModel, model)
+		return
+	}
+	tokenizers.byModel[model] = tokenizer
+}
+
+func registeredTokenizer(model string) (tokenizer Tokenizer, ok bool) {
+	tokenizers.RLock()
+	defer tokenizers.RUnlock()
+	if tokenizer, ok = tokenizers.byModel[model]; !ok {
+		tokenizer, ok = tokenizers.byModel[""]
+	}
+	return
+}
+
+// EstimateTokens returns a rough token count of the request content for model: the
+// tokens of Content and of the text parts, encoded with the tokenizer registered with
+// RegisterTokenizer, plus a fixed cost per image_file and image_url part, 765 tokens, or
+// 85 for an image_url at low detail. Message formatting overhead and video parts are not
+// counted. It fails with ErrTokenizerNotRegistered when no tokenizer is registered.
+func (r MessageRequest) EstimateTokens(model string) (tokens int, err error) {
+	tokenizer, ok := registeredTokenizer(model)
+	if !ok {
+		err = fmt.Errorf("%w: %s", ErrTokenizerNotRegistered, model)
+		return
+	}
+
+	if r.Content != "" {
+		tokens += len(tokenizer.Encode(r.Content))
+	}
+	for _, part := range r.ContentParts {
+		switch part.Type {
+		case MessageContentTypeText:
+			if part.Text != nil {
+				tokens += len(tokenizer.Encode(part.Text.Value))
+			}
+		case MessageContentTypeImageFile:
+			tokens += messageImageTokens
+		case MessageContentTypeImageURL:
+			if part.ImageURL != nil && part.ImageURL.Detail == ImageURLDetailLow {
+				tokens += messageLowDetailImageTokens
+			} else {
+				tokens += messageImageTokens
+			}
+		}
+	}
+	return
+}
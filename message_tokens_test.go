@@ -0,0 +1,46 @@
+package openai_test
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestMessageRequestEstimateTokens(t *testing.T) {
+	const model = "test-estimate-model"
+	request := openai.MessageRequest{
+		Role: string(openai.ThreadMessageRoleUser),
+		ContentParts: []openai.MessageContent{
+			openai.NewTextMessageContent("compare these two images"),
+			openai.NewImageFileMessageContent("file_abc123"),
+			openai.NewImageURLMessageContent("https://example.com/cat.png", openai.ImageURLDetailLow),
+			openai.NewTextMessageContent("please"),
+		},
+	}
+
+	_, err := request.EstimateTokens(model)
+	checks.ErrorIs(t, err, openai.ErrTokenizerNotRegistered, "expected an error without a tokenizer")
+
+	openai.RegisterTokenizer(model, &wordTokenizer{})
+	defer openai.RegisterTokenizer(model, nil)
+
+	tokens, err := request.EstimateTokens(model)
+	checks.NoError(t, err, "EstimateTokens error")
+	if want := 4 + 765 + 85 + 1; tokens != want {
+		t.Fatalf("expected %d tokens, got %d", want, tokens)
+	}
+
+	tokens, err = openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "hello there").EstimateTokens(model)
+	checks.NoError(t, err, "EstimateTokens error")
+	if tokens != 2 {
+		t.Fatalf("expected 2 tokens for the string content, got %d", tokens)
+	}
+
+	_, err = request.EstimateTokens("test-other-model")
+	checks.ErrorIs(t, err, openai.ErrTokenizerNotRegistered, "expected other models to have no tokenizer")
+	openai.RegisterTokenizer("", &wordTokenizer{})
+	defer openai.RegisterTokenizer("", nil)
+	_, err = request.EstimateTokens("test-other-model")
+	checks.NoError(t, err, "expected the default tokenizer to be used")
+}
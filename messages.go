@@ -1,32 +1,256 @@
 package openai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"time"
 )
 
 const (
 	messagesSuffix = "messages"
+
+	messageObjectType        = "thread.message"
+	messageFileObjectType    = "thread.message.file"
+	messageDeletedObjectType = "thread.message.deleted"
+	listObjectType           = "list"
+
+	// messagesListMaxLimit is the largest page size accepted by the list messages endpoint.
+	messagesListMaxLimit = 100
+)
+
+var (
+	ErrMessageRequestInvalidRole  = errors.New("message role must be either user or assistant")
+	ErrMessageRequestEmptyContent = errors.New("message request requires content or attachments")
+	ErrMessageContentInvalid      = errors.New("invalid message content")
+	ErrMessageFileNotFound        = errors.New("message has no such file")
+	ErrVideoFieldsMisused         = errors.New("can't use both URL and ImageURLs properties of Video simultaneously")
+	ErrImageTooLarge              = errors.New("image exceeds the upload size limit")
+	ErrImageTypeUnsupported       = errors.New("image must be a png, jpeg, gif or webp image")
+	ErrThreadNotFound             = errors.New("thread not found")
 )
 
+// DefaultMaxImageUploadBytes is the default limit of ClientConfig.MaxImageUploadBytes,
+// the size limit of images uploaded for vision.
+const DefaultMaxImageUploadBytes = 20 * 1024 * 1024
+
 type Message struct {
-	ID          string           `json:"id"`
-	Object      string           `json:"object"`
-	CreatedAt   int              `json:"created_at"`
-	ThreadID    string           `json:"thread_id"`
-	Role        string           `json:"role"`
-	Content     []MessageContent `json:"content"`
-	FileIds     []string         `json:"file_ids"` //nolint:revive //backwards-compatibility
-	AssistantID *string          `json:"assistant_id,omitempty"`
-	RunID       *string          `json:"run_id,omitempty"`
-	Metadata    map[string]any   `json:"metadata"`
+	ID          string             `json:"id"`
+	Object      string             `json:"object"`
+	CreatedAt   int                `json:"created_at"`
+	ThreadID    string             `json:"thread_id"`
+	Role        string             `json:"role"`
+	Content     []MessageContent   `json:"content"`
+	FileIds     []string           `json:"file_ids"` //nolint:revive //backwards-compatibility
+	Attachments []ThreadAttachment `json:"attachments,omitempty"`
+	AssistantID *string            `json:"assistant_id,omitempty"`
+	RunID       *string            `json:"run_id,omitempty"`
+	Metadata    map[string]any     `json:"metadata"`
+
+	// Status is one of the MessageStatus values. An assistant message which was cut
+	// short, for instance by the token limit of its run, is incomplete and sets
+	// IncompleteAt and IncompleteDetails.
+	Status            string                    `json:"status,omitempty"`
+	CompletedAt       *int                      `json:"completed_at,omitempty"`
+	IncompleteAt      *int                      `json:"incomplete_at,omitempty"`
+	IncompleteDetails *MessageIncompleteDetails `json:"incomplete_details,omitempty"`
 
 	httpHeader
 }
 
+// UnmarshalJSON also accepts content sent as a plain string, as some OpenAI compatible
+// servers do, and decodes it into a single text part. An empty string decodes into no
+// parts. As the message is decoded on its own, the client decodes its metadata again
+// for ClientConfig.UseJSONNumber, see decodeMessageMetadataNumbers.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type alias Message
+	decoded := struct {
+		*alias
+		Content json.RawMessage `json:"content"`
+	}{alias: (*alias)(m)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Content == nil {
+		return nil
+	}
+	if decoded.Content[0] != '"' {
+		return json.Unmarshal(decoded.Content, &m.Content)
+	}
+
+	var text string
+	if err := json.Unmarshal(decoded.Content, &text); err != nil {
+		return err
+	}
+	m.Content = nil
+	if text != "" {
+		m.Content = []MessageContent{{
+			Type: MessageContentTypeText,
+			Text: &MessageText{Value: text, Annotations: []any{}},
+		}}
+	}
+	return nil
+}
+
+// decodeMessageMetadataNumbers decodes the metadata of the messages of body again,
+// with json.Number values, into v when it is a message or a list of messages.
+// Message.UnmarshalJSON is out of reach of the UseNumber setting of the decoder of
+// the whole response.
+func decodeMessageMetadataNumbers(body []byte, v any) error {
+	type metadataOnly struct {
+		Metadata map[string]any `json:"metadata"`
+	}
+	switch v := v.(type) {
+	case *Message:
+		var decoded metadataOnly
+		if err := decodeResponse(bytes.NewReader(body), &decoded, true); err != nil {
+			return err
+		}
+		v.Metadata = decoded.Metadata
+	case *MessagesList:
+		var decoded struct {
+			Data []metadataOnly `json:"data"`
+		}
+		if err := decodeResponse(bytes.NewReader(body), &decoded, true); err != nil {
+			return err
+		}
+		for i := range v.Messages {
+			if i < len(decoded.Data) {
+				v.Messages[i].Metadata = decoded.Data[i].Metadata
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	MessageStatusInProgress = "in_progress"
+	MessageStatusIncomplete = "incomplete"
+	MessageStatusCompleted  = "completed"
+)
+
+// MessageIncompleteDetails explains why a message is incomplete.
+type MessageIncompleteDetails struct {
+	// Reason is content_filter, max_tokens, run_cancelled, run_expired or run_failed.
+	Reason string `json:"reason"`
+}
+
+// CreatedTime returns CreatedAt as a time.Time.
+func (m Message) CreatedTime() time.Time {
+	return time.Unix(int64(m.CreatedAt), 0)
+}
+
+// RunIDValue returns the ID of the run which created the message, and false for a
+// message which was not created by a run.
+func (m Message) RunIDValue() (string, bool) {
+	if m.RunID == nil || *m.RunID == "" {
+		return "", false
+	}
+	return *m.RunID, true
+}
+
+// MetadataString returns the metadata value of key as a string, normalizing values
+// which were decoded as numbers or booleans, so an integer-looking value reads back
+// as "42" rather than "42.000000". It returns false when the key is not set.
+func (m Message) MetadataString(key string) (string, bool) {
+	return metadataString(m.Metadata, key)
+}
+
+// TextContent returns the values of the text parts of the message concatenated in
+// order, or "" when the message has no text.
+func (m Message) TextContent() string {
+	var text strings.Builder
+	for _, part := range m.Content {
+		if part.Type == MessageContentTypeText && part.Text != nil {
+			text.WriteString(part.Text.Value)
+		}
+	}
+	return text.String()
+}
+
+// RefusalText returns the values of the refusal parts of the message concatenated in
+// order. ok reports whether the message has a refusal part, so a refusal can be told
+// apart from an empty answer.
+func (m Message) RefusalText() (refusal string, ok bool) {
+	var text strings.Builder
+	for _, part := range m.Content {
+		if part.Type == MessageContentTypeRefusal && part.Refusal != nil {
+			text.WriteString(*part.Refusal)
+			ok = true
+		}
+	}
+	refusal = text.String()
+	return
+}
+
+// ReasoningSummary joins the summary texts of the reasoning parts of the message with
+// blank lines. It is empty unless the message comes from a reasoning model which
+// returned a summary.
+func (m Message) ReasoningSummary() string {
+	var texts []string
+	for _, part := range m.Content {
+		if part.Type == MessageContentTypeReasoning && part.Reasoning != nil {
+			for _, summary := range part.Reasoning.Summary {
+				texts = append(texts, summary.Text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// ImageFileIDs returns the file IDs of the image_file parts of the message in order.
+func (m Message) ImageFileIDs() (fileIDs []string) {
+	for _, part := range m.Content {
+		if part.Type == MessageContentTypeImageFile && part.ImageFile != nil {
+			fileIDs = append(fileIDs, part.ImageFile.FileID)
+		}
+	}
+	return
+}
+
+// ImageURLs returns the URLs of the image_url parts of the message in order.
+func (m Message) ImageURLs() (urls []string) {
+	for _, part := range m.Content {
+		if part.Type == MessageContentTypeImageURL && part.ImageURL != nil {
+			urls = append(urls, part.ImageURL.URL)
+		}
+	}
+	return
+}
+
+// Files returns the files of the message, its attachments followed by the files of its
+// image_file parts, as the v1 message files endpoints listed them. Each file is
+// reported once and has the creation time of the message.
+func (m Message) Files() (files []MessageFile) {
+	seen := make(map[string]bool)
+	add := func(fileID string) {
+		if fileID == "" || seen[fileID] {
+			return
+		}
+		seen[fileID] = true
+		files = append(files, MessageFile{
+			ID:        fileID,
+			Object:    "thread.message.file",
+			CreatedAt: m.CreatedAt,
+			MessageID: m.ID,
+		})
+	}
+	for _, attachment := range m.Attachments {
+		add(attachment.FileID)
+	}
+	for _, fileID := range m.ImageFileIDs() {
+		add(fileID)
+	}
+	return
+}
+
 type MessagesList struct {
 	Messages []Message `json:"data"`
 
@@ -38,6 +262,56 @@ type MessagesList struct {
 	httpHeader
 }
 
+// NextPageParams returns params moved to the page following the list, which was
+// fetched with params, so the other filters are kept. It returns false when the list
+// is the last page.
+func (l MessagesList) NextPageParams(params ListMessagesParams) (ListMessagesParams, bool) {
+	after, ok := l.Page().NextAfter()
+	if !ok {
+		return params, false
+	}
+	params.After, params.Before = after, ""
+	return params, true
+}
+
+// PrevPageParams returns params moved to the page preceding the list, which was
+// fetched with params, so the other filters are kept. It returns false when the list
+// is empty or was fetched without a cursor, as the first page.
+func (l MessagesList) PrevPageParams(params ListMessagesParams) (ListMessagesParams, bool) {
+	before, ok := l.Page().PrevBefore()
+	if !ok || (params.After == "" && params.Before == "") {
+		return params, false
+	}
+	params.After, params.Before = "", before
+	return params, true
+}
+
+// SortedByCreatedAt returns a copy of the messages ordered by CreatedAt.
+// Messages created in the same second keep their relative order from the list.
+func (l MessagesList) SortedByCreatedAt(ascending bool) []Message {
+	messages := make([]Message, len(l.Messages))
+	copy(messages, l.Messages)
+	sort.SliceStable(messages, func(i, j int) bool {
+		if ascending {
+			return messages[i].CreatedAt < messages[j].CreatedAt
+		}
+		return messages[i].CreatedAt > messages[j].CreatedAt
+	})
+	return messages
+}
+
+const (
+	MessageContentTypeText      = "text"
+	MessageContentTypeImageFile = "image_file"
+	MessageContentTypeImageURL  = "image_url"
+	MessageContentTypeVideoURL  = "video_url"
+	MessageContentTypeVideo     = "video"
+	MessageContentTypeRefusal   = "refusal"
+	MessageContentTypeReasoning = "reasoning"
+)
+
+// MessageContent is a part of the content of a message. Type is one of the
+// MessageContentType values and selects the populated field.
 type MessageContent struct {
 	Type      string       `json:"type"`
 	Text      *MessageText `json:"text,omitempty"`
@@ -45,10 +319,260 @@ type MessageContent struct {
 	ImageURL  *ImageURL    `json:"image_url,omitempty"`
 	VideoURL  *VideoURL    `json:"video_url,omitempty"`
 	Video     *Video       `json:"video,omitempty"`
+	// Refusal is the text of a refusal part, returned by the model instead of an answer.
+	Refusal *string `json:"refusal,omitempty"`
+	// Reasoning is the reasoning summary of a reasoning model, set on reasoning parts.
+	Reasoning *MessageReasoning `json:"reasoning,omitempty"`
+
+	// Raw is the part as received when its Type is not one this package models, such
+	// as audio, so callers can decode it themselves.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps parts of unknown types in Raw. Unknown parts whose fields do
+// not match the known variants are decoded into Type and Raw only.
+func (m *MessageContent) UnmarshalJSON(data []byte) error {
+	type alias MessageContent
+	var decoded alias
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		var head struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(data, &head) != nil || isKnownMessageContentType(head.Type) {
+			return err
+		}
+		decoded = alias{Type: head.Type}
+	}
+	*m = MessageContent(decoded)
+	if !isKnownMessageContentType(m.Type) {
+		m.Raw = append(json.RawMessage(nil), data...)
+	}
+	return nil
+}
+
+func isKnownMessageContentType(contentType string) bool {
+	switch contentType {
+	case MessageContentTypeText, MessageContentTypeImageFile, MessageContentTypeImageURL,
+		MessageContentTypeVideoURL, MessageContentTypeVideo, MessageContentTypeRefusal,
+		MessageContentTypeReasoning:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewTextMessageContent returns a text content part.
+func NewTextMessageContent(text string) MessageContent {
+	return MessageContent{Type: MessageContentTypeText, Text: &MessageText{Value: text}}
+}
+
+// NewImageFileMessageContent returns an image_file content part referring to an
+// uploaded file.
+func NewImageFileMessageContent(fileID string) MessageContent {
+	return MessageContent{Type: MessageContentTypeImageFile, ImageFile: &ImageFile{FileID: fileID}}
+}
+
+// NewImageURLMessageContent returns an image_url content part. An empty detail lets
+// the API choose.
+func NewImageURLMessageContent(imageURL string, detail ImageURLDetail) MessageContent {
+	return MessageContent{Type: MessageContentTypeImageURL, ImageURL: &ImageURL{URL: imageURL, Detail: detail}}
+}
+
+// Validate checks the image and video parts of the content: a video must not set both
+// URL and ImageURLs, the Fps of a video_url must not be negative, and the Detail of an
+// image_url or video_url, when set, must be one of auto, low and high. Errors wrap
+// ErrMessageContentInvalid.
+func (m MessageContent) Validate() error {
+	if m.Video != nil {
+		if err := m.Video.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrMessageContentInvalid, err)
+		}
+	}
+	if m.ImageURL != nil && !m.ImageURL.Detail.valid() {
+		return fmt.Errorf("%w: image detail must be auto, low or high, got %q",
+			ErrMessageContentInvalid, m.ImageURL.Detail)
+	}
+	if m.VideoURL == nil {
+		return nil
+	}
+	if m.VideoURL.Fps < 0 {
+		return fmt.Errorf("%w: negative video fps %v", ErrMessageContentInvalid, m.VideoURL.Fps)
+	}
+	if !m.VideoURL.Detail.valid() {
+		return fmt.Errorf("%w: video detail must be auto, low or high, got %q",
+			ErrMessageContentInvalid, m.VideoURL.Detail)
+	}
+	return nil
 }
+
+const MessageReasoningSummaryTypeText = "summary_text"
+
+// MessageReasoning is the content of a reasoning part.
+type MessageReasoning struct {
+	Summary []MessageReasoningSummary `json:"summary"`
+
+	// Raw is the reasoning object as received, so fields this package does not model,
+	// such as encrypted content, can be decoded by callers.
+	Raw json.RawMessage `json:"-"`
+}
+
+// MessageReasoningSummary is a part of a reasoning summary, of type summary_text.
+type MessageReasoningSummary struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// UnmarshalJSON keeps the reasoning object in Raw as well.
+func (r *MessageReasoning) UnmarshalJSON(data []byte) error {
+	type alias MessageReasoning
+	var decoded alias
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = MessageReasoning(decoded)
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
 type MessageText struct {
 	Value       string `json:"value"`
 	Annotations []any  `json:"annotations"`
+	// ParsedAnnotations holds Annotations decoded into MessageAnnotation values when the
+	// text is unmarshaled from a response.
+	ParsedAnnotations []MessageAnnotation `json:"-"`
+	// FileSearchResults holds the cited chunks, populated only when the message
+	// is retrieved with the matching include expansion.
+	FileSearchResults []FileSearchResult `json:"file_search_results,omitempty"`
+}
+
+// UnmarshalJSON decodes Annotations into ParsedAnnotations as well. It also accepts
+// the plain string text of a message request part.
+func (t *MessageText) UnmarshalJSON(data []byte) error {
+	var value string
+	if json.Unmarshal(data, &value) == nil {
+		*t = MessageText{Value: value}
+		return nil
+	}
+	type alias MessageText
+	if err := json.Unmarshal(data, (*alias)(t)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Annotations []json.RawMessage `json:"annotations"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	t.ParsedAnnotations = nil
+	for _, annotation := range raw.Annotations {
+		var parsed MessageAnnotation
+		if json.Unmarshal(annotation, &parsed) != nil {
+			parsed = MessageAnnotation{Raw: annotation}
+		}
+		t.ParsedAnnotations = append(t.ParsedAnnotations, parsed)
+	}
+	return nil
+}
+
+const (
+	MessageAnnotationTypeFileCitation = "file_citation"
+	MessageAnnotationTypeFilePath     = "file_path"
+	MessageAnnotationTypeURLCitation  = "url_citation"
+)
+
+// MessageAnnotation is a citation or file reference within the text of a message.
+// Type is one of the MessageAnnotationType values and selects the populated variant.
+// Annotations of other types only carry their Type, the common fields and Raw.
+type MessageAnnotation struct {
+	Type       string `json:"type"`
+	Text       string `json:"text"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
+	// Index is the position of the annotation, set only in message deltas.
+	Index *int `json:"index,omitempty"`
+
+	FileCitation *MessageFileCitation `json:"file_citation,omitempty"`
+	FilePath     *MessageFilePath     `json:"file_path,omitempty"`
+	URLCitation  *MessageURLCitation  `json:"url_citation,omitempty"`
+
+	// Raw is the annotation as received, for types this package does not model.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps the received annotation in Raw. Annotations of unknown types
+// whose fields do not match the known variants are decoded into Type and Raw only.
+func (a *MessageAnnotation) UnmarshalJSON(data []byte) error {
+	type alias MessageAnnotation
+	var decoded alias
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		var head struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(data, &head) != nil {
+			return err
+		}
+		decoded = alias{Type: head.Type}
+	}
+	*a = MessageAnnotation(decoded)
+	a.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+type MessageFileCitation struct {
+	FileID string `json:"file_id"`
+	Quote  string `json:"quote,omitempty"`
+}
+
+type MessageFilePath struct {
+	FileID string `json:"file_id"`
+}
+
+type MessageURLCitation struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+// source returns the file ID or URL the annotation refers to.
+func (a MessageAnnotation) source() string {
+	switch {
+	case a.FileCitation != nil:
+		return a.FileCitation.FileID
+	case a.FilePath != nil:
+		return a.FilePath.FileID
+	case a.URLCitation != nil:
+		return a.URLCitation.URL
+	default:
+		return ""
+	}
+}
+
+// TypedAnnotations returns ParsedAnnotations, or decodes Annotations into MessageAnnotation
+// values when the text was not unmarshaled from a response.
+func (t MessageText) TypedAnnotations() (annotations []MessageAnnotation, err error) {
+	if t.ParsedAnnotations != nil || len(t.Annotations) == 0 {
+		return t.ParsedAnnotations, nil
+	}
+	data, err := json.Marshal(t.Annotations)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &annotations)
+	return
+}
+
+// FileSearchResult is a chunk found by the file search tool.
+type FileSearchResult struct {
+	FileID   string                    `json:"file_id"`
+	FileName string                    `json:"file_name"`
+	Score    float64                   `json:"score"`
+	Content  []FileSearchResultContent `json:"content,omitempty"`
+}
+
+type FileSearchResultContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
 }
 
 type ImageFile struct {
@@ -56,28 +580,43 @@ type ImageFile struct {
 }
 
 type ImageURL struct {
-	URL    string `json:"url"`
-	Detail string `json:"detail,omitempty"`
+	URL    string         `json:"url"`
+	Detail ImageURLDetail `json:"detail,omitempty"`
 }
 
 type VideoURL struct {
-	URL    string  `json:"url"`
-	Fps    float64 `json:"fps,omitempty"`
-	Detail string  `json:"detail,omitempty"`
+	URL    string         `json:"url"`
+	Fps    float64        `json:"fps,omitempty"`
+	Detail ImageURLDetail `json:"detail,omitempty"`
 }
 
+// Video is a video given either by the URL of a video file or by the URLs of its
+// frames as images. Only one of URL and ImageURLs may be set.
 type Video struct {
 	URL       string   `json:"-"`
 	ImageURLs []string `json:"-"`
 }
 
+// Validate returns ErrVideoFieldsMisused when both URL and ImageURLs are set.
+func (v *Video) Validate() error {
+	if v.URL != "" && len(v.ImageURLs) > 0 {
+		return ErrVideoFieldsMisused
+	}
+	return nil
+}
+
+// MarshalJSON sends URL as a string and ImageURLs as an array, and null when neither
+// is set.
 func (v *Video) MarshalJSON() ([]byte, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
 	if len(v.ImageURLs) > 0 {
 		return json.Marshal(v.ImageURLs)
 	} else if v.URL != "" {
 		return json.Marshal(v.URL)
 	}
-	return nil, nil
+	return []byte("null"), nil
 }
 
 func (v *Video) UnmarshalJSON(data []byte) error {
@@ -86,6 +625,7 @@ func (v *Video) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	switch t := i.(type) {
+	case nil:
 	case string:
 		v.URL = t
 	case []any:
@@ -103,13 +643,157 @@ func (v *Video) UnmarshalJSON(data []byte) error {
 }
 
 type MessageRequest struct {
-	Role        string             `json:"role"`
-	Content     string             `json:"content"`
-	FileIds     []string           `json:"file_ids,omitempty"` //nolint:revive // backwards-compatibility
+	// Role is one of the ThreadMessageRole values. Messages with any other role are
+	// rejected before they are sent, as the API only creates user and assistant messages.
+	Role string `json:"role"`
+	// Content is omitted from the request when it is empty and Attachments is set, to
+	// create an attachment-only message.
+	Content string `json:"content"`
+	// ContentParts replaces Content to send text, image_file, image_url and video_url
+	// parts. Only one of Content and ContentParts may be set.
+	ContentParts []MessageContent `json:"-"`
+	// FileIds is the v1 way to attach files. On assistants v2, which rejects the field,
+	// the file ids are sent as attachments appended to Attachments, routed to the
+	// ClientConfig.FileIDsAttachmentTool, and file_ids is omitted. A v1 client, or
+	// one with ClientConfig.LegacyFileIDs set, sends them unchanged.
+	FileIds []string `json:"file_ids,omitempty"` //nolint:revive // backwards-compatibility
+	// Metadata is encoded with its keys sorted, nested maps included, so the same
+	// request always produces the same body.
 	Metadata    map[string]any     `json:"metadata,omitempty"`
 	Attachments []ThreadAttachment `json:"attachments,omitempty"`
 }
 
+// NewMessageRequest returns a request for a message with the given role, user or
+// assistant, and text content. The With methods add the other fields.
+func NewMessageRequest(role, content string) MessageRequest {
+	return MessageRequest{Role: role, Content: content}
+}
+
+// WithAttachment returns a copy of the request with attachment appended.
+func (r MessageRequest) WithAttachment(attachment ThreadAttachment) MessageRequest {
+	r.Attachments = append(r.Attachments[:len(r.Attachments):len(r.Attachments)], attachment)
+	return r
+}
+
+// WithMetadata returns a copy of the request with the metadata key set to value.
+func (r MessageRequest) WithMetadata(key string, value any) MessageRequest {
+	metadata := make(map[string]any, len(r.Metadata)+1)
+	for k, v := range r.Metadata {
+		metadata[k] = v
+	}
+	metadata[key] = value
+	r.Metadata = metadata
+	return r
+}
+
+// WithFileIDs returns a copy of the request with fileIDs appended to FileIds, which
+// the client converts to attachments unless ClientConfig.LegacyFileIDs is set.
+func (r MessageRequest) WithFileIDs(fileIDs ...string) MessageRequest {
+	r.FileIds = append(r.FileIds[:len(r.FileIds):len(r.FileIds)], fileIDs...)
+	return r
+}
+
+// MarshalJSON sends Content, or a single text part, as a string and other ContentParts
+// as an array of parts.
+func (r MessageRequest) MarshalJSON() ([]byte, error) {
+	content, err := messageRequestContent(r.Content, r.ContentParts)
+	if err != nil {
+		return nil, err
+	}
+	if content == "" && len(r.Attachments) > 0 {
+		content = nil
+	}
+	type alias MessageRequest
+	return json.Marshal(struct {
+		alias
+		Content any `json:"content,omitempty"`
+	}{alias(r), content})
+}
+
+// messageRequestPart is a MessageContent as the create endpoints expect it, with the
+// text of a text part given as a plain string.
+type messageRequestPart struct {
+	Type      string     `json:"type"`
+	Text      string     `json:"text,omitempty"`
+	ImageFile *ImageFile `json:"image_file,omitempty"`
+	ImageURL  *ImageURL  `json:"image_url,omitempty"`
+	VideoURL  *VideoURL  `json:"video_url,omitempty"`
+	Video     *Video     `json:"video,omitempty"`
+}
+
+// messageRequestContent returns the content field of a message request: the string
+// content or the text of a single text part, or otherwise the array of parts.
+func messageRequestContent(content string, parts []MessageContent) (any, error) {
+	if content != "" && len(parts) > 0 {
+		return nil, ErrContentFieldsMisused
+	}
+	if len(parts) == 0 {
+		return content, nil
+	}
+	if len(parts) == 1 && parts[0].Type == MessageContentTypeText && parts[0].Text != nil {
+		return parts[0].Text.Value, nil
+	}
+
+	requestParts := make([]messageRequestPart, len(parts))
+	for i, part := range parts {
+		requestParts[i] = messageRequestPart{
+			Type:      part.Type,
+			ImageFile: part.ImageFile,
+			ImageURL:  part.ImageURL,
+			VideoURL:  part.VideoURL,
+			Video:     part.Video,
+		}
+		if part.Text != nil {
+			requestParts[i].Text = part.Text.Value
+		}
+	}
+	return requestParts, nil
+}
+
+// migrateFileIDs converts the deprecated FileIds into Attachments using the
+// configured FileIDsAttachmentTool, unless the client uses assistants v1, which has
+// no attachments, or LegacyFileIDs is set. A deprecation
+// warning is logged the first time a client converts file ids.
+func (c *Client) migrateFileIDs(request MessageRequest) MessageRequest {
+	if len(request.FileIds) == 0 || c.config.LegacyFileIDs || c.config.AssistantVersion == assistantVersionV1 {
+		return request
+	}
+
+	c.fileIDsWarning.Do(func() {
+		if c.config.Logger != nil {
+			c.config.Logger.Printf("openai: MessageRequest.FileIds is deprecated, " +
+				"the file ids have been converted to attachments; use Attachments instead")
+		}
+	})
+
+	tool := c.config.FileIDsAttachmentTool
+	if tool == "" {
+		tool = AssistantToolTypeFileSearch
+	}
+	attachments := make([]ThreadAttachment, 0, len(request.Attachments)+len(request.FileIds))
+	attachments = append(attachments, request.Attachments...)
+	for _, fileID := range request.FileIds {
+		attachments = append(attachments, NewThreadAttachment(fileID, tool))
+	}
+	request.Attachments = attachments
+	request.FileIds = nil
+	return request
+}
+
+// validate checks the request carries a supported role and something to send: content,
+// content parts, attachments or file ids.
+func (r MessageRequest) validate() error {
+	switch ThreadMessageRole(r.Role) {
+	case ThreadMessageRoleUser, ThreadMessageRoleAssistant:
+	default:
+		return fmt.Errorf("%w, got %q", ErrMessageRequestInvalidRole, r.Role)
+	}
+	if r.Content == "" && len(r.ContentParts) == 0 && len(r.Attachments) == 0 && len(r.FileIds) == 0 {
+		return ErrMessageRequestEmptyContent
+	}
+	return nil
+}
+
 type MessageFile struct {
 	ID        string `json:"id"`
 	Object    string `json:"object"`
@@ -119,12 +803,36 @@ type MessageFile struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (f MessageFile) CreatedTime() time.Time {
+	return time.Unix(int64(f.CreatedAt), 0)
+}
+
 type MessageFilesList struct {
 	MessageFiles []MessageFile `json:"data"`
 
+	Object  string  `json:"object"`
+	FirstID *string `json:"first_id"`
+	LastID  *string `json:"last_id"`
+	HasMore bool    `json:"has_more"`
+
 	httpHeader
 }
 
+// NextPageParams returns params moved to the page following the list, which was
+// fetched with params. It returns false when the list is the last page.
+func (l MessageFilesList) NextPageParams(params ListMessageFilesParams) (ListMessageFilesParams, bool) {
+	after, ok := l.Page().NextAfter()
+	if !ok {
+		return params, false
+	}
+	params.After, params.Before = after, ""
+	return params, true
+}
+
+// MessageDeletionStatus is the result of DeleteMessage. A message which does not exist
+// fails with an *APIError, so Deleted is false without an error only when the API
+// reports a message which was already deleted.
 type MessageDeletionStatus struct {
 	ID      string `json:"id"`
 	Object  string `json:"object"`
@@ -133,68 +841,646 @@ type MessageDeletionStatus struct {
 	httpHeader
 }
 
+func (m Message) objectType() (string, string) { return m.Object, messageObjectType }
+
+func (l MessagesList) objectType() (string, string) { return l.Object, listObjectType }
+
+func (f MessageFile) objectType() (string, string) { return f.Object, messageFileObjectType }
+
+func (l MessageFilesList) objectType() (string, string) { return l.Object, listObjectType }
+
+func (s MessageDeletionStatus) objectType() (string, string) {
+	return s.Object, messageDeletedObjectType
+}
+
+// validateContentParts validates every content part when ValidateMessageContent is set.
+func (c *Client) validateContentParts(parts []MessageContent) error {
+	if !c.config.ValidateMessageContent {
+		return nil
+	}
+	for i, part := range parts {
+		if err := part.Validate(); err != nil {
+			return fmt.Errorf("content parts[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // CreateMessage creates a new message.
-func (c *Client) CreateMessage(ctx context.Context, threadID string, request MessageRequest) (msg Message, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/%s", threadID, messagesSuffix)
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+func (c *Client) CreateMessage(
+	ctx context.Context,
+	threadID string,
+	request MessageRequest,
+	opts ...RequestOption,
+) (msg Message, err error) {
+	return c.CreateMessageWithIdempotencyKey(ctx, threadID, request, "", opts...)
+}
+
+// CreateMessageWithIdempotencyKey creates a new message, sending key as the
+// Idempotency-Key header so the API creates it only once when the call is retried with
+// the same key. Automatic retries configured with ClientConfig.Retry reuse the key.
+// An empty key behaves like CreateMessage.
+func (c *Client) CreateMessageWithIdempotencyKey(
+	ctx context.Context,
+	threadID string,
+	request MessageRequest,
+	key string,
+	opts ...RequestOption,
+) (msg Message, err error) {
+	if request, err = c.prepareMessageRequest(request); err != nil {
+		return
+	}
+
+	urlSuffix, urlOption, extra := applyRequestOptions(fmt.Sprintf("/threads/%s/%s", threadID, messagesSuffix), opts)
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix, urlOption), withBody(messageRequestBody{request}),
+		withBetaAssistantVersion(c.config.AssistantVersion), withIdempotencyKey(key), extra)
 	if err != nil {
 		return
 	}
+	if err = gzipRequestBody(req, c.config.GzipMessageRequestsOver); err != nil {
+		return
+	}
 
 	err = c.sendRequest(req, &msg)
 	return
 }
 
+// CreateMessageWithImage uploads the image read from image with the vision purpose and
+// creates a user message made of text, when not empty, followed by an image_file part
+// referring to the upload. It returns the message and the ID of the uploaded file.
+// When the message cannot be created, the file is deleted again and fileID is empty;
+// if that deletion fails too, fileID is returned so the caller can clean it up.
+//
+// The image is checked before the upload: it fails with ErrImageTooLarge when it is
+// larger than ClientConfig.MaxImageUploadBytes, reading no more than the limit, and
+// with ErrImageTypeUnsupported when its content is not a png, jpeg, gif or webp image.
+func (c *Client) CreateMessageWithImage(
+	ctx context.Context,
+	threadID string,
+	text string,
+	image io.Reader,
+	filename string,
+) (msg Message, fileID string, err error) {
+	data, err := c.readUploadImage(image)
+	if err != nil {
+		return
+	}
+	file, err := c.CreateFileBytes(ctx, FileBytesRequest{Name: filename, Bytes: data, Purpose: PurposeVision})
+	if err != nil {
+		return
+	}
+
+	var parts []MessageContent
+	if text != "" {
+		parts = append(parts, NewTextMessageContent(text))
+	}
+	parts = append(parts, NewImageFileMessageContent(file.ID))
+
+	msg, err = c.CreateMessage(ctx, threadID, MessageRequest{
+		Role:         string(ThreadMessageRoleUser),
+		ContentParts: parts,
+	})
+	if err != nil {
+		if c.DeleteFile(ctx, file.ID) != nil {
+			fileID = file.ID
+		}
+		return
+	}
+	fileID = file.ID
+	return
+}
+
+// readUploadImage reads an image for CreateMessageWithImage, checking its size and type.
+func (c *Client) readUploadImage(image io.Reader) (data []byte, err error) {
+	limit := c.config.MaxImageUploadBytes
+	if limit > 0 {
+		image = io.LimitReader(image, int64(limit)+1)
+	}
+	if data, err = io.ReadAll(image); err != nil {
+		return
+	}
+	if limit > 0 && len(data) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrImageTooLarge, limit)
+	}
+	mimeType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	if !visionImageTypes[mimeType] {
+		return nil, fmt.Errorf("%w, got %s", ErrImageTypeUnsupported, mimeType)
+	}
+	return
+}
+
+// CopyMessage creates a copy of a message of the thread srcThreadID in the thread
+// dstThreadID, with the same role, content, attachments and metadata. Text parts are
+// copied without their annotations, and refusal, reasoning and unknown parts are
+// dropped as they cannot be sent. Image files are referred to by ID, so an image_file
+// part or attachment whose file is not usable from the destination thread may fail.
+func (c *Client) CopyMessage(
+	ctx context.Context,
+	srcThreadID, messageID, dstThreadID string,
+) (msg Message, err error) {
+	src, err := c.RetrieveMessage(ctx, srcThreadID, messageID)
+	if err != nil {
+		return
+	}
+	return c.CreateMessage(ctx, dstThreadID, messageRequestFromMessage(src))
+}
+
+// messageRequestFromMessage rebuilds the request creating a message like m.
+func messageRequestFromMessage(m Message) MessageRequest {
+	request := MessageRequest{Role: m.Role}
+	textOnly := true
+	for _, part := range m.Content {
+		switch part.Type {
+		case MessageContentTypeText:
+			if part.Text != nil {
+				request.ContentParts = append(request.ContentParts, NewTextMessageContent(part.Text.Value))
+			}
+		case MessageContentTypeImageFile, MessageContentTypeImageURL,
+			MessageContentTypeVideoURL, MessageContentTypeVideo:
+			request.ContentParts = append(request.ContentParts, part)
+			textOnly = false
+		}
+	}
+	if textOnly {
+		request.Content, request.ContentParts = m.TextContent(), nil
+	}
+
+	if len(m.Attachments) > 0 {
+		request.Attachments = append([]ThreadAttachment(nil), m.Attachments...)
+	}
+	if len(m.Metadata) > 0 {
+		request.Metadata = make(map[string]any, len(m.Metadata))
+		for k, v := range m.Metadata {
+			request.Metadata[k] = v
+		}
+	}
+	return request
+}
+
+// prepareMessageRequest validates request and applies the client defaults to it: file ids
+// are migrated to attachments for the assistants version in use and the default metadata
+// is merged, before the metadata itself is validated. Requests creating a message go
+// through it, so they are checked and sent the same way.
+func (c *Client) prepareMessageRequest(request MessageRequest) (prepared MessageRequest, err error) {
+	if err = request.validate(); err != nil {
+		return
+	}
+	if err = c.validateContentParts(request.ContentParts); err != nil {
+		return
+	}
+	prepared = c.migrateFileIDs(request)
+	prepared.Metadata, err = c.mergeDefaultMetadata(prepared.Metadata)
+	if err != nil {
+		return
+	}
+	err = c.validateMessageMetadata(prepared.Metadata)
+	return
+}
+
+// ErrThreadMessageNotRead is matched by the error of CreateThreadWithMessage when the
+// thread and its message were created but the message could not be read back.
+var ErrThreadMessageNotRead = errors.New("thread created but its message could not be read")
+
+// threadMessageNotReadError is the error of reading back the message of a thread just
+// created by CreateThreadWithMessage. It matches ErrThreadMessageNotRead and unwraps to
+// the error of the list call.
+type threadMessageNotReadError struct {
+	threadID string
+	err      error
+}
+
+func (e *threadMessageNotReadError) Error() string {
+	return fmt.Sprintf("%s: thread %s: %v", ErrThreadMessageNotRead, e.threadID, e.err)
+}
+
+func (e *threadMessageNotReadError) Unwrap() error {
+	return e.err
+}
+
+func (e *threadMessageNotReadError) Is(target error) bool {
+	return target == ErrThreadMessageNotRead
+}
+
+// CreateThreadWithMessage creates a new thread seeded with request as its initial message.
+// The request is checked like CreateMessage's and sent inline through the thread create
+// endpoint. That endpoint returns the thread only, so a second request lists the newest
+// message of the thread to return the created message too; it costs the same two
+// requests as CreateThread followed by CreateMessage, but the thread is never left empty.
+//
+// When the second request fails, the thread exists: it is returned with an error
+// matching ErrThreadMessageNotRead, and its message can be listed again by the thread ID.
+func (c *Client) CreateThreadWithMessage(
+	ctx context.Context,
+	request MessageRequest,
+) (thread Thread, msg Message, err error) {
+	if request, err = c.prepareMessageRequest(request); err != nil {
+		return
+	}
+
+	thread, err = c.CreateThread(ctx, ThreadRequest{Messages: []ThreadMessage{request.ThreadMessage()}})
+	if err != nil {
+		return
+	}
+
+	messages, err := c.ListMessagesWithParams(ctx, thread.ID, ListMessagesParams{Limit: 1, Order: "desc"})
+	if err != nil {
+		err = &threadMessageNotReadError{threadID: thread.ID, err: err}
+		return
+	}
+	if len(messages.Messages) > 0 {
+		msg = messages.Messages[0]
+	}
+	return
+}
+
+// ListMessagesParams holds the optional query parameters of ListMessagesWithParams.
+// Zero values are not sent.
+//
+// After and Before are cursors relative to Order, not to time: with the default desc
+// order After moves to older messages and Before to newer ones, while with asc After
+// moves to newer messages. ListMessagesChronological hides this inversion.
+type ListMessagesParams struct {
+	Limit  int
+	Order  string
+	After  string
+	Before string
+	RunID  string
+	// Include lists fields to expand in the listed messages. Each value is sent as a
+	// repeated include[] query parameter.
+	Include []string
+}
+
+func (p ListMessagesParams) values() url.Values {
+	urlValues := url.Values{}
+	if p.Limit != 0 {
+		urlValues.Add("limit", fmt.Sprintf("%d", p.Limit))
+	}
+	if p.Order != "" {
+		urlValues.Add("order", p.Order)
+	}
+	if p.After != "" {
+		urlValues.Add("after", p.After)
+	}
+	if p.Before != "" {
+		urlValues.Add("before", p.Before)
+	}
+	if p.RunID != "" {
+		urlValues.Add("run_id", p.RunID)
+	}
+	for _, field := range p.Include {
+		if field != "" {
+			urlValues.Add("include[]", field)
+		}
+	}
+	return urlValues
+}
+
 // ListMessage fetches all messages in the thread.
+// Nil parameters are not sent; see ListMessagesWithParams.
 func (c *Client) ListMessage(ctx context.Context, threadID string,
 	limit *int,
 	order *string,
 	after *string,
 	before *string,
 	runID *string,
+	opts ...RequestOption,
 ) (messages MessagesList, err error) {
-	urlValues := url.Values{}
+	var params ListMessagesParams
 	if limit != nil {
-		urlValues.Add("limit", fmt.Sprintf("%d", *limit))
+		params.Limit = *limit
 	}
 	if order != nil {
-		urlValues.Add("order", *order)
+		params.Order = *order
 	}
 	if after != nil {
-		urlValues.Add("after", *after)
+		params.After = *after
 	}
 	if before != nil {
-		urlValues.Add("before", *before)
+		params.Before = *before
 	}
 	if runID != nil {
-		urlValues.Add("run_id", *runID)
+		params.RunID = *runID
+	}
+	return c.ListMessagesWithParams(ctx, threadID, params, opts...)
+}
+
+// ListMessagesWithParams fetches a page of messages in the thread. A thread which does
+// not exist, for instance deleted while its messages are paged, fails with an error
+// matching ErrThreadNotFound which unwraps to the *APIError.
+func (c *Client) ListMessagesWithParams(
+	ctx context.Context,
+	threadID string,
+	params ListMessagesParams,
+	opts ...RequestOption,
+) (messages MessagesList, err error) {
+	req, err := c.newListMessagesRequest(ctx, threadID, params, opts)
+	if err != nil {
+		return
+	}
+
+	err = threadNotFound(c.sendRequest(req, &messages))
+	return
+}
+
+// threadNotFoundError is a 404 listing the messages of a thread which does not exist,
+// or no longer does. It matches ErrThreadNotFound and unwraps to the *APIError.
+type threadNotFoundError struct {
+	err error
+}
+
+func (e *threadNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrThreadNotFound, e.err)
+}
+
+func (e *threadNotFoundError) Unwrap() error {
+	return e.err
+}
+
+func (e *threadNotFoundError) Is(target error) bool {
+	return target == ErrThreadNotFound
+}
+
+// threadNotFound marks a 404 of a messages list as a missing thread. A 404 for a
+// message, such as an unknown After or Before cursor, is returned unchanged.
+func threadNotFound(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		return err
 	}
+	if apiErr.Code == "message_not_found" || strings.Contains(strings.ToLower(apiErr.Message), "no message found") {
+		return err
+	}
+	return &threadNotFoundError{err: err}
+}
 
+func (c *Client) newListMessagesRequest(
+	ctx context.Context,
+	threadID string,
+	params ListMessagesParams,
+	opts []RequestOption,
+) (*http.Request, error) {
 	encodedValues := ""
-	if len(urlValues) > 0 {
+	if urlValues := params.values(); len(urlValues) > 0 {
 		encodedValues = "?" + urlValues.Encode()
 	}
 
-	urlSuffix := fmt.Sprintf("/threads/%s/%s%s", threadID, messagesSuffix, encodedValues)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
-	if err != nil {
-		return
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s%s", threadID, messagesSuffix, encodedValues), opts)
+	return c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix, urlOption),
+		withBetaAssistantVersion(c.config.AssistantVersion), extra)
+}
+
+// MessageIterator walks all messages of a thread, fetching pages lazily as they are consumed.
+type MessageIterator struct {
+	client   *Client
+	ctx      context.Context
+	threadID string
+	params   ListMessagesParams
+
+	messages []Message
+	index    int
+	current  Message
+	done     bool
+	err      error
+}
+
+// MessagesIterator returns an iterator over the messages of the thread starting at the
+// page described by params. A Before cursor is only applied to the first page.
+func (c *Client) MessagesIterator(ctx context.Context, threadID string, params ListMessagesParams) *MessageIterator {
+	return &MessageIterator{
+		client:   c,
+		ctx:      ctx,
+		threadID: threadID,
+		params:   params,
+	}
+}
+
+// Next advances to the next message, fetching the following page when needed.
+// It returns false once all messages are consumed or an error occurred.
+func (it *MessageIterator) Next() bool {
+	for it.index >= len(it.messages) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			return false
+		}
+
+		var list MessagesList
+		list, it.err = it.client.ListMessagesWithParams(it.ctx, it.threadID, it.params)
+		if it.err != nil {
+			return false
+		}
+		it.messages, it.index = list.Messages, 0
+		it.done = !list.HasMore || list.LastID == nil
+		if list.LastID != nil {
+			it.params.After = *list.LastID
+		}
+		it.params.Before = ""
+	}
+
+	it.current = it.messages[it.index]
+	it.index++
+	return true
+}
+
+// Message returns the message the iterator currently points at.
+func (it *MessageIterator) Message() Message {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *MessageIterator) Err() error {
+	return it.err
+}
+
+// ListMessagesChronological returns the messages of the thread created at or after
+// since, oldest first, whatever order the server lists them in. It requests the newest
+// messages first and stops paging once it reaches a message older than since; when
+// the server returns pages in ascending order instead, it pages through the thread.
+// A zero since returns the whole thread.
+func (c *Client) ListMessagesChronological(
+	ctx context.Context,
+	threadID string,
+	since time.Time,
+) (messages []Message, err error) {
+	params := ListMessagesParams{Limit: messagesListMaxLimit, Order: "desc"}
+	descending := true
+	for {
+		var list MessagesList
+		list, err = c.ListMessagesWithParams(ctx, threadID, params)
+		if err != nil {
+			return
+		}
+		page := list.Messages
+		if len(page) > 1 {
+			descending = page[0].CreatedAt >= page[len(page)-1].CreatedAt
+		}
+		reachedSince := false
+		for _, message := range page {
+			if message.CreatedTime().Before(since) {
+				reachedSince = true
+				continue
+			}
+			messages = append(messages, message)
+		}
+		var ok bool
+		if params, ok = list.NextPageParams(params); !ok || (reachedSince && descending) {
+			break
+		}
+	}
+
+	if descending {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
 	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt < messages[j].CreatedAt
+	})
+	return
+}
+
+// ListMessagesWithin returns the messages of the thread created during the last
+// window, oldest first, like ListMessagesChronological. The window ends at the
+// current time of ClientConfig.Now.
+func (c *Client) ListMessagesWithin(
+	ctx context.Context,
+	threadID string,
+	window time.Duration,
+) ([]Message, error) {
+	return c.ListMessagesChronological(ctx, threadID, c.now().Add(-window))
+}
 
-	err = c.sendRequest(req, &messages)
+// MessagesForRun returns the messages the run created in the thread, oldest first.
+// It pages through the messages listed with the run_id filter and also drops the
+// messages of other runs, for servers which ignore the filter.
+func (c *Client) MessagesForRun(ctx context.Context, threadID, runID string) (messages []Message, err error) {
+	it := c.MessagesIterator(ctx, threadID, ListMessagesParams{
+		Limit: messagesListMaxLimit,
+		Order: "asc",
+		RunID: runID,
+	})
+	for it.Next() {
+		if id, ok := it.Message().RunIDValue(); ok && id == runID {
+			messages = append(messages, it.Message())
+		}
+	}
+	err = it.Err()
 	return
 }
 
+// CountMessages returns the number of messages in the thread.
+// It pages through the whole thread with the maximum page size, so the cost grows
+// with the thread length: one request per 100 messages.
+func (c *Client) CountMessages(ctx context.Context, threadID string) (count int, err error) {
+	params := ListMessagesParams{Limit: messagesListMaxLimit}
+	for {
+		var messages MessagesList
+		messages, err = c.ListMessagesWithParams(ctx, threadID, params)
+		if err != nil {
+			return
+		}
+		count += len(messages.Messages)
+		var ok bool
+		if params, ok = messages.NextPageParams(params); !ok {
+			return
+		}
+	}
+}
+
+// CollectThreadCitations pages through the thread from the oldest message and returns
+// the annotations of all text content, keeping the first annotation for every cited
+// file ID or URL. Annotations without a source are skipped.
+func (c *Client) CollectThreadCitations(
+	ctx context.Context,
+	threadID string,
+) (annotations []MessageAnnotation, err error) {
+	params := ListMessagesParams{Limit: messagesListMaxLimit, Order: "asc"}
+	seen := make(map[string]bool)
+	for {
+		var messages MessagesList
+		messages, err = c.ListMessagesWithParams(ctx, threadID, params)
+		if err != nil {
+			return
+		}
+		for _, message := range messages.Messages {
+			for _, content := range message.Content {
+				if content.Text == nil {
+					continue
+				}
+				var typed []MessageAnnotation
+				typed, err = content.Text.TypedAnnotations()
+				if err != nil {
+					return
+				}
+				for _, annotation := range typed {
+					source := annotation.source()
+					if source == "" || seen[source] {
+						continue
+					}
+					seen[source] = true
+					annotations = append(annotations, annotation)
+				}
+			}
+		}
+		var ok bool
+		if params, ok = messages.NextPageParams(params); !ok {
+			return
+		}
+	}
+}
+
+// HasAnyMessages reports whether the thread contains at least one message.
+// It costs a single request fetching at most one message.
+func (c *Client) HasAnyMessages(ctx context.Context, threadID string) (bool, error) {
+	messages, err := c.ListMessagesWithParams(ctx, threadID, ListMessagesParams{Limit: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(messages.Messages) > 0, nil
+}
+
 // RetrieveMessage retrieves a Message.
 func (c *Client) RetrieveMessage(
 	ctx context.Context,
 	threadID, messageID string,
+	opts ...RequestOption,
+) (msg Message, err error) {
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID), opts)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix, urlOption),
+		withBetaAssistantVersion(c.config.AssistantVersion), extra)
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &msg)
+	return
+}
+
+// RetrieveMessageWithInclude retrieves a Message, expanding the fields listed in include.
+// Each value is sent as a repeated include[] query parameter.
+func (c *Client) RetrieveMessageWithInclude(
+	ctx context.Context,
+	threadID, messageID string,
+	include []string,
+	opts ...RequestOption,
 ) (msg Message, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+	urlValues := url.Values{}
+	for _, field := range include {
+		urlValues.Add("include[]", field)
+	}
+
+	encodedValues := ""
+	if len(urlValues) > 0 {
+		encodedValues = "?" + urlValues.Encode()
+	}
+
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s/%s%s", threadID, messagesSuffix, messageID, encodedValues), opts)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix, urlOption),
+		withBetaAssistantVersion(c.config.AssistantVersion), extra)
 	if err != nil {
 		return
 	}
@@ -204,14 +1490,39 @@ func (c *Client) RetrieveMessage(
 }
 
 // ModifyMessage modifies a message.
+// Use ModifyMessageWithMetadata to set metadata values which are not strings.
 func (c *Client) ModifyMessage(
 	ctx context.Context,
 	threadID, messageID string,
 	metadata map[string]string,
+	opts ...RequestOption,
 ) (msg Message, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID)
-	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix),
-		withBody(map[string]any{"metadata": metadata}), withBetaAssistantVersion(c.config.AssistantVersion))
+	var values map[string]any
+	if metadata != nil {
+		values = make(map[string]any, len(metadata))
+		for k, v := range metadata {
+			values[k] = v
+		}
+	}
+	return c.ModifyMessageWithMetadata(ctx, threadID, messageID, values, opts...)
+}
+
+// ModifyMessageWithMetadata modifies the metadata of a message with values of any JSON
+// type, matching the Metadata of MessageRequest.
+func (c *Client) ModifyMessageWithMetadata(
+	ctx context.Context,
+	threadID, messageID string,
+	metadata map[string]any,
+	opts ...RequestOption,
+) (msg Message, err error) {
+	if err = c.validateMessageMetadata(metadata); err != nil {
+		return
+	}
+
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID), opts)
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix, urlOption),
+		withBody(map[string]any{"metadata": metadata}), withBetaAssistantVersion(c.config.AssistantVersion), extra)
 	if err != nil {
 		return
 	}
@@ -221,13 +1532,35 @@ func (c *Client) ModifyMessage(
 }
 
 // RetrieveMessageFile fetches a message file.
+//
+// The v2 assistants API removed the message files endpoints, so unless the client uses
+// AssistantVersion v1 the message is retrieved and the file looked up in Message.Files.
+// A file the message does not reference then fails with ErrMessageFileNotFound.
 func (c *Client) RetrieveMessageFile(
 	ctx context.Context,
 	threadID, messageID, fileID string,
+	opts ...RequestOption,
 ) (file MessageFile, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/%s/%s/files/%s", threadID, messagesSuffix, messageID, fileID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+	if c.config.AssistantVersion != assistantVersionV1 {
+		var msg Message
+		if msg, err = c.RetrieveMessage(ctx, threadID, messageID, opts...); err != nil {
+			return
+		}
+		for _, file = range msg.Files() {
+			if file.ID == fileID {
+				file.httpHeader = msg.httpHeader
+				return
+			}
+		}
+		file = MessageFile{}
+		err = fmt.Errorf("%w: %s", ErrMessageFileNotFound, fileID)
+		return
+	}
+
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s/%s/files/%s", threadID, messagesSuffix, messageID, fileID), opts)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix, urlOption),
+		withBetaAssistantVersion(c.config.AssistantVersion), extra)
 	if err != nil {
 		return
 	}
@@ -236,14 +1569,82 @@ func (c *Client) RetrieveMessageFile(
 	return
 }
 
-// ListMessageFiles fetches all files attached to a message.
+// ListMessageFiles fetches the first page of files attached to a message; use
+// ListMessageFilesWithParams or MessageFilesIterator for the following pages.
+//
+// The v2 assistants API removed the message files endpoints, so unless the client uses
+// AssistantVersion v1 the files endpoint is not called: the message is retrieved and its
+// Message.Files, its attachments and image_file parts, are returned as a single page.
+// See ListMessageFilesWithParams for how that page differs from a v1 one.
 func (c *Client) ListMessageFiles(
 	ctx context.Context,
 	threadID, messageID string,
+	opts ...RequestOption,
+) (files MessageFilesList, err error) {
+	return c.ListMessageFilesWithParams(ctx, threadID, messageID, ListMessageFilesParams{}, opts...)
+}
+
+// ListMessageFilesParams holds the query parameters of ListMessageFilesWithParams.
+// Zero values are not sent.
+type ListMessageFilesParams struct {
+	Limit  int
+	Order  string
+	After  string
+	Before string
+}
+
+func (p ListMessageFilesParams) values() url.Values {
+	urlValues := url.Values{}
+	if p.Limit != 0 {
+		urlValues.Add("limit", fmt.Sprintf("%d", p.Limit))
+	}
+	if p.Order != "" {
+		urlValues.Add("order", p.Order)
+	}
+	if p.After != "" {
+		urlValues.Add("after", p.After)
+	}
+	if p.Before != "" {
+		urlValues.Add("before", p.Before)
+	}
+	return urlValues
+}
+
+// ListMessageFilesWithParams fetches one page of the files attached to a message.
+//
+// Assistants v2 has no message files endpoint, so unless the client uses AssistantVersion
+// v1 the message is retrieved with RetrieveMessage instead and its Message.Files are
+// returned. Params are then ignored: every file is returned in one page, in the order of
+// Message.Files, with HasMore false. Each file has the ID of the referenced file and the
+// creation time of the message, and the response headers are those of the message.
+func (c *Client) ListMessageFilesWithParams(
+	ctx context.Context,
+	threadID, messageID string,
+	params ListMessageFilesParams,
+	opts ...RequestOption,
 ) (files MessageFilesList, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/%s/%s/files", threadID, messagesSuffix, messageID)
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+	if c.config.AssistantVersion != assistantVersionV1 {
+		var msg Message
+		if msg, err = c.RetrieveMessage(ctx, threadID, messageID, opts...); err != nil {
+			return
+		}
+		files.MessageFiles, files.Object = msg.Files(), "list"
+		if n := len(files.MessageFiles); n > 0 {
+			files.FirstID, files.LastID = &files.MessageFiles[0].ID, &files.MessageFiles[n-1].ID
+		}
+		files.httpHeader = msg.httpHeader
+		return
+	}
+
+	encodedValues := ""
+	if urlValues := params.values(); len(urlValues) > 0 {
+		encodedValues = "?" + urlValues.Encode()
+	}
+
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s/%s/files%s", threadID, messagesSuffix, messageID, encodedValues), opts)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix, urlOption),
+		withBetaAssistantVersion(c.config.AssistantVersion), extra)
 	if err != nil {
 		return
 	}
@@ -252,14 +1653,85 @@ func (c *Client) ListMessageFiles(
 	return
 }
 
-// DeleteMessage deletes a message..
+// MessageFileIterator walks all files of a message, fetching pages lazily as they
+// are consumed.
+type MessageFileIterator struct {
+	client              *Client
+	ctx                 context.Context
+	threadID, messageID string
+	params              ListMessageFilesParams
+
+	files   []MessageFile
+	index   int
+	current MessageFile
+	done    bool
+	err     error
+}
+
+// MessageFilesIterator returns an iterator over the files of the message starting at
+// the page described by params. A Before cursor is only applied to the first page.
+func (c *Client) MessageFilesIterator(
+	ctx context.Context,
+	threadID, messageID string,
+	params ListMessageFilesParams,
+) *MessageFileIterator {
+	return &MessageFileIterator{
+		client:    c,
+		ctx:       ctx,
+		threadID:  threadID,
+		messageID: messageID,
+		params:    params,
+	}
+}
+
+// Next advances to the next file, fetching the following page when needed.
+// It returns false once all files are consumed or an error occurred.
+func (it *MessageFileIterator) Next() bool {
+	for it.index >= len(it.files) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			return false
+		}
+
+		var list MessageFilesList
+		list, it.err = it.client.ListMessageFilesWithParams(it.ctx, it.threadID, it.messageID, it.params)
+		if it.err != nil {
+			return false
+		}
+		var more bool
+		it.files, it.index = list.MessageFiles, 0
+		it.params, more = list.NextPageParams(it.params)
+		it.done = !more
+	}
+
+	it.current = it.files[it.index]
+	it.index++
+	return true
+}
+
+// MessageFile returns the file the iterator currently points at.
+func (it *MessageFileIterator) MessageFile() MessageFile {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *MessageFileIterator) Err() error {
+	return it.err
+}
+
+// DeleteMessage deletes a message. An unknown thread or message fails with an *APIError
+// carrying the 404 status and the error code of the API.
 func (c *Client) DeleteMessage(
 	ctx context.Context,
 	threadID, messageID string,
+	opts ...RequestOption,
 ) (status MessageDeletionStatus, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID)
-	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix),
-		withBetaAssistantVersion(c.config.AssistantVersion))
+	urlSuffix, urlOption, extra := applyRequestOptions(
+		fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID), opts)
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix, urlOption),
+		withBetaAssistantVersion(c.config.AssistantVersion), extra)
 	if err != nil {
 		return
 	}
@@ -267,3 +1739,35 @@ func (c *Client) DeleteMessage(
 	err = c.sendRequest(req, &status)
 	return
 }
+
+// DeleteMessageIfExists deletes a message when it exists, for cleanup routines which
+// should not report messages which are already gone. The message is retrieved first,
+// and existed is false, without an error, when the retrieval or the deletion, for a
+// message deleted in between, fails with a 404. A message of an unknown thread is
+// reported as not existing as well. opts apply to both requests.
+func (c *Client) DeleteMessageIfExists(
+	ctx context.Context,
+	threadID, messageID string,
+	opts ...RequestOption,
+) (status MessageDeletionStatus, existed bool, err error) {
+	if _, err = c.RetrieveMessage(ctx, threadID, messageID, opts...); err != nil {
+		if isNotFoundError(err) {
+			err = nil
+		}
+		return
+	}
+
+	status, err = c.DeleteMessage(ctx, threadID, messageID, opts...)
+	if err != nil && isNotFoundError(err) {
+		err = nil
+		return
+	}
+	existed = true
+	return
+}
+
+// isNotFoundError reports whether err is an *APIError with a 404 status.
+func isNotFoundError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusNotFound
+}
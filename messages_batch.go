@@ -0,0 +1,244 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var ErrMessageDeletionBatchFailed = errors.New("deletion failed for some messages")
+
+// MessageDeletionFailure is a message DeleteMessages failed to delete.
+type MessageDeletionFailure struct {
+	MessageID string
+	Err       error
+}
+
+// MessageDeletionBatchError lists the messages DeleteMessages failed to delete, in
+// input order, together with the IDs of the messages it did delete. errors.Is matches
+// ErrMessageDeletionBatchFailed, and errors.Is and errors.As reach the error of each
+// message through Unwrap.
+type MessageDeletionBatchError struct {
+	Deleted []string
+	Failed  []MessageDeletionFailure
+}
+
+func (e *MessageDeletionBatchError) Error() string {
+	failures := make([]string, len(e.Failed))
+	for i, failure := range e.Failed {
+		failures[i] = fmt.Sprintf("%s: %v", failure.MessageID, failure.Err)
+	}
+	return fmt.Sprintf("%s: %d of %d messages: %s", ErrMessageDeletionBatchFailed,
+		len(e.Failed), len(e.Failed)+len(e.Deleted), strings.Join(failures, "; "))
+}
+
+func (e *MessageDeletionBatchError) Is(target error) bool {
+	return target == ErrMessageDeletionBatchFailed
+}
+
+func (e *MessageDeletionBatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, failure := range e.Failed {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// messageBatchContentPreview is the number of runes of content a MessageBatchError keeps.
+const messageBatchContentPreview = 40
+
+// MessageBatchError is the error of the request of CreateMessages which failed.
+type MessageBatchError struct {
+	// Index is the index of the failed request within the requests.
+	Index int
+	Role  string
+	// Content is the start of the text of the request, truncated to 40 characters.
+	Content string
+	Err     error
+}
+
+func newMessageBatchError(index int, request MessageRequest, err error) *MessageBatchError {
+	content := request.Content
+	if content == "" {
+		var text strings.Builder
+		for _, part := range request.ContentParts {
+			if part.Type == MessageContentTypeText && part.Text != nil {
+				text.WriteString(part.Text.Value)
+			}
+		}
+		content = text.String()
+	}
+	if runes := []rune(content); len(runes) > messageBatchContentPreview {
+		content = string(runes[:messageBatchContentPreview]) + "..."
+	}
+	return &MessageBatchError{Index: index, Role: request.Role, Content: content, Err: err}
+}
+
+func (e *MessageBatchError) Error() string {
+	return fmt.Sprintf("message %d (%s %q): %v", e.Index, e.Role, e.Content, e.Err)
+}
+
+func (e *MessageBatchError) Unwrap() error {
+	return e.Err
+}
+
+// CreateMessages creates the messages of requests in the thread one after the other, so
+// the thread keeps their order, and returns them in the order of requests. It stops at
+// the first failure and returns the messages created so far together with a
+// *MessageBatchError wrapping the error of the failed request.
+//
+// With WithMaxConcurrency up to n messages are created at a time. Their order within the
+// thread is then not guaranteed, while the returned messages still follow requests. The
+// first failure cancels the requests in flight and no further message is created.
+func (c *Client) CreateMessages(
+	ctx context.Context,
+	threadID string,
+	requests []MessageRequest,
+	opts ...BatchOption,
+) (messages []Message, err error) {
+	var options batchOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.maxConcurrency <= 1 {
+		for i, request := range requests {
+			var msg Message
+			if msg, err = c.CreateMessage(ctx, threadID, request); err != nil {
+				err = newMessageBatchError(i, request, err)
+				return
+			}
+			messages = append(messages, msg)
+		}
+		return
+	}
+	return c.createMessagesConcurrently(ctx, threadID, requests, options.maxConcurrency)
+}
+
+func (c *Client) createMessagesConcurrently(
+	ctx context.Context,
+	threadID string,
+	requests []MessageRequest,
+	concurrency int,
+) (messages []Message, err error) {
+	if concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var once sync.Once
+
+	created := make([]Message, len(requests))
+	failed := make([]bool, len(requests))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				msg, createErr := c.CreateMessage(ctx, threadID, requests[i])
+				if createErr != nil {
+					failed[i] = true
+					once.Do(func() {
+						err = newMessageBatchError(i, requests[i], createErr)
+						cancel()
+					})
+					continue
+				}
+				created[i] = msg
+			}
+		}()
+	}
+	dispatched := 0
+	for ; dispatched < len(requests) && ctx.Err() == nil; dispatched++ {
+		indexes <- dispatched
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := 0; i < dispatched; i++ {
+		if !failed[i] {
+			messages = append(messages, created[i])
+		}
+	}
+	if err == nil && dispatched < len(requests) {
+		err = ctx.Err()
+	}
+	return
+}
+
+// DeleteMessages deletes the messages of messageIDs from the thread with up to
+// concurrency requests in flight. A non-positive concurrency deletes one message at a
+// time. It returns the statuses of the deleted messages in the order of messageIDs.
+//
+// When any deletion fails, the statuses are returned together with a
+// *MessageDeletionBatchError. No deletion is started once ctx is done; the remaining
+// messages fail with the context error. With WithFailFast the first failure cancels
+// the deletions in flight, which fail with the context error as well, and the error
+// is returned as soon as they have stopped.
+func (c *Client) DeleteMessages(
+	ctx context.Context,
+	threadID string,
+	messageIDs []string,
+	concurrency int,
+	opts ...BatchOption,
+) (statuses []MessageDeletionStatus, err error) {
+	var options batchOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(messageIDs) {
+		concurrency = len(messageIDs)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var once sync.Once
+
+	deleted := make([]MessageDeletionStatus, len(messageIDs))
+	errs := make([]error, len(messageIDs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				deleted[i], errs[i] = c.DeleteMessage(ctx, threadID, messageIDs[i])
+				if errs[i] != nil && options.failFast {
+					once.Do(cancel)
+				}
+			}
+		}()
+	}
+	dispatched := 0
+	for ; dispatched < len(messageIDs) && ctx.Err() == nil; dispatched++ {
+		indexes <- dispatched
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := dispatched; i < len(messageIDs); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	batchErr := &MessageDeletionBatchError{}
+	for i, id := range messageIDs {
+		if errs[i] != nil {
+			batchErr.Failed = append(batchErr.Failed, MessageDeletionFailure{MessageID: id, Err: errs[i]})
+			continue
+		}
+		statuses = append(statuses, deleted[i])
+		batchErr.Deleted = append(batchErr.Deleted, id)
+	}
+	if len(batchErr.Failed) > 0 {
+		err = batchErr
+	}
+	return
+}
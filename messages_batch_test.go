@@ -0,0 +1,219 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateMessages(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var mu sync.Mutex
+	var received []string
+	inFlight, maxInFlight := 0, 0
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		var req openai.MessageRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		checks.NoError(t, err, "Decode error")
+
+		mu.Lock()
+		received = append(received, req.Content)
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if req.Content == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error":{"message":"invalid message","type":"invalid_request_error"}}`)
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+		resBytes, _ := json.Marshal(openai.Message{ID: "msg_" + req.Content, ThreadID: "thread_abc123"})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	requests := func(contents ...string) (reqs []openai.MessageRequest) {
+		for _, content := range contents {
+			reqs = append(reqs, openai.MessageRequest{Role: string(openai.ThreadMessageRoleUser), Content: content})
+		}
+		return
+	}
+	ids := func(messages []openai.Message) (ids []string) {
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+		}
+		return
+	}
+
+	ctx := context.Background()
+	messages, err := client.CreateMessages(ctx, "thread_abc123", requests("a", "b", "c", "d"))
+	checks.NoError(t, err, "CreateMessages error")
+	if fmt.Sprint(ids(messages)) != "[msg_a msg_b msg_c msg_d]" || fmt.Sprint(received) != "[a b c d]" {
+		t.Fatalf("expected messages to be created in order, got %v from %v", ids(messages), received)
+	}
+
+	received = nil
+	messages, err = client.CreateMessages(ctx, "thread_abc123", requests("a", "fail", "c"))
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Fatalf("expected the API error of the failed message, got %v", err)
+	}
+	var batchErr *openai.MessageBatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 || batchErr.Role != "user" || batchErr.Content != "fail" {
+		t.Fatalf("expected the failed request to be named, got %#v", err)
+	}
+	if fmt.Sprint(ids(messages)) != "[msg_a]" || fmt.Sprint(received) != "[a fail]" {
+		t.Fatalf("expected to stop at the failed message, got %v from %v", ids(messages), received)
+	}
+
+	received, maxInFlight = nil, 0
+	messages, err = client.CreateMessages(ctx, "thread_abc123", requests("a", "b", "c", "d", "e", "f"),
+		openai.WithMaxConcurrency(3))
+	checks.NoError(t, err, "CreateMessages error")
+	if fmt.Sprint(ids(messages)) != "[msg_a msg_b msg_c msg_d msg_e msg_f]" || maxInFlight > 3 {
+		t.Fatalf("expected ordered messages with at most 3 requests in flight, got %v and %d",
+			ids(messages), maxInFlight)
+	}
+
+	received = nil
+	_, err = client.CreateMessages(ctx, "thread_abc123", requests("fail", "b", "c", "d", "e", "f"),
+		openai.WithMaxConcurrency(2))
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected the API error of the failed message, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) == 6 {
+		t.Fatalf("expected the failure to stop further messages, got %v", received)
+	}
+}
+
+func TestMessageBatchError(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"error":{"message":"invalid message","type":"invalid_request_error"}}`)
+	})
+
+	long := strings.Repeat("é", 50)
+	_, err := client.CreateMessages(context.Background(), "thread_abc123", []openai.MessageRequest{{
+		Role:         string(openai.ThreadMessageRoleAssistant),
+		ContentParts: []openai.MessageContent{openai.NewTextMessageContent(long)},
+	}}, openai.WithMaxConcurrency(2))
+
+	var batchErr *openai.MessageBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a *MessageBatchError, got %v", err)
+	}
+	want := fmt.Sprintf("message 0 (assistant %q): ", strings.Repeat("é", 40)+"...")
+	if !strings.HasPrefix(err.Error(), want) || !strings.Contains(err.Error(), "invalid message") {
+		t.Fatalf("unexpected error text %q", err.Error())
+	}
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Fatalf("expected the API error to be wrapped, got %v", err)
+	}
+}
+
+func TestDeleteMessages(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	for _, id := range []string{"msg_1", "msg_2", "msg_3", "msg_4", "msg_missing"} {
+		id := id
+		server.RegisterHandler("/v1/threads/thread_abc123/messages/"+id, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete {
+				t.Errorf("expected DELETE, got %s", r.Method)
+			}
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+
+			time.Sleep(5 * time.Millisecond)
+			if id == "msg_missing" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprintln(w, `{"error":{"message":"No message found","type":"invalid_request_error"}}`)
+				return
+			}
+			fmt.Fprintf(w, `{"id":%q,"object":"thread.message.deleted","deleted":true}`, id)
+		})
+	}
+
+	ctx := context.Background()
+	statuses, err := client.DeleteMessages(ctx, "thread_abc123",
+		[]string{"msg_1", "msg_missing", "msg_2", "msg_3", "msg_4"}, 2)
+	checks.ErrorIs(t, err, openai.ErrMessageDeletionBatchFailed, "expected a batch error")
+	var batchErr *openai.MessageDeletionBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a *MessageDeletionBatchError, got %T", err)
+	}
+	var apiErr *openai.APIError
+	if len(batchErr.Failed) != 1 || batchErr.Failed[0].MessageID != "msg_missing" ||
+		!errors.As(batchErr.Failed[0].Err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected failures %+v", batchErr.Failed)
+	}
+	wantDeleted := []string{"msg_1", "msg_2", "msg_3", "msg_4"}
+	if fmt.Sprint(batchErr.Deleted) != fmt.Sprint(wantDeleted) || len(statuses) != len(wantDeleted) {
+		t.Fatalf("unexpected deleted messages %v with %d statuses", batchErr.Deleted, len(statuses))
+	}
+	for i, status := range statuses {
+		if status.ID != wantDeleted[i] || !status.Deleted {
+			t.Fatalf("unexpected status %d: %+v", i, status)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 deletions in flight, got %d", maxInFlight)
+	}
+
+	statuses, err = client.DeleteMessages(ctx, "thread_abc123", []string{"msg_1", "msg_2"}, 0)
+	checks.NoError(t, err, "DeleteMessages error")
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	statuses, err = client.DeleteMessages(canceled, "thread_abc123", []string{"msg_1", "msg_2"}, 1)
+	checks.ErrorIs(t, err, context.Canceled, "expected the context error")
+	if len(statuses) != 0 {
+		t.Fatalf("expected no deletion after cancellation, got %+v", statuses)
+	}
+
+	_, err = client.DeleteMessages(ctx, "thread_abc123", []string{"msg_missing", "msg_1"}, 1, openai.WithFailFast())
+	if !errors.As(err, &batchErr) || !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Fatalf("expected a batch error wrapping the error of the failed message with WithFailFast, got %v", err)
+	}
+	if len(batchErr.Failed) != 2 || batchErr.Failed[0].MessageID != "msg_missing" ||
+		!errors.Is(batchErr.Failed[1].Err, context.Canceled) || len(batchErr.Deleted) != 0 {
+		t.Fatalf("expected the remaining message to fail with the context error, got %+v", batchErr)
+	}
+}
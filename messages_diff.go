@@ -0,0 +1,77 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const missingMessagePart = "<missing>"
+
+// EqualContent reports whether m and other have the same role, content parts, in the
+// same order, attachments and metadata. Server assigned fields, such as ID, CreatedAt,
+// ThreadID, RunID or Status, and the response headers are ignored, so a message can
+// be compared with the expected one in tests. Nil and empty attachments or metadata
+// are equal.
+func (m Message) EqualContent(other Message) bool {
+	return DiffMessages(m, other) == ""
+}
+
+// DiffMessages returns the differences between the fields of a and b compared by
+// Message.EqualContent, one per line as "field: a != b", with the content parts,
+// attachments and metadata shown as JSON. It returns an empty string when the
+// messages are equal.
+func DiffMessages(a, b Message) string {
+	var diff strings.Builder
+	addDiff := func(field, x, y string) {
+		if x != y {
+			fmt.Fprintf(&diff, "%s: %s != %s\n", field, x, y)
+		}
+	}
+
+	addDiff("role", fmt.Sprintf("%q", a.Role), fmt.Sprintf("%q", b.Role))
+	if len(a.Content) != len(b.Content) {
+		addDiff("content", fmt.Sprintf("%d parts", len(a.Content)), fmt.Sprintf("%d parts", len(b.Content)))
+	}
+	for i := 0; i < len(a.Content) || i < len(b.Content); i++ {
+		addDiff(fmt.Sprintf("content[%d]", i), messagePartJSON(a.Content, i), messagePartJSON(b.Content, i))
+	}
+	var aAttachments, bAttachments any
+	if len(a.Attachments) > 0 {
+		aAttachments = a.Attachments
+	}
+	if len(b.Attachments) > 0 {
+		bAttachments = b.Attachments
+	}
+	addDiff("attachments", diffJSON(aAttachments), diffJSON(bAttachments))
+	var aMetadata, bMetadata any
+	if len(a.Metadata) > 0 {
+		aMetadata = a.Metadata
+	}
+	if len(b.Metadata) > 0 {
+		bMetadata = b.Metadata
+	}
+	addDiff("metadata", diffJSON(aMetadata), diffJSON(bMetadata))
+	return diff.String()
+}
+
+// messagePartJSON returns the JSON of parts[i], its Raw JSON for parts of unknown
+// types, or missingMessagePart when parts is shorter.
+func messagePartJSON(parts []MessageContent, i int) string {
+	if i >= len(parts) {
+		return missingMessagePart
+	}
+	if len(parts[i].Raw) > 0 {
+		return string(parts[i].Raw)
+	}
+	return diffJSON(parts[i])
+}
+
+// diffJSON returns v as JSON. Map keys are sorted, so equal values have equal JSON.
+func diffJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,74 @@
+package openai_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestMessageEqualContent(t *testing.T) {
+	runID := "run_abc123"
+	expected := openai.Message{
+		Role: string(openai.ThreadMessageRoleAssistant),
+		Content: []openai.MessageContent{
+			openai.NewTextMessageContent("here is the chart"),
+			openai.NewImageFileMessageContent("file_chart"),
+		},
+		Attachments: []openai.ThreadAttachment{
+			openai.NewThreadAttachment("file_data", openai.AssistantToolTypeCodeInterpreter),
+		},
+		Metadata: map[string]any{"step": "plot", "attempt": float64(1)},
+	}
+	received := expected
+	received.ID = "msg_abc123"
+	received.CreatedAt = 1699061776
+	received.ThreadID = "thread_abc123"
+	received.RunID = &runID
+	received.Metadata = map[string]any{"attempt": float64(1), "step": "plot"}
+	if !received.EqualContent(expected) {
+		t.Fatalf("expected server assigned fields to be ignored, got diff:\n%s", openai.DiffMessages(received, expected))
+	}
+	if diff := openai.DiffMessages(received, expected); diff != "" {
+		t.Fatalf("expected no diff, got:\n%s", diff)
+	}
+
+	empty := openai.Message{Role: "user", Metadata: map[string]any{}, Attachments: []openai.ThreadAttachment{}}
+	if !empty.EqualContent(openai.Message{Role: "user"}) {
+		t.Fatal("expected empty and nil metadata and attachments to be equal")
+	}
+
+	reordered := expected
+	reordered.Content = []openai.MessageContent{expected.Content[1], expected.Content[0]}
+	if reordered.EqualContent(expected) {
+		t.Fatal("expected the order of content parts to matter")
+	}
+	diff := openai.DiffMessages(reordered, expected)
+	for _, want := range []string{
+		`content[0]: {"type":"image_file","image_file":{"file_id":"file_chart"}} != {"type":"text"`,
+		`content[1]: {"type":"text"`,
+	} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+
+	changed := expected
+	changed.Role = string(openai.ThreadMessageRoleUser)
+	changed.Content = expected.Content[:1]
+	changed.Metadata = map[string]any{"step": "fit"}
+	diff = openai.DiffMessages(changed, expected)
+	for _, want := range []string{
+		`role: "user" != "assistant"`,
+		"content: 1 parts != 2 parts",
+		`content[1]: <missing> != {"type":"image_file"`,
+		`metadata: {"step":"fit"} != {"attempt":1,"step":"plot"}`,
+	} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "attachments") {
+		t.Fatalf("expected equal attachments not to be reported, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MessageRef is the ID and role of a message, as listed by ListMessageIDs.
+type MessageRef struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+// MessageRefsList is a page of messages listed by ListMessageIDs.
+type MessageRefsList struct {
+	Messages []MessageRef
+
+	FirstID *string
+	LastID  *string
+	HasMore bool
+
+	httpHeader
+}
+
+// NextPageParams returns params moved to the page following the list, which was
+// fetched with params. It returns false when the list is the last page.
+func (l MessageRefsList) NextPageParams(params ListMessagesParams) (ListMessagesParams, bool) {
+	after, ok := l.Page().NextAfter()
+	if !ok {
+		return params, false
+	}
+	params.After, params.Before = after, ""
+	return params, true
+}
+
+// ListMessageIDs fetches a page of messages like ListMessagesWithParams, but only
+// keeps their IDs and roles. The response is decoded as it is read and the content
+// of each message, whose annotations may carry large file search quotes, is skipped
+// without being decoded, for tools which scan long threads.
+func (c *Client) ListMessageIDs(
+	ctx context.Context,
+	threadID string,
+	params ListMessagesParams,
+	opts ...RequestOption,
+) (list MessageRefsList, err error) {
+	req, err := c.newListMessagesRequest(ctx, threadID, params, opts)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.sendRequestRaw(req)
+	if err != nil {
+		err = threadNotFound(err)
+		return
+	}
+	defer res.Close()
+
+	list.httpHeader = res.httpHeader
+	err = decodeMessageRefs(res, &list)
+	return
+}
+
+// decodeMessageRefs decodes a messages list page from r into list, one message at a
+// time, keeping only the fields of MessageRef.
+func decodeMessageRefs(r io.Reader, list *MessageRefsList) error {
+	decoder := json.NewDecoder(r)
+	if err := expectJSONDelim(decoder, '{'); err != nil {
+		return err
+	}
+	var embeddedErr *APIError
+	isResource := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch key, _ := token.(string); key {
+		case "data":
+			if err = decodeMessageRefsData(decoder, list); err != nil {
+				return err
+			}
+		case "first_id":
+			err = decoder.Decode(&list.FirstID)
+		case "last_id":
+			err = decoder.Decode(&list.LastID)
+		case "has_more":
+			err = decoder.Decode(&list.HasMore)
+		case "error":
+			// Some gateways return an error object with a 2xx status code, see
+			// embeddedAPIError.
+			var apiErr *APIError
+			if decoder.Decode(&apiErr) == nil && apiErr != nil && apiErr.Message != "" {
+				embeddedErr = apiErr
+			}
+		case "id", "object":
+			isResource = true
+			fallthrough
+		default:
+			var skipped json.RawMessage
+			err = decoder.Decode(&skipped)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if embeddedErr != nil && !isResource {
+		return embeddedErr
+	}
+	return expectJSONDelim(decoder, '}')
+}
+
+func decodeMessageRefsData(decoder *json.Decoder, list *MessageRefsList) error {
+	if err := expectJSONDelim(decoder, '['); err != nil {
+		return err
+	}
+	for decoder.More() {
+		// Only the fields of MessageRef are decoded, the other ones are scanned.
+		var ref MessageRef
+		if err := decoder.Decode(&ref); err != nil {
+			return err
+		}
+		list.Messages = append(list.Messages, ref)
+	}
+	return expectJSONDelim(decoder, ']')
+}
+
+func expectJSONDelim(decoder *json.Decoder, want json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("invalid messages list, expected %q, got %v", want, token)
+	}
+	return nil
+}
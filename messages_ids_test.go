@@ -0,0 +1,101 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// messagesListWithQuotes returns a messages list page whose messages carry file
+// search citations quoting quoteSize bytes each.
+func messagesListWithQuotes(messages, quoteSize int) []byte {
+	quote := strings.Repeat("lorem ipsum ", quoteSize/12)
+	list := map[string]any{"object": "list", "has_more": true}
+	data := make([]map[string]any, messages)
+	for i := range data {
+		data[i] = map[string]any{
+			"id":     fmt.Sprintf("msg_%d", i),
+			"object": "thread.message",
+			"role":   "assistant",
+			"content": []map[string]any{{
+				"type": "text",
+				"text": map[string]any{
+					"value": "See the report.",
+					"annotations": []map[string]any{{
+						"type":          "file_citation",
+						"text":          "【4:0†source】",
+						"file_citation": map[string]any{"file_id": "file_abc123", "quote": quote},
+					}},
+				},
+			}},
+			"metadata": map[string]any{"id": "not the message id"},
+		}
+	}
+	list["data"] = data
+	list["first_id"] = "msg_0"
+	list["last_id"] = fmt.Sprintf("msg_%d", messages-1)
+	body, _ := json.Marshal(list)
+	return body
+}
+
+func TestListMessageIDs(t *testing.T) {
+	doer := &staticResponseDoer{statusCode: http.StatusOK, body: string(messagesListWithQuotes(3, 1024))}
+	config := DefaultConfig("token")
+	config.HTTPClient = doer
+	client := NewClientWithConfig(config)
+
+	params := ListMessagesParams{Limit: 3, Order: "asc"}
+	list, err := client.ListMessageIDs(context.Background(), "thread_abc123", params)
+	checks.NoError(t, err, "ListMessageIDs error")
+	want := []MessageRef{{"msg_0", "assistant"}, {"msg_1", "assistant"}, {"msg_2", "assistant"}}
+	if fmt.Sprint(list.Messages) != fmt.Sprint(want) {
+		t.Fatalf("unexpected messages %v", list.Messages)
+	}
+	if !strings.HasSuffix(doer.lastURL, "/threads/thread_abc123/messages?limit=3&order=asc") {
+		t.Fatalf("unexpected URL %s", doer.lastURL)
+	}
+	next, ok := list.NextPageParams(params)
+	if !ok || next.After != "msg_2" || *list.FirstID != "msg_0" {
+		t.Fatalf("unexpected next page params %+v, %v", next, ok)
+	}
+
+	doer.body = `{"error":{"message":"upstream unavailable","type":"server_error"}}`
+	_, err = client.ListMessageIDs(context.Background(), "thread_abc123", params)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "upstream unavailable" {
+		t.Fatalf("expected the embedded API error, got %v", err)
+	}
+
+	doer.body = `{"object":"list","data":[{"id":"msg_1"}`
+	_, err = client.ListMessageIDs(context.Background(), "thread_abc123", params)
+	checks.HasError(t, err, "expected a truncated body to fail")
+}
+
+func BenchmarkListMessagesDecode(b *testing.B) {
+	body := messagesListWithQuotes(100, 64*1024)
+	b.Run("full", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var list MessagesList
+			if err := json.NewDecoder(bytes.NewReader(body)).Decode(&list); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ids", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var list MessageRefsList
+			if err := decodeMessageRefs(bytes.NewReader(body), &list); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package openai
+
+import "context"
+
+// MessagesService is the set of message operations of the assistants API. *Client
+// implements it, so code creating and reading messages can depend on the interface and
+// be tested with a fake.
+type MessagesService interface {
+	CreateMessage(ctx context.Context, threadID string, request MessageRequest, opts ...RequestOption) (Message, error)
+	ListMessage(ctx context.Context, threadID string, limit *int, order *string, after *string, before *string,
+		runID *string, opts ...RequestOption) (MessagesList, error)
+	RetrieveMessage(ctx context.Context, threadID, messageID string, opts ...RequestOption) (Message, error)
+	ModifyMessage(ctx context.Context, threadID, messageID string, metadata map[string]string,
+		opts ...RequestOption) (Message, error)
+	DeleteMessage(ctx context.Context, threadID, messageID string, opts ...RequestOption) (MessageDeletionStatus, error)
+	ListMessageFiles(ctx context.Context, threadID, messageID string, opts ...RequestOption) (MessageFilesList, error)
+	RetrieveMessageFile(ctx context.Context, threadID, messageID, fileID string,
+		opts ...RequestOption) (MessageFile, error)
+}
+
+var _ MessagesService = (*Client)(nil)
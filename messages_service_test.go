@@ -0,0 +1,44 @@
+package openai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// fakeMessages stores created messages in memory. The methods a test does not use
+// panic through the embedded nil interface.
+type fakeMessages struct {
+	openai.MessagesService
+	created []openai.MessageRequest
+}
+
+func (f *fakeMessages) CreateMessage(
+	_ context.Context,
+	threadID string,
+	request openai.MessageRequest,
+	_ ...openai.RequestOption,
+) (openai.Message, error) {
+	f.created = append(f.created, request)
+	return openai.Message{ID: "msg_fake", ThreadID: threadID, Role: request.Role}, nil
+}
+
+// askQuestion is code under test which only depends on the message operations.
+func askQuestion(ctx context.Context, messages openai.MessagesService, threadID, question string) (string, error) {
+	request := openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), question)
+	msg, err := messages.CreateMessage(ctx, threadID, request)
+	return msg.ID, err
+}
+
+func TestMessagesServiceFake(t *testing.T) {
+	fake := &fakeMessages{}
+	id, err := askQuestion(context.Background(), fake, "thread_abc123", "How are you?")
+	checks.NoError(t, err, "askQuestion error")
+	if id != "msg_fake" || len(fake.created) != 1 || fake.created[0].Content != "How are you?" {
+		t.Fatalf("unexpected fake state %q, %+v", id, fake.created)
+	}
+
+	var _ openai.MessagesService = openai.NewClient("token")
+}
@@ -0,0 +1,104 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// messageDeltaObject is the object type of the message delta events of a run stream.
+const messageDeltaObject = "thread.message.delta"
+
+// MessageDeltaEvent is a thread.message.delta event: the content of message ID added
+// since the previous delta.
+type MessageDeltaEvent struct {
+	ID     string       `json:"id"`
+	Object string       `json:"object"`
+	Delta  MessageDelta `json:"delta"`
+}
+
+type MessageDelta struct {
+	Role    string                `json:"role,omitempty"`
+	Content []MessageDeltaContent `json:"content,omitempty"`
+}
+
+// MessageDeltaContent is a fragment of the content part at position Index of the message.
+type MessageDeltaContent struct {
+	Index     int               `json:"index"`
+	Type      string            `json:"type"`
+	Text      *MessageDeltaText `json:"text,omitempty"`
+	ImageFile *ImageFile        `json:"image_file,omitempty"`
+	ImageURL  *ImageURL         `json:"image_url,omitempty"`
+}
+
+// MessageDeltaText is text to append to a text content part, with the annotations
+// added to it.
+type MessageDeltaText struct {
+	Value       string              `json:"value,omitempty"`
+	Annotations []MessageAnnotation `json:"annotations,omitempty"`
+}
+
+// Text returns the text added by the delta across its content parts.
+func (e MessageDeltaEvent) Text() string {
+	var text strings.Builder
+	for _, content := range e.Delta.Content {
+		if content.Text != nil {
+			text.WriteString(content.Text.Value)
+		}
+	}
+	return text.String()
+}
+
+// MessageDeltaStream streams the message deltas of a run. Recv skips the run, run step
+// and other events of the stream and returns io.EOF after its done event.
+type MessageDeltaStream struct {
+	*streamReader[MessageDeltaEvent]
+}
+
+// isMessageDelta reports whether raw is a message delta event.
+func isMessageDelta(raw []byte) bool {
+	var event struct {
+		Object string `json:"object"`
+	}
+	return json.Unmarshal(raw, &event) == nil && event.Object == messageDeltaObject
+}
+
+// CreateRunMessageStream creates a run with streaming enabled and returns the deltas
+// of the messages it writes.
+func (c *Client) CreateRunMessageStream(
+	ctx context.Context,
+	threadID string,
+	request RunRequest,
+	opts ...StreamOption,
+) (stream *MessageDeltaStream, err error) {
+	request, err = c.mergeDefaultRunMetadata(request)
+	if err != nil {
+		return
+	}
+
+	urlSuffix := fmt.Sprintf("/threads/%s/runs", threadID)
+	req, err := c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL(urlSuffix),
+		withBody(struct {
+			RunRequest
+			Stream bool `json:"stream"`
+		}{request, true}),
+		withBetaAssistantVersion(c.config.AssistantVersion))
+	if err != nil {
+		return
+	}
+
+	resp, err := sendRequestStream[MessageDeltaEvent](c, req, opts...)
+	if err != nil {
+		return
+	}
+	resp.accept = isMessageDelta
+	stream = &MessageDeltaStream{
+		streamReader: resp,
+	}
+	return
+}
@@ -0,0 +1,94 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateRunMessageStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/threads/thread_abc123/runs", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		err := json.NewDecoder(r.Body).Decode(&body)
+		checks.NoError(t, err, "Decode error")
+		if body["stream"] != true || body["assistant_id"] != "asst_abc123" {
+			t.Errorf("expected a streaming run request, got %v", body)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, _ := w.(http.Flusher)
+		send := func(s string) {
+			fmt.Fprint(w, s)
+			flusher.Flush()
+		}
+		send("event: thread.run.created\ndata: {\"id\":\"run_abc123\",\"object\":\"thread.run\",\"status\":\"queued\"}\n\n")
+		send("event: thread.message.created\ndata: {\"id\":\"msg_abc123\",\"object\":\"thread.message\"}\n\n")
+		send("event: thread.message.delta\ndata: {\"id\":\"msg_abc123\",\"object\":\"thread.message.delta\",")
+		send("\"delta\":{\"content\":[{\"index\":0,\"type\":\"text\",\"text\":{\"value\":\"Hel\"}}]}}\n\n")
+		send("event: thread.message.delta\ndata: {\"id\":\"msg_abc123\",\"object\":\"thread.message.delta\"," +
+			"\"delta\":{\"content\":[{\"index\":0,\"type\":\"text\",\"text\":{\"value\":\"lo [1]\",\"annotations\":" +
+			"[{\"index\":0,\"type\":\"file_citation\",\"text\":\"[1]\",\"start_index\":3,\"end_index\":6," +
+			"\"file_citation\":{\"file_id\":\"file_1\"}}]}}]}}\n\n")
+		send("event: thread.run.step.delta\ndata: {\"id\":\"step_abc123\",\"object\":\"thread.run.step.delta\"}\n\n")
+		send("event: thread.message.delta\ndata: {\"id\":\"msg_abc123\",\"object\":\"thread.message.delta\"," +
+			"\"delta\":{\"content\":[{\"index\":1,\"type\":\"image_file\",\"image_file\":{\"file_id\":\"file_img\"}}]}}\n\n")
+		send("event: thread.run.completed\ndata: {\"id\":\"run_abc123\",\"object\":\"thread.run\"," +
+			"\"status\":\"completed\"}\n\n")
+		send("event: done\ndata: [DONE]\n\n")
+	})
+
+	ctx := context.Background()
+	request := openai.RunRequest{AssistantID: "asst_abc123"}
+	stream, err := client.CreateRunMessageStream(ctx, "thread_abc123", request)
+	checks.NoError(t, err, "CreateRunMessageStream error")
+	defer stream.Close()
+
+	var deltas []openai.MessageDeltaEvent
+	text := ""
+	for {
+		var delta openai.MessageDeltaEvent
+		delta, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "stream.Recv() failed")
+		deltas = append(deltas, delta)
+		text += delta.Text()
+	}
+	if len(deltas) != 3 || text != "Hello [1]" {
+		t.Fatalf("expected 3 message deltas spelling %q, got %d spelling %q", "Hello [1]", len(deltas), text)
+	}
+	annotation := deltas[1].Delta.Content[0].Text.Annotations[0]
+	if annotation.Index == nil || *annotation.Index != 0 || annotation.FileCitation.FileID != "file_1" {
+		t.Fatalf("unexpected annotation delta: %+v", annotation)
+	}
+	image := deltas[2].Delta.Content[0]
+	if image.Index != 1 || image.ImageFile == nil || image.ImageFile.FileID != "file_img" {
+		t.Fatalf("unexpected image delta: %+v", image)
+	}
+
+	stream, err = client.CreateRunMessageStream(ctx, "thread_abc123", request)
+	checks.NoError(t, err, "CreateRunMessageStream error")
+	defer stream.Close()
+	events := 0
+	for event := range stream.Events(ctx) {
+		checks.NoError(t, event.Err, "stream event error")
+		if event.Response.Object != "thread.message.delta" {
+			t.Fatalf("expected only message deltas, got %+v", event.Response)
+		}
+		events++
+	}
+	if events != 3 {
+		t.Fatalf("expected 3 message deltas from Events, got %d", events)
+	}
+}
@@ -1,11 +1,21 @@
 package openai_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/sashabaranov/go-openai/internal/test"
@@ -241,6 +251,16 @@ func TestMessages(t *testing.T) {
 		t.Fatalf("expected message metadata to get modified")
 	}
 
+	msg, err = client.ModifyMessageWithMetadata(ctx, threadID, messageID,
+		map[string]any{
+			"attempt":  float64(2),
+			"reviewed": true,
+		})
+	checks.NoError(t, err, "ModifyMessageWithMetadata error")
+	if msg.Metadata["attempt"] != float64(2) || msg.Metadata["reviewed"] != true {
+		t.Fatalf("expected non-string message metadata to get modified, got %v", msg.Metadata)
+	}
+
 	msgDel, err := client.DeleteMessage(ctx, threadID, messageID)
 	checks.NoError(t, err, "DeleteMessage error")
 	if msgDel.ID != messageID {
@@ -252,16 +272,18 @@ func TestMessages(t *testing.T) {
 	_, err = client.DeleteMessage(ctx, threadID, "not_exist_id")
 	checks.HasError(t, err, "DeleteMessage error")
 
-	// message files
+	// message files, served by the v1 endpoints
+	v1, err := client.WithAssistantVersion("v1")
+	checks.NoError(t, err, "WithAssistantVersion error")
 	var msgFile openai.MessageFile
-	msgFile, err = client.RetrieveMessageFile(ctx, threadID, messageID, fileID)
+	msgFile, err = v1.RetrieveMessageFile(ctx, threadID, messageID, fileID)
 	checks.NoError(t, err, "RetrieveMessageFile error")
 	if msgFile.ID != fileID {
 		t.Fatalf("unexpected message file id: '%s'", msgFile.ID)
 	}
 
 	var msgFiles openai.MessageFilesList
-	msgFiles, err = client.ListMessageFiles(ctx, threadID, messageID)
+	msgFiles, err = v1.ListMessageFiles(ctx, threadID, messageID)
 	checks.NoError(t, err, "RetrieveMessageFile error")
 	if len(msgFiles.MessageFiles) != 1 {
 		t.Fatalf("unexpected count of message files: %d", len(msgFiles.MessageFiles))
@@ -270,3 +292,2227 @@ func TestMessages(t *testing.T) {
 		t.Fatalf("unexpected message file id: '%s' in list message files", msgFiles.MessageFiles[0].ID)
 	}
 }
+
+func TestMessageFilesV2(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("OpenAI-Beta") != "assistants=v2" {
+			t.Errorf("unexpected beta header %q", r.Header.Get("OpenAI-Beta"))
+		}
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message","created_at":1699061776,
+			"attachments":[{"file_id":"file_doc","tools":[{"type":"file_search"}]},{"file_id":"file_img"}],
+			"content":[{"type":"image_file","image_file":{"file_id":"file_img"}},
+				{"type":"image_file","image_file":{"file_id":"file_chart"}}]}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123/files", func(http.ResponseWriter, *http.Request) {
+		t.Error("the v2 client should not call the removed files endpoint")
+	})
+
+	ctx := context.Background()
+	files, err := client.ListMessageFiles(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "ListMessageFiles error")
+	var ids []string
+	for _, file := range files.MessageFiles {
+		ids = append(ids, file.ID)
+		if file.MessageID != "msg_abc123" || file.CreatedAt != 1699061776 || file.Object != "thread.message.file" {
+			t.Fatalf("unexpected message file: %+v", file)
+		}
+	}
+	if strings.Join(ids, ",") != "file_doc,file_img,file_chart" {
+		t.Fatalf("expected attachments and image files once each, got %v", ids)
+	}
+	if files.HasMore || files.FirstID == nil || *files.FirstID != "file_doc" ||
+		files.LastID == nil || *files.LastID != "file_chart" {
+		t.Fatalf("expected a single complete page, got %+v", files)
+	}
+
+	params := openai.ListMessageFilesParams{Limit: 1, Order: "asc", After: "file_doc"}
+	page, err := client.ListMessageFilesWithParams(ctx, "thread_abc123", "msg_abc123", params)
+	checks.NoError(t, err, "ListMessageFilesWithParams error")
+	if len(page.MessageFiles) != 3 || page.HasMore {
+		t.Fatalf("expected the params to be ignored in v2 mode, got %+v", page)
+	}
+	if _, more := page.NextPageParams(params); more {
+		t.Fatal("expected no following page in v2 mode")
+	}
+	var iterated int
+	for it := client.MessageFilesIterator(ctx, "thread_abc123", "msg_abc123", params); it.Next(); {
+		iterated++
+	}
+	if iterated != 3 {
+		t.Fatalf("expected the iterator to walk the 3 files once, got %d", iterated)
+	}
+
+	file, err := client.RetrieveMessageFile(ctx, "thread_abc123", "msg_abc123", "file_chart")
+	checks.NoError(t, err, "RetrieveMessageFile error")
+	if file.ID != "file_chart" || file.MessageID != "msg_abc123" {
+		t.Fatalf("unexpected message file: %+v", file)
+	}
+	_, err = client.RetrieveMessageFile(ctx, "thread_abc123", "msg_abc123", "file_other")
+	checks.ErrorIs(t, err, openai.ErrMessageFileNotFound, "expected an unreferenced file to fail")
+}
+
+func TestCreateThreadWithMessage(t *testing.T) {
+	threadID := "thread_abc123"
+	messageID := "msg_abc123"
+
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	setupServerForTestMessage(t, server)
+	server.RegisterHandler(
+		"/v1/threads",
+		func(w http.ResponseWriter, r *http.Request) {
+			var request openai.ThreadRequest
+			err := json.NewDecoder(r.Body).Decode(&request)
+			checks.NoError(t, err, "Decode error")
+			if len(request.Messages) != 1 || request.Messages[0].Content != "How does AI work?" {
+				t.Fatalf("expected the initial message inline, got %+v", request.Messages)
+			}
+
+			resBytes, _ := json.Marshal(openai.Thread{
+				ID:        threadID,
+				Object:    "thread",
+				CreatedAt: 1234567890,
+			})
+			fmt.Fprintln(w, string(resBytes))
+		},
+	)
+
+	ctx := context.Background()
+	thread, msg, err := client.CreateThreadWithMessage(ctx, openai.MessageRequest{
+		Role:    openai.ChatMessageRoleUser,
+		Content: "How does AI work?",
+	})
+	checks.NoError(t, err, "CreateThreadWithMessage error")
+	if thread.ID != threadID {
+		t.Fatalf("unexpected thread id: '%s'", thread.ID)
+	}
+	if msg.ID != messageID {
+		t.Fatalf("unexpected message id: '%s'", msg.ID)
+	}
+
+	_, _, err = client.CreateThreadWithMessage(ctx, openai.MessageRequest{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: "How does AI work?",
+	})
+	checks.ErrorIs(t, err, openai.ErrMessageRequestInvalidRole, "expected invalid role error")
+
+	_, _, err = client.CreateThreadWithMessage(ctx, openai.MessageRequest{
+		Role: openai.ChatMessageRoleUser,
+	})
+	checks.ErrorIs(t, err, openai.ErrMessageRequestEmptyContent, "expected empty content error")
+}
+
+func TestCreateThreadWithMessageReadFailure(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"thread_abc123","object":"thread"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error":{"message":"server error","type":"server_error"}}`)
+	})
+
+	thread, _, err := client.CreateThreadWithMessage(context.Background(),
+		openai.MessageRequest{Role: openai.ChatMessageRoleUser, Content: "Hello"})
+	checks.ErrorIs(t, err, openai.ErrThreadMessageNotRead, "expected the failed read to be reported")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the error to unwrap to the API error, got %v", err)
+	}
+	if thread.ID != "thread_abc123" {
+		t.Fatalf("expected the created thread to be returned, got %+v", thread)
+	}
+}
+
+func TestCreateThreadWithMessageMetadata(t *testing.T) {
+	server := test.NewTestServer()
+	var requests int
+	server.RegisterHandler("/v1/threads", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var request openai.ThreadRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if len(request.Messages) != 1 || request.Messages[0].Metadata["tenant"] != "acme" ||
+			request.Messages[0].Metadata["trace"] != "trace_abc123" {
+			t.Fatalf("expected the default metadata to be merged, got %+v", request.Messages)
+		}
+		fmt.Fprint(w, `{"id":"thread_abc123","object":"thread"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"msg_abc123","object":"thread.message"}]}`)
+	})
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.DefaultMetadata = map[string]any{"tenant": "acme"}
+	config.ValidateMetadata = true
+	client := openai.NewClientWithConfig(config)
+
+	ctx := context.Background()
+	request := openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Hello")
+	_, msg, err := client.CreateThreadWithMessage(ctx, request.WithMetadata("trace", "trace_abc123"))
+	checks.NoError(t, err, "CreateThreadWithMessage error")
+	if msg.ID != "msg_abc123" {
+		t.Fatalf("unexpected message id: '%s'", msg.ID)
+	}
+
+	_, _, err = client.CreateThreadWithMessage(ctx, request.WithMetadata("attempt", 1))
+	checks.ErrorIs(t, err, openai.ErrMetadataValueNotValid, "expected invalid metadata to fail")
+	if requests != 1 {
+		t.Fatalf("expected invalid metadata not to be sent, got %d thread requests", requests)
+	}
+}
+
+func TestCountMessages(t *testing.T) {
+	threadID := "thread_abc123"
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	pages := map[string][]string{
+		"":      {"msg_1", "msg_2"},
+		"msg_2": {"msg_3"},
+	}
+	server.RegisterHandler(
+		"/v1/threads/"+threadID+"/messages",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("limit") == "" {
+				t.Fatalf("expected a limit to be set")
+			}
+			after := r.URL.Query().Get("after")
+			list := openai.MessagesList{Object: "list"}
+			for _, id := range pages[after] {
+				list.Messages = append(list.Messages, openai.Message{ID: id, ThreadID: threadID})
+			}
+			if r.URL.Query().Get("limit") == "1" && len(list.Messages) > 1 {
+				list.Messages = list.Messages[:1]
+			}
+			if len(list.Messages) > 0 {
+				list.LastID = &list.Messages[len(list.Messages)-1].ID
+			}
+			_, list.HasMore = pages[*list.LastID]
+			resBytes, _ := json.Marshal(list)
+			fmt.Fprintln(w, string(resBytes))
+		},
+	)
+
+	ctx := context.Background()
+	count, err := client.CountMessages(ctx, threadID)
+	checks.NoError(t, err, "CountMessages error")
+	if count != 3 {
+		t.Fatalf("expected 3 messages, got %d", count)
+	}
+
+	hasAny, err := client.HasAnyMessages(ctx, threadID)
+	checks.NoError(t, err, "HasAnyMessages error")
+	if !hasAny {
+		t.Fatalf("expected thread to have messages")
+	}
+}
+
+func TestRetrieveMessageWithInclude(t *testing.T) {
+	threadID := "thread_abc123"
+	messageID := "msg_abc123"
+	include := "content[*].text.file_search_results[*].content"
+
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler(
+		"/v1/threads/"+threadID+"/messages/"+messageID,
+		func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query()["include[]"]; len(got) != 1 || got[0] != include {
+				t.Fatalf("unexpected include params: %v", got)
+			}
+			fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message","content":[{"type":"text",
+				"text":{"value":"cited","annotations":[],"file_search_results":[{"file_id":"file_abc123",
+				"file_name":"doc.txt","score":0.9,"content":[{"type":"text","text":"chunk"}]}]}}]}`)
+		},
+	)
+
+	msg, err := client.RetrieveMessageWithInclude(context.Background(), threadID, messageID, []string{include})
+	checks.NoError(t, err, "RetrieveMessageWithInclude error")
+	results := msg.Content[0].Text.FileSearchResults
+	if len(results) != 1 || results[0].FileID != "file_abc123" || results[0].Content[0].Text != "chunk" {
+		t.Fatalf("unexpected file search results: %+v", results)
+	}
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestCreateMessageFileIDsMigration(t *testing.T) {
+	threadID := "thread_abc123"
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var received openai.MessageRequest
+	server.RegisterHandler("/v1/threads/"+threadID+"/messages", func(w http.ResponseWriter, r *http.Request) {
+		received = openai.MessageRequest{}
+		err := json.NewDecoder(r.Body).Decode(&received)
+		checks.NoError(t, err, "Decode error")
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	logger := &recordingLogger{}
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Logger = logger
+	config.FileIDsAttachmentTool = openai.AssistantToolTypeCodeInterpreter
+	client := openai.NewClientWithConfig(config)
+
+	ctx := context.Background()
+	request := openai.MessageRequest{
+		Role:    openai.ChatMessageRoleUser,
+		Content: "Summarize the files",
+		FileIds: []string{"file_1", "file_2"},
+	}
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateMessage(ctx, threadID, request)
+		checks.NoError(t, err, "CreateMessage error")
+	}
+	if len(received.FileIds) != 0 || len(received.Attachments) != 2 {
+		t.Fatalf("expected file ids to be converted to attachments, got %+v", received)
+	}
+	if received.Attachments[1].FileID != "file_2" ||
+		received.Attachments[1].Tools[0].Type != string(openai.AssistantToolTypeCodeInterpreter) {
+		t.Fatalf("unexpected attachment: %+v", received.Attachments[1])
+	}
+	if len(logger.messages) != 1 {
+		t.Fatalf("expected a single deprecation warning, got %d", len(logger.messages))
+	}
+
+	config.LegacyFileIDs = true
+	client = openai.NewClientWithConfig(config)
+	_, err := client.CreateMessage(ctx, threadID, request)
+	checks.NoError(t, err, "CreateMessage error")
+	if len(received.FileIds) != 2 || len(received.Attachments) != 0 {
+		t.Fatalf("expected file ids to be passed through, got %+v", received)
+	}
+}
+
+func TestMessagesListSortedByCreatedAt(t *testing.T) {
+	list := openai.MessagesList{Messages: []openai.Message{
+		{ID: "msg_3", CreatedAt: 3},
+		{ID: "msg_1a", CreatedAt: 1},
+		{ID: "msg_2", CreatedAt: 2},
+		{ID: "msg_1b", CreatedAt: 1},
+	}}
+
+	ids := func(messages []openai.Message) string {
+		var s []string
+		for _, m := range messages {
+			s = append(s, m.ID)
+		}
+		return strings.Join(s, ",")
+	}
+	if got := ids(list.SortedByCreatedAt(true)); got != "msg_1a,msg_1b,msg_2,msg_3" {
+		t.Fatalf("unexpected ascending order: %s", got)
+	}
+	if got := ids(list.SortedByCreatedAt(false)); got != "msg_3,msg_2,msg_1a,msg_1b" {
+		t.Fatalf("unexpected descending order: %s", got)
+	}
+	if list.Messages[0].ID != "msg_3" {
+		t.Fatalf("expected the list to be left untouched")
+	}
+}
+
+func TestMessagesListPageParams(t *testing.T) {
+	first, last := "msg_1", "msg_2"
+	params := openai.ListMessagesParams{Limit: 2, Order: "asc", RunID: "run_abc123"}
+	list := openai.MessagesList{FirstID: &first, LastID: &last, HasMore: true}
+
+	next, ok := list.NextPageParams(params)
+	want := openai.ListMessagesParams{Limit: 2, Order: "asc", RunID: "run_abc123", After: last}
+	if !ok || !reflect.DeepEqual(next, want) {
+		t.Fatalf("unexpected next page params %+v, %v", next, ok)
+	}
+	if _, ok = list.PrevPageParams(params); ok {
+		t.Fatalf("expected no previous page before the first page")
+	}
+
+	prev, ok := list.PrevPageParams(next)
+	want = openai.ListMessagesParams{Limit: 2, Order: "asc", RunID: "run_abc123", Before: first}
+	if !ok || !reflect.DeepEqual(prev, want) {
+		t.Fatalf("unexpected previous page params %+v, %v", prev, ok)
+	}
+
+	list.HasMore = false
+	if _, ok = list.NextPageParams(next); ok {
+		t.Fatalf("expected no next page after the last page")
+	}
+	if _, ok = (openai.MessagesList{}).PrevPageParams(next); ok {
+		t.Fatalf("expected no previous page for an empty list")
+	}
+}
+
+func TestListMessagesChronological(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		honorOrder   bool
+		wantRequests int
+	}{
+		{name: "descending", honorOrder: true, wantRequests: 2},
+		{name: "ascending", honorOrder: false, wantRequests: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, server, teardown := setupOpenAITestServer()
+			defer teardown()
+
+			requests := 0
+			server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				ids := []int{100, 101, 102, 103, 104}
+				if tc.honorOrder && r.URL.Query().Get("order") == "desc" {
+					ids = []int{104, 103, 102, 101, 100}
+				}
+				start := 0
+				if after := r.URL.Query().Get("after"); after != "" {
+					for i, id := range ids {
+						if fmt.Sprintf("msg_%d", id) == after {
+							start = i + 1
+						}
+					}
+				}
+				end := start + 2
+				if end > len(ids) {
+					end = len(ids)
+				}
+				list := openai.MessagesList{HasMore: end < len(ids)}
+				for _, id := range ids[start:end] {
+					list.Messages = append(list.Messages, openai.Message{ID: fmt.Sprintf("msg_%d", id), CreatedAt: id})
+				}
+				lastID := list.Messages[len(list.Messages)-1].ID
+				list.LastID = &lastID
+				resBytes, _ := json.Marshal(list)
+				fmt.Fprintln(w, string(resBytes))
+			})
+
+			messages, err := client.ListMessagesChronological(context.Background(), "thread_abc123", time.Unix(102, 0))
+			checks.NoError(t, err, "ListMessagesChronological error")
+			var ids []string
+			for _, msg := range messages {
+				ids = append(ids, msg.ID)
+			}
+			if !reflect.DeepEqual(ids, []string{"msg_102", "msg_103", "msg_104"}) {
+				t.Fatalf("expected messages since 102 oldest first, got %v", ids)
+			}
+			if requests != tc.wantRequests {
+				t.Fatalf("expected %d requests, got %d", tc.wantRequests, requests)
+			}
+		})
+	}
+}
+
+func TestMessageContentAccessors(t *testing.T) {
+	var msg openai.Message
+	err := json.Unmarshal([]byte(`{"id":"msg_abc123","content":[
+		{"type":"text","text":{"value":"Here is the chart","annotations":[]}},
+		{"type":"image_file","image_file":{"file_id":"file_img1"}},
+		{"type":"image_url","image_url":{"url":"https://example.com/a.png"}},
+		{"type":"text","text":{"value":" and a photo.","annotations":[]}},
+		{"type":"image_file","image_file":{"file_id":"file_img2"}}
+	]}`), &msg)
+	checks.NoError(t, err, "Unmarshal error")
+
+	if got := msg.TextContent(); got != "Here is the chart and a photo." {
+		t.Fatalf("unexpected text content: %q", got)
+	}
+	if got := msg.ImageFileIDs(); !reflect.DeepEqual(got, []string{"file_img1", "file_img2"}) {
+		t.Fatalf("unexpected image file IDs: %v", got)
+	}
+	if got := msg.ImageURLs(); !reflect.DeepEqual(got, []string{"https://example.com/a.png"}) {
+		t.Fatalf("unexpected image URLs: %v", got)
+	}
+
+	empty := openai.Message{Content: []openai.MessageContent{{Type: "text"}}}
+	if empty.TextContent() != "" || empty.ImageFileIDs() != nil || empty.ImageURLs() != nil {
+		t.Fatalf("expected no content from a message without parts")
+	}
+}
+
+func TestCreatedTime(t *testing.T) {
+	const createdAt = 1700000000
+	want := time.Unix(createdAt, 0)
+	times := map[string]time.Time{
+		"message":      openai.Message{CreatedAt: createdAt}.CreatedTime(),
+		"message file": openai.MessageFile{CreatedAt: createdAt}.CreatedTime(),
+		"thread":       openai.Thread{CreatedAt: createdAt}.CreatedTime(),
+		"run":          openai.Run{CreatedAt: createdAt}.CreatedTime(),
+		"batch":        openai.Batch{CreatedAt: createdAt}.CreatedTime(),
+		"file":         openai.File{CreatedAt: createdAt}.CreatedTime(),
+	}
+	for name, got := range times {
+		if !got.Equal(want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+	if got := (openai.Message{}).CreatedTime(); got.Unix() != 0 {
+		t.Errorf("expected the Unix epoch for a zero CreatedAt, got %v", got)
+	}
+}
+
+func TestListMessagesWithin(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	const created = 1700000000
+	now := time.Unix(created, 0)
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		list := openai.MessagesList{Object: "list"}
+		for _, age := range []int{0, 30, 59, 60, 61, 3600} {
+			list.Messages = append(list.Messages, openai.Message{
+				ID:        fmt.Sprintf("msg_%d", age),
+				CreatedAt: created - age,
+			})
+		}
+		resBytes, _ := json.Marshal(list)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Now = func() time.Time { return now }
+	client := openai.NewClientWithConfig(config)
+
+	messages, err := client.ListMessagesWithin(context.Background(), "thread_abc123", time.Minute)
+	checks.NoError(t, err, "ListMessagesWithin error")
+	var ids []string
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	if want := []string{"msg_60", "msg_59", "msg_30", "msg_0"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected the messages of the last minute by the frozen clock %v, got %v", want, ids)
+	}
+
+	now = now.Add(time.Hour)
+	messages, err = client.ListMessagesWithin(context.Background(), "thread_abc123", time.Minute)
+	checks.NoError(t, err, "ListMessagesWithin error")
+	if len(messages) != 0 {
+		t.Fatalf("expected no messages an hour later, got %d", len(messages))
+	}
+}
+
+func TestCreateMessageWithIdempotencyKey(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var keys []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"server error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Retry = openai.RetryConfig{MaxRetries: 1, InitialBackoff: time.Millisecond}
+	client := openai.NewClientWithConfig(config)
+
+	msg, err := client.CreateMessageWithIdempotencyKey(context.Background(), "thread_abc123", openai.MessageRequest{
+		Role:    string(openai.ThreadMessageRoleUser),
+		Content: "Hello",
+	}, "key_abc123")
+	checks.NoError(t, err, "CreateMessageWithIdempotencyKey error")
+	if msg.ID != "msg_abc123" {
+		t.Fatalf("unexpected message ID %q", msg.ID)
+	}
+	if !reflect.DeepEqual(keys, []string{"key_abc123", "key_abc123"}) {
+		t.Fatalf("expected the key to be sent on both attempts, got %q", keys)
+	}
+
+	keys = nil
+	_, err = client.CreateMessage(context.Background(), "thread_abc123", openai.MessageRequest{
+		Role:    string(openai.ThreadMessageRoleUser),
+		Content: "Hello",
+	})
+	checks.NoError(t, err, "CreateMessage error")
+	if keys[len(keys)-1] != "" {
+		t.Fatalf("expected no key without one, got %q", keys)
+	}
+}
+
+func TestMessageContentUnknownType(t *testing.T) {
+	var msg openai.Message
+	err := json.Unmarshal([]byte(`{"id":"msg_abc123","content":[
+		{"type":"text","text":{"value":"Hello","annotations":[]}},
+		{"type":"refusal","refusal":"I can't help with that."},
+		{"type":"audio","audio":{"data":"UklGRg==","format":"wav"},"video":"not an object"}
+	]}`), &msg)
+	checks.NoError(t, err, "Unmarshal error")
+
+	if len(msg.Content) != 3 {
+		t.Fatalf("expected 3 content parts, got %d", len(msg.Content))
+	}
+	if msg.Content[0].Raw != nil {
+		t.Errorf("expected no raw payload for a known type, got %s", msg.Content[0].Raw)
+	}
+
+	if refusal := msg.Content[1]; refusal.Refusal == nil || refusal.Raw != nil {
+		t.Errorf("expected the refusal part to be decoded, got %+v", refusal)
+	}
+
+	audio := msg.Content[2]
+	if audio.Type != "audio" || !strings.Contains(string(audio.Raw), `"format":"wav"`) {
+		t.Errorf("unexpected audio part %q: %s", audio.Type, audio.Raw)
+	}
+
+	var content openai.MessageContent
+	err = json.Unmarshal([]byte(`{"type":"image_file","image_file":"not an object"}`), &content)
+	checks.HasError(t, err, "expected malformed known part to fail")
+}
+
+func TestMessageStatus(t *testing.T) {
+	var msg openai.Message
+	err := json.Unmarshal([]byte(`{"id":"msg_abc123","status":"incomplete","incomplete_at":1700000010,
+		"incomplete_details":{"reason":"max_tokens"}}`), &msg)
+	checks.NoError(t, err, "Unmarshal error")
+	if msg.Status != openai.MessageStatusIncomplete || msg.CompletedAt != nil {
+		t.Fatalf("unexpected status %q, completed at %v", msg.Status, msg.CompletedAt)
+	}
+	if msg.IncompleteAt == nil || *msg.IncompleteAt != 1700000010 {
+		t.Fatalf("unexpected incomplete at %v", msg.IncompleteAt)
+	}
+	if msg.IncompleteDetails == nil || msg.IncompleteDetails.Reason != "max_tokens" {
+		t.Fatalf("unexpected incomplete details %+v", msg.IncompleteDetails)
+	}
+
+	msg = openai.Message{}
+	checks.NoError(t, json.Unmarshal([]byte(`{"id":"msg_abc123"}`), &msg), "Unmarshal error")
+	if msg.Status != "" || msg.IncompleteAt != nil || msg.IncompleteDetails != nil {
+		t.Fatalf("expected absent fields to stay empty, got %+v", msg)
+	}
+	data, err := json.Marshal(msg)
+	checks.NoError(t, err, "Marshal error")
+	if strings.Contains(string(data), "status") || strings.Contains(string(data), "incomplete") {
+		t.Fatalf("expected unset fields to be omitted, got %s", data)
+	}
+}
+
+// pngHeader is the signature of a png file, enough for its type to be detected.
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+func TestCreateMessageWithImage(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var purpose, sentBody string
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		checks.NoError(t, r.ParseMultipartForm(1<<20), "ParseMultipartForm error")
+		purpose = r.FormValue("purpose")
+		fmt.Fprint(w, `{"id":"file_img123","object":"file","purpose":"vision"}`)
+	})
+	var deleted bool
+	server.RegisterHandler("/v1/files/file_img123", func(w http.ResponseWriter, r *http.Request) {
+		deleted = r.Method == http.MethodDelete
+		fmt.Fprint(w, `{"id":"file_img123","object":"file","deleted":true}`)
+	})
+	fail := false
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sentBody = string(body)
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"invalid thread"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	ctx := context.Background()
+	msg, fileID, err := client.CreateMessageWithImage(ctx, "thread_abc123", "What is in this image?",
+		strings.NewReader(pngHeader), "photo.png")
+	checks.NoError(t, err, "CreateMessageWithImage error")
+	if msg.ID != "msg_abc123" || fileID != "file_img123" {
+		t.Fatalf("unexpected message %q and file %q", msg.ID, fileID)
+	}
+	if purpose != string(openai.PurposeVision) {
+		t.Fatalf("expected the vision purpose, got %q", purpose)
+	}
+	if !strings.Contains(sentBody, `"image_file":{"file_id":"file_img123"}`) ||
+		!strings.Contains(sentBody, "What is in this image?") {
+		t.Fatalf("unexpected message request %s", sentBody)
+	}
+	if deleted {
+		t.Fatalf("expected the file to be kept")
+	}
+
+	fail = true
+	_, fileID, err = client.CreateMessageWithImage(ctx, "thread_abc123", "", strings.NewReader(pngHeader), "photo.png")
+	checks.HasError(t, err, "expected the message creation to fail")
+	if !deleted || fileID != "" {
+		t.Fatalf("expected the uploaded file to be deleted, got deleted %v and file %q", deleted, fileID)
+	}
+	if strings.Contains(sentBody, `"type":"text"`) {
+		t.Fatalf("expected no text part for empty text, got %s", sentBody)
+	}
+}
+
+func TestNewMessageRequest(t *testing.T) {
+	base := openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Hello").WithMetadata("source", "test")
+	request := base.
+		WithAttachment(openai.ThreadAttachment{FileID: "file_abc123"}).
+		WithMetadata("lang", "en").
+		WithFileIDs("file_def456")
+	want := openai.MessageRequest{
+		Role:        "user",
+		Content:     "Hello",
+		Attachments: []openai.ThreadAttachment{{FileID: "file_abc123"}},
+		Metadata:    map[string]any{"source": "test", "lang": "en"},
+		FileIds:     []string{"file_def456"},
+	}
+	if !reflect.DeepEqual(request, want) {
+		t.Fatalf("unexpected request %+v", request)
+	}
+	if len(base.Metadata) != 1 || base.Attachments != nil {
+		t.Fatalf("expected the With methods not to modify the original request, got %+v", base)
+	}
+
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests int
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	ctx := context.Background()
+	_, err := client.CreateMessage(ctx, "thread_abc123", openai.NewMessageRequest("system", "Hello"))
+	checks.ErrorIs(t, err, openai.ErrMessageRequestInvalidRole, "expected invalid role to fail")
+	if !strings.Contains(err.Error(), `"system"`) {
+		t.Fatalf("expected the error to name the rejected role, got %v", err)
+	}
+	_, _, err = client.CreateThreadWithMessage(ctx, openai.NewMessageRequest("system", "Hello"))
+	checks.ErrorIs(t, err, openai.ErrMessageRequestInvalidRole, "expected invalid role to fail")
+	_, err = client.CreateMessage(ctx, "thread_abc123", openai.NewMessageRequest("", "Hello"))
+	checks.ErrorIs(t, err, openai.ErrMessageRequestInvalidRole, "expected missing role to fail")
+	if requests != 0 {
+		t.Fatalf("expected invalid requests not to be sent, got %d requests", requests)
+	}
+
+	_, err = client.CreateMessage(ctx, "thread_abc123", openai.NewMessageRequest("assistant", "Hello"))
+	checks.NoError(t, err, "CreateMessage error")
+}
+
+func TestDeleteMessageNotFound(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":{"message":"No message found with id 'msg_missing'.",`+
+			`"type":"invalid_request_error","param":null,"code":"message_not_found"}}`)
+	})
+
+	status, err := client.DeleteMessage(context.Background(), "thread_abc123", "msg_missing")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got %v", err)
+	}
+	if apiErr.HTTPStatusCode != http.StatusNotFound || apiErr.Code != "message_not_found" {
+		t.Fatalf("unexpected API error: status %d, code %v", apiErr.HTTPStatusCode, apiErr.Code)
+	}
+	if status.Deleted {
+		t.Fatalf("expected the message not to be reported as deleted")
+	}
+}
+
+func TestDeleteMessageIfExists(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var requests []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		if r.Method == http.MethodDelete {
+			fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message.deleted","deleted":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_missing", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":{"message":"No message found with id 'msg_missing'.",`+
+			`"type":"invalid_request_error","param":null,"code":"message_not_found"}}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintln(w, `{"error":{"message":"server error","type":"server_error"}}`)
+	})
+
+	ctx := context.Background()
+	status, existed, err := client.DeleteMessageIfExists(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "DeleteMessageIfExists error")
+	if !existed || !status.Deleted || !reflect.DeepEqual(requests, []string{http.MethodGet, http.MethodDelete}) {
+		t.Fatalf("expected the message to be retrieved then deleted, got %+v, %v after %v", status, existed, requests)
+	}
+
+	requests = nil
+	status, existed, err = client.DeleteMessageIfExists(ctx, "thread_abc123", "msg_missing")
+	checks.NoError(t, err, "expected a missing message not to be an error")
+	if existed || status.Deleted || !reflect.DeepEqual(requests, []string{http.MethodGet}) {
+		t.Fatalf("expected a missing message not to be deleted, got %+v, %v after %v", status, existed, requests)
+	}
+
+	_, existed, err = client.DeleteMessageIfExists(ctx, "thread_abc123", "msg_broken")
+	checks.HasError(t, err, "expected other errors to be returned")
+	if existed {
+		t.Fatal("expected a failed retrieval not to report the message as existing")
+	}
+}
+
+func TestListMessagesThreadNotFound(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	deleted := false
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		if deleted {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":{"message":"No thread found with id 'thread_abc123'.",`+
+				`"type":"invalid_request_error","param":null,"code":null}}`)
+			return
+		}
+		if after := r.URL.Query().Get("after"); after == "msg_missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":{"message":"No message found with id 'msg_missing'.",`+
+				`"type":"invalid_request_error","param":null,"code":"message_not_found"}}`)
+			return
+		}
+		deleted = true
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"msg_1","object":"thread.message"}],`+
+			`"first_id":"msg_1","last_id":"msg_1","has_more":true}`)
+	})
+
+	ctx := context.Background()
+	iterator := client.MessagesIterator(ctx, "thread_abc123", openai.ListMessagesParams{Limit: 1})
+	var ids []string
+	for iterator.Next() {
+		ids = append(ids, iterator.Message().ID)
+	}
+	checks.ErrorIs(t, iterator.Err(), openai.ErrThreadNotFound, "expected the deleted thread to be reported")
+	var apiErr *openai.APIError
+	if !errors.As(iterator.Err(), &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound || len(ids) != 1 {
+		t.Fatalf("expected the API error after the first page, got %v after %v", iterator.Err(), ids)
+	}
+
+	_, err := client.ListMessage(ctx, "thread_abc123", nil, nil, nil, nil, nil)
+	checks.ErrorIs(t, err, openai.ErrThreadNotFound, "expected ListMessage to report the deleted thread")
+	_, err = client.ListMessageIDs(ctx, "thread_abc123", openai.ListMessagesParams{})
+	checks.ErrorIs(t, err, openai.ErrThreadNotFound, "expected ListMessageIDs to report the deleted thread")
+
+	deleted = false
+	_, err = client.ListMessagesWithParams(ctx, "thread_abc123", openai.ListMessagesParams{After: "msg_missing"})
+	if errors.Is(err, openai.ErrThreadNotFound) || !errors.As(err, &apiErr) || apiErr.Code != "message_not_found" {
+		t.Fatalf("expected an unknown cursor message not to be a missing thread, got %v", err)
+	}
+}
+
+func TestMessageContentConstructors(t *testing.T) {
+	parts := []openai.MessageContent{
+		openai.NewTextMessageContent("Compare these"),
+		openai.NewImageFileMessageContent("file_abc123"),
+		openai.NewImageURLMessageContent("https://example.com/cat.png", openai.ImageURLDetailHigh),
+	}
+	data, err := json.Marshal(parts)
+	checks.NoError(t, err, "Marshal error")
+	want := `[{"type":"text","text":{"value":"Compare these","annotations":null}},` +
+		`{"type":"image_file","image_file":{"file_id":"file_abc123"}},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"high"}}]`
+	if string(data) != want {
+		t.Fatalf("unexpected wire format %s", data)
+	}
+
+	for i, want := range []string{
+		openai.MessageContentTypeText, openai.MessageContentTypeImageFile, openai.MessageContentTypeImageURL,
+	} {
+		if parts[i].Type != want {
+			t.Fatalf("expected part %d to be %q, got %q", i, want, parts[i].Type)
+		}
+	}
+
+	var decoded []openai.MessageContent
+	checks.NoError(t, json.Unmarshal(data, &decoded), "Unmarshal error")
+	if decoded[2].ImageURL.Detail != openai.ImageURLDetailHigh {
+		t.Fatalf("unexpected decoded detail %q", decoded[2].ImageURL.Detail)
+	}
+
+	for _, part := range parts {
+		checks.NoError(t, part.Validate(), "expected a valid part")
+	}
+	typo := openai.NewImageURLMessageContent("https://example.com/cat.png", "hi")
+	checks.ErrorIs(t, typo.Validate(), openai.ErrMessageContentInvalid, "expected an unknown detail to be rejected")
+}
+
+func TestCreateMessageValidateContent(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	requests := 0
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	video := func(fps float64, detail openai.ImageURLDetail) openai.MessageRequest {
+		return openai.MessageRequest{
+			Role: string(openai.ThreadMessageRoleUser),
+			ContentParts: []openai.MessageContent{
+				{Type: "text", Text: &openai.MessageText{Value: "What happens here?"}},
+				{Type: "video_url", VideoURL: &openai.VideoURL{URL: "https://example.com/a.mp4", Fps: fps, Detail: detail}},
+			},
+		}
+	}
+	checks.NoError(t, video(2, "low").ContentParts[1].Validate(), "expected a valid video part")
+	checks.NoError(t, video(0, "").ContentParts[1].Validate(), "expected unset fps and detail to be valid")
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	ctx := context.Background()
+	_, err := openai.NewClientWithConfig(config).CreateMessage(ctx, "thread_abc123", video(-1, "ultra"))
+	checks.NoError(t, err, "content should not be validated by default")
+
+	config.ValidateMessageContent = true
+	client := openai.NewClientWithConfig(config)
+	_, err = client.CreateMessage(ctx, "thread_abc123", video(-1, "low"))
+	checks.ErrorIs(t, err, openai.ErrMessageContentInvalid, "expected negative fps to be rejected")
+	_, err = client.CreateMessage(ctx, "thread_abc123", video(2, "ultra"))
+	checks.ErrorIs(t, err, openai.ErrMessageContentInvalid, "expected an unknown detail to be rejected")
+	if !strings.Contains(err.Error(), "content parts[1]") {
+		t.Fatalf("expected the error to name the invalid part, got %v", err)
+	}
+	_, err = client.CreateMessage(ctx, "thread_abc123", video(2, "high"))
+	checks.NoError(t, err, "CreateMessage error")
+	if requests != 2 {
+		t.Fatalf("expected invalid content to be rejected before sending, got %d requests", requests)
+	}
+}
+
+func TestVideoMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		video openai.Video
+		want  string
+		err   error
+	}{
+		{"empty", openai.Video{}, "null", nil},
+		{"url", openai.Video{URL: "https://example.com/a.mp4"}, `"https://example.com/a.mp4"`, nil},
+		{"image urls", openai.Video{ImageURLs: []string{"a.png", "b.png"}}, `["a.png","b.png"]`, nil},
+		{"both", openai.Video{URL: "https://example.com/a.mp4", ImageURLs: []string{"a.png"}}, "",
+			openai.ErrVideoFieldsMisused},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			video := tc.video
+			data, err := json.Marshal(&video)
+			if tc.err != nil {
+				checks.ErrorIs(t, err, tc.err, "expected MarshalJSON to fail")
+				checks.ErrorIs(t, video.Validate(), tc.err, "expected Validate to fail")
+				return
+			}
+			checks.NoError(t, err, "MarshalJSON error")
+			checks.NoError(t, video.Validate(), "Validate error")
+			if string(data) != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, data)
+			}
+
+			var decoded openai.Video
+			checks.NoError(t, json.Unmarshal(data, &decoded), "UnmarshalJSON error")
+			if !reflect.DeepEqual(decoded, tc.video) {
+				t.Fatalf("expected %+v after a round trip, got %+v", tc.video, decoded)
+			}
+		})
+	}
+
+	part := openai.MessageContent{Type: "video", Video: &openai.Video{URL: "a.mp4", ImageURLs: []string{"a.png"}}}
+	checks.ErrorIs(t, part.Validate(), openai.ErrMessageContentInvalid, "expected the part to be invalid")
+}
+
+func TestCollectThreadCitations(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("order") != "asc" {
+			t.Errorf("expected ascending order, got %q", r.URL.Query().Get("order"))
+		}
+		if r.URL.Query().Get("after") == "" {
+			fmt.Fprintln(w, `{"object":"list","data":[{"id":"msg_1","content":[{"type":"text","text":{"value":"a",
+				"annotations":[{"type":"file_citation","text":"[1]","start_index":0,"end_index":3,
+				"file_citation":{"file_id":"file_1"}},{"type":"url_citation","text":"[2]",
+				"url_citation":{"url":"https://example.com","title":"Example"}}]}}]}],
+				"last_id":"msg_1","has_more":true}`)
+			return
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[{"id":"msg_2","content":[{"type":"image_file",
+			"image_file":{"file_id":"file_img"}},{"type":"text","text":{"value":"b","annotations":[
+			{"type":"file_citation","text":"[3]","file_citation":{"file_id":"file_1"}},
+			{"type":"file_path","text":"sandbox:/out.csv","file_path":{"file_id":"file_2"}}]}}]}],
+			"last_id":"msg_2","has_more":false}`)
+	})
+
+	annotations, err := client.CollectThreadCitations(context.Background(), "thread_abc123")
+	checks.NoError(t, err, "CollectThreadCitations error")
+	if len(annotations) != 3 {
+		t.Fatalf("expected 3 unique sources, got %+v", annotations)
+	}
+	if annotations[0].FileCitation.FileID != "file_1" || annotations[0].Text != "[1]" || annotations[0].EndIndex != 3 {
+		t.Fatalf("expected the first citation of file_1 to be kept, got %+v", annotations[0])
+	}
+	if annotations[1].URLCitation.Title != "Example" || annotations[2].FilePath.FileID != "file_2" {
+		t.Fatalf("unexpected annotations: %+v, %+v", annotations[1], annotations[2])
+	}
+}
+
+func TestListMessagesWithParams(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var query string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprintln(w, `{"object":"list","data":[{"id":"msg_abc123"}],"has_more":false}`)
+	})
+
+	ctx := context.Background()
+	msgs, err := client.ListMessagesWithParams(ctx, "thread_abc123", openai.ListMessagesParams{})
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	if query != "" || len(msgs.Messages) != 1 {
+		t.Fatalf("expected zero params to send no query, got %q", query)
+	}
+
+	params := openai.ListMessagesParams{Limit: 1, Order: "desc", After: "obj_foo", Before: "obj_bar", RunID: "run_abc123"}
+	_, err = client.ListMessagesWithParams(ctx, "thread_abc123", params)
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	withParams := query
+
+	_, err = client.ListMessage(ctx, "thread_abc123",
+		&params.Limit, &params.Order, &params.After, &params.Before, &params.RunID)
+	checks.NoError(t, err, "ListMessage error")
+	if withParams != query || withParams != "after=obj_foo&before=obj_bar&limit=1&order=desc&run_id=run_abc123" {
+		t.Fatalf("expected matching queries, got %q and %q", withParams, query)
+	}
+	params = openai.ListMessagesParams{Include: []string{
+		"step_details.tool_calls[*].file_search.results[*].content",
+		"content[*].text.file_search_results[*].content",
+	}}
+	_, err = client.ListMessagesWithParams(ctx, "thread_abc123", params)
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	values, err := url.ParseQuery(query)
+	checks.NoError(t, err, "ParseQuery error")
+	if !reflect.DeepEqual(values["include[]"], params.Include) || len(values) != 1 {
+		t.Fatalf("expected include values as repeated include[] params, got %q", query)
+	}
+	if strings.Count(query, "include%5B%5D=") != 2 || strings.Contains(query, ",") {
+		t.Fatalf("expected include values not to be joined, got %q", query)
+	}
+
+	var empty string
+	zero := 0
+	_, err = client.ListMessage(ctx, "thread_abc123", &zero, &empty, &empty, &empty, &empty)
+	checks.NoError(t, err, "ListMessage error")
+	if query != "" {
+		t.Fatalf("expected empty values to send no query, got %q", query)
+	}
+	_, err = client.ListMessagesWithParams(ctx, "thread_abc123", openai.ListMessagesParams{Include: []string{""}})
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	if query != "" {
+		t.Fatalf("expected an empty include value to be skipped, got %q", query)
+	}
+}
+
+func TestMessagesIterator(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	pages := map[string][]string{
+		"":      {"msg_1", "msg_2"},
+		"msg_2": {"msg_3"},
+	}
+	var requests int
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if query.Get("limit") != "2" || query.Get("order") != "asc" || query.Get("before") != "" {
+			t.Fatalf("unexpected query %s", r.URL.RawQuery)
+		}
+		list := openai.MessagesList{}
+		for _, id := range pages[query.Get("after")] {
+			list.Messages = append(list.Messages, openai.Message{ID: id})
+		}
+		list.LastID = &list.Messages[len(list.Messages)-1].ID
+		_, list.HasMore = pages[*list.LastID]
+		resBytes, _ := json.Marshal(list)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	params := openai.ListMessagesParams{Limit: 2, Order: "asc"}
+	it := client.MessagesIterator(context.Background(), "thread_abc123", params)
+	if requests != 0 {
+		t.Fatalf("expected pages to be fetched lazily, got %d requests", requests)
+	}
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Message().ID)
+	}
+	checks.NoError(t, it.Err(), "MessagesIterator error")
+	if strings.Join(ids, ",") != "msg_1,msg_2,msg_3" {
+		t.Fatalf("unexpected messages: %v", ids)
+	}
+	if requests != 2 || it.Next() {
+		t.Fatalf("expected iteration to stop after 2 page requests, got %d", requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it = client.MessagesIterator(ctx, "thread_abc123", params)
+	if !it.Next() {
+		t.Fatalf("expected a first message, got %v", it.Err())
+	}
+	cancel()
+	remaining := 0
+	for it.Next() {
+		remaining++
+	}
+	if remaining != 1 {
+		t.Fatalf("expected only the rest of the fetched page, got %d messages", remaining)
+	}
+	checks.ErrorIs(t, it.Err(), context.Canceled, "expected context error between pages")
+}
+
+func TestMessageRequestContentParts(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	parts := []openai.MessageContent{
+		{Type: "text", Text: &openai.MessageText{Value: "What is in this image?"}},
+		{Type: "image_url", ImageURL: &openai.ImageURL{URL: "https://example.com/cat.png", Detail: "low"}},
+		{Type: "image_file", ImageFile: &openai.ImageFile{FileID: "file_abc123"}},
+	}
+	var sent []openai.MessageContent
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Content json.RawMessage `json:"content"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		checks.NoError(t, err, "Decode error")
+		if !strings.Contains(string(body.Content), `"text":"What is in this image?"`) {
+			t.Fatalf("expected text parts to carry a plain string, got %s", body.Content)
+		}
+		err = json.Unmarshal(body.Content, &sent)
+		checks.NoError(t, err, "content should unmarshal into message content")
+		fmt.Fprintln(w, `{"id":"msg_abc123","content":`+string(body.Content)+`}`)
+	})
+
+	msg, err := client.CreateMessage(context.Background(), "thread_abc123", openai.MessageRequest{
+		Role:         openai.ChatMessageRoleUser,
+		ContentParts: parts,
+	})
+	checks.NoError(t, err, "CreateMessage error")
+	if !reflect.DeepEqual(sent, parts) || !reflect.DeepEqual(msg.Content, parts) {
+		t.Fatalf("expected the parts to round-trip, got %+v", sent)
+	}
+
+	data, err := json.Marshal(openai.MessageRequest{Role: "user", ContentParts: parts[:1]})
+	checks.NoError(t, err, "Marshal error")
+	if string(data) != `{"role":"user","content":"What is in this image?"}` {
+		t.Fatalf("expected a lone text part to be sent as a string, got %s", data)
+	}
+
+	data, err = json.Marshal(openai.ThreadMessage{Role: openai.ThreadMessageRoleUser, Content: "Hello"})
+	checks.NoError(t, err, "Marshal error")
+	if string(data) != `{"role":"user","content":"Hello"}` {
+		t.Fatalf("expected string content, got %s", data)
+	}
+
+	_, err = json.Marshal(openai.MessageRequest{Role: "user", Content: "Hello", ContentParts: parts})
+	checks.ErrorIs(t, err, openai.ErrContentFieldsMisused, "expected misused content fields error")
+}
+
+func TestMessageTextParsedAnnotations(t *testing.T) {
+	data := []byte(`{"type":"text","text":{"value":"See [1] and [2].","annotations":[
+		{"type":"file_citation","text":"[1]","start_index":4,"end_index":7,
+			"file_citation":{"file_id":"file_1","quote":"cited text"}},
+		{"type":"file_path","text":"[2]","start_index":12,"end_index":15,"file_path":{"file_id":"file_2"}},
+		{"type":"container_file_citation","text":"[3]","file_citation":"unexpected"},
+		"not an object"]}}`)
+
+	var content openai.MessageContent
+	err := json.Unmarshal(data, &content)
+	checks.NoError(t, err, "Unmarshal error")
+	annotations := content.Text.ParsedAnnotations
+	if len(annotations) != 4 || len(content.Text.Annotations) != 4 {
+		t.Fatalf("expected 4 annotations, got %+v", annotations)
+	}
+
+	citation := annotations[0]
+	if citation.Type != openai.MessageAnnotationTypeFileCitation || citation.FileCitation.FileID != "file_1" ||
+		citation.FileCitation.Quote != "cited text" || citation.StartIndex != 4 || citation.EndIndex != 7 {
+		t.Fatalf("unexpected file citation: %+v", citation)
+	}
+	if annotations[1].Type != openai.MessageAnnotationTypeFilePath || annotations[1].FilePath.FileID != "file_2" {
+		t.Fatalf("unexpected file path: %+v", annotations[1])
+	}
+	if annotations[2].Type != "container_file_citation" || annotations[2].FileCitation != nil ||
+		!strings.Contains(string(annotations[2].Raw), `"unexpected"`) {
+		t.Fatalf("expected the unknown annotation to fall back to its raw JSON, got %+v", annotations[2])
+	}
+	if annotations[3].Type != "" || string(annotations[3].Raw) != `"not an object"` {
+		t.Fatalf("expected a raw fallback for a non-object annotation, got %+v", annotations[3])
+	}
+
+	typed, err := content.Text.TypedAnnotations()
+	checks.NoError(t, err, "TypedAnnotations error")
+	if len(typed) != 4 {
+		t.Fatalf("expected TypedAnnotations to return the parsed annotations, got %+v", typed)
+	}
+}
+
+func TestCreateMessageAttachmentTools(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var body string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		checks.NoError(t, err, "ReadAll error")
+		body = string(data)
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	_, err := client.CreateMessage(context.Background(), "thread_abc123", openai.MessageRequest{
+		Role:        openai.ChatMessageRoleUser,
+		Content:     "Summarize the report",
+		Attachments: []openai.ThreadAttachment{openai.NewThreadAttachment("file_abc123", openai.AssistantToolTypeFileSearch)},
+	})
+	checks.NoError(t, err, "CreateMessage error")
+	const expected = `"attachments":[{"file_id":"file_abc123","tools":[{"type":"file_search"}]}]`
+	if !strings.Contains(body, expected) {
+		t.Fatalf("expected %s in the request body, got %s", expected, body)
+	}
+}
+
+func TestMessageRequestOptions(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var requests []*http.Request
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"object":"list","data":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Method == http.MethodDelete {
+			fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message.deleted","deleted":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+	opts := []openai.RequestOption{
+		openai.WithRequestHeader("X-Tenant-ID", "tenant_abc123"),
+		openai.WithRequestQuery("trace", "1"),
+	}
+
+	_, err := client.CreateMessage(ctx, "thread_abc123", openai.MessageRequest{
+		Role:    string(openai.ThreadMessageRoleUser),
+		Content: "Hello",
+	}, opts...)
+	checks.NoError(t, err, "CreateMessage error")
+	limit := 10
+	_, err = client.ListMessage(ctx, "thread_abc123", &limit, nil, nil, nil, nil, opts...)
+	checks.NoError(t, err, "ListMessage error")
+	_, err = client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123", opts...)
+	checks.NoError(t, err, "RetrieveMessage error")
+	_, err = client.ModifyMessage(ctx, "thread_abc123", "msg_abc123", map[string]string{"k": "v"}, opts...)
+	checks.NoError(t, err, "ModifyMessage error")
+	_, err = client.DeleteMessage(ctx, "thread_abc123", "msg_abc123", opts...)
+	checks.NoError(t, err, "DeleteMessage error")
+
+	if len(requests) != 5 {
+		t.Fatalf("expected 5 requests, got %d", len(requests))
+	}
+	for _, r := range requests {
+		if got := r.Header.Get("X-Tenant-ID"); got != "tenant_abc123" {
+			t.Errorf("%s %s: expected the custom header, got %q", r.Method, r.URL, got)
+		}
+		if got := r.URL.Query().Get("trace"); got != "1" {
+			t.Errorf("%s %s: expected the custom query parameter, got %q", r.Method, r.URL, got)
+		}
+		if got := r.Header.Get("OpenAI-Beta"); got == "" {
+			t.Errorf("%s %s: expected the assistants beta header to be kept", r.Method, r.URL)
+		}
+	}
+	if got := requests[1].URL.Query().Get("limit"); got != "10" {
+		t.Errorf("expected the list parameters to be kept, got limit %q", got)
+	}
+}
+
+func TestMessageFilesPagination(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	pages := map[string][]string{
+		"":       {"file_1", "file_2"},
+		"file_2": {"file_3"},
+	}
+	var requests int
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123/files",
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			query := r.URL.Query()
+			if query.Get("limit") != "2" || query.Get("order") != "asc" {
+				t.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+			list := openai.MessageFilesList{Object: "list"}
+			for _, id := range pages[query.Get("after")] {
+				list.MessageFiles = append(list.MessageFiles, openai.MessageFile{ID: id, MessageID: "msg_abc123"})
+			}
+			list.FirstID = &list.MessageFiles[0].ID
+			list.LastID = &list.MessageFiles[len(list.MessageFiles)-1].ID
+			_, list.HasMore = pages[*list.LastID]
+			resBytes, _ := json.Marshal(list)
+			fmt.Fprintln(w, string(resBytes))
+		})
+
+	v1, err := client.WithAssistantVersion("v1")
+	checks.NoError(t, err, "WithAssistantVersion error")
+	ctx := context.Background()
+	params := openai.ListMessageFilesParams{Limit: 2, Order: "asc"}
+
+	first, err := v1.ListMessageFilesWithParams(ctx, "thread_abc123", "msg_abc123", params)
+	checks.NoError(t, err, "ListMessageFilesWithParams error")
+	if len(first.MessageFiles) != 2 || !first.HasMore || *first.FirstID != "file_1" || *first.LastID != "file_2" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	next, ok := first.NextPageParams(params)
+	if !ok || next.After != "file_2" || next.Limit != 2 {
+		t.Fatalf("unexpected next page params: %+v, %v", next, ok)
+	}
+	second, err := v1.ListMessageFilesWithParams(ctx, "thread_abc123", "msg_abc123", next)
+	checks.NoError(t, err, "ListMessageFilesWithParams error")
+	if len(second.MessageFiles) != 1 || second.HasMore {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+	if _, ok = second.NextPageParams(next); ok {
+		t.Fatal("expected no page after the last one")
+	}
+
+	requests = 0
+	it := v1.MessageFilesIterator(ctx, "thread_abc123", "msg_abc123", params)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.MessageFile().ID)
+	}
+	checks.NoError(t, it.Err(), "MessageFilesIterator error")
+	if strings.Join(ids, ",") != "file_1,file_2,file_3" {
+		t.Fatalf("unexpected files: %v", ids)
+	}
+	if requests != 2 || it.Next() {
+		t.Fatalf("expected iteration to stop after 2 page requests, got %d", requests)
+	}
+}
+
+func TestMessageRefusal(t *testing.T) {
+	var msg openai.Message
+	err := json.Unmarshal([]byte(`{"id":"msg_abc123","role":"assistant","content":[
+		{"type":"refusal","refusal":"I can't help with that."}
+	]}`), &msg)
+	checks.NoError(t, err, "Unmarshal error")
+
+	refusal, ok := msg.RefusalText()
+	if !ok || refusal != "I can't help with that." {
+		t.Fatalf("unexpected refusal %q, %v", refusal, ok)
+	}
+	if msg.TextContent() != "" {
+		t.Fatalf("expected no text, got %q", msg.TextContent())
+	}
+	if part := msg.Content[0]; part.Type != openai.MessageContentTypeRefusal || part.Raw != nil {
+		t.Fatalf("unexpected refusal part %+v", part)
+	}
+	data, err := json.Marshal(msg.Content[0])
+	checks.NoError(t, err, "Marshal error")
+	if string(data) != `{"type":"refusal","refusal":"I can't help with that."}` {
+		t.Fatalf("unexpected wire format %s", data)
+	}
+
+	msg.Content = []openai.MessageContent{openai.NewTextMessageContent("")}
+	if _, ok = msg.RefusalText(); ok {
+		t.Fatal("expected an empty answer not to be a refusal")
+	}
+}
+
+func TestMessageReasoning(t *testing.T) {
+	var msg openai.Message
+	err := json.Unmarshal([]byte(`{"id":"msg_abc123","object":"thread.message","role":"assistant",
+		"assistant_id":"asst_abc123","run_id":"run_abc123","content":[
+		{"type":"reasoning","reasoning":{"id":"rs_abc123","summary":[
+			{"type":"summary_text","text":"**Comparing totals**\n\nThe user wants the sum."},
+			{"type":"summary_text","text":"Adding the two quarters gives 42."}
+		],"encrypted_content":"gAAAAABo"}},
+		{"type":"text","text":{"value":"The total is 42.","annotations":[]}}
+	]}`), &msg)
+	checks.NoError(t, err, "Unmarshal error")
+
+	part := msg.Content[0]
+	if part.Type != openai.MessageContentTypeReasoning || part.Reasoning == nil || part.Raw != nil {
+		t.Fatalf("unexpected reasoning part %+v", part)
+	}
+	if summary := part.Reasoning.Summary; len(summary) != 2 ||
+		summary[1].Type != openai.MessageReasoningSummaryTypeText {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	want := "**Comparing totals**\n\nThe user wants the sum.\n\nAdding the two quarters gives 42."
+	if got := msg.ReasoningSummary(); got != want {
+		t.Fatalf("unexpected reasoning summary %q", got)
+	}
+	if msg.TextContent() != "The total is 42." {
+		t.Fatalf("expected the reasoning not to be text content, got %q", msg.TextContent())
+	}
+
+	var raw struct {
+		ID               string `json:"id"`
+		EncryptedContent string `json:"encrypted_content"`
+	}
+	checks.NoError(t, json.Unmarshal(part.Reasoning.Raw, &raw), "Unmarshal raw error")
+	if raw.ID != "rs_abc123" || raw.EncryptedContent != "gAAAAABo" {
+		t.Fatalf("expected unknown reasoning fields to be kept in Raw, got %+v", raw)
+	}
+
+	msg.Content = msg.Content[1:]
+	if msg.ReasoningSummary() != "" {
+		t.Fatalf("expected no reasoning summary, got %q", msg.ReasoningSummary())
+	}
+}
+
+func TestMessageStringContent(t *testing.T) {
+	var fromParts, fromString openai.Message
+	checks.NoError(t, json.Unmarshal([]byte(`{"id":"msg_abc123","object":"thread.message","role":"assistant",
+		"content":[{"type":"text","text":{"value":"Hello there.","annotations":[]}}],"metadata":{}}`), &fromParts),
+		"Unmarshal error")
+	checks.NoError(t, json.Unmarshal([]byte(`{"id":"msg_abc123","object":"thread.message","role":"assistant",
+		"content":"Hello there.","metadata":{}}`), &fromString), "Unmarshal error")
+	if !reflect.DeepEqual(fromString, fromParts) {
+		t.Fatalf("expected equivalent messages, got %+v and %+v", fromString, fromParts)
+	}
+	if fromString.TextContent() != "Hello there." {
+		t.Fatalf("unexpected text %q", fromString.TextContent())
+	}
+
+	var list openai.MessagesList
+	checks.NoError(t, json.Unmarshal([]byte(`{"object":"list","data":[
+		{"id":"msg_1","role":"user","content":"Hi"},
+		{"id":"msg_2","role":"assistant","content":""}
+	]}`), &list), "Unmarshal error")
+	if list.Messages[0].TextContent() != "Hi" || len(list.Messages[1].Content) != 0 {
+		t.Fatalf("unexpected messages %+v", list.Messages)
+	}
+
+	var msg openai.Message
+	checks.HasError(t, json.Unmarshal([]byte(`{"id":"msg_abc123","content":42}`), &msg),
+		"expected content of another type to fail")
+}
+
+func TestMessageRequestBodyDeterministic(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var bodies []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	metadata := map[string]any{"nested": map[string]any{"z": 1, "a": 2}}
+	for i := 0; i < 16; i++ {
+		metadata[fmt.Sprintf("key_%02d", 15-i)] = i
+	}
+	requests := []openai.MessageRequest{
+		{Role: string(openai.ThreadMessageRoleUser), Content: "Hello", Metadata: metadata},
+		{
+			Role:         string(openai.ThreadMessageRoleUser),
+			ContentParts: []openai.MessageContent{openai.NewTextMessageContent("Hello")},
+			Metadata:     metadata,
+		},
+	}
+	for _, request := range requests {
+		bodies = nil
+		for i := 0; i < 5; i++ {
+			_, err := client.CreateMessage(context.Background(), "thread_abc123", request)
+			checks.NoError(t, err, "CreateMessage error")
+		}
+		for _, body := range bodies[1:] {
+			if body != bodies[0] {
+				t.Fatalf("expected identical bodies, got\n%s\n%s", bodies[0], body)
+			}
+		}
+		if !strings.Contains(bodies[0], `"metadata":{"key_00":15,"key_01":14,`) ||
+			!strings.Contains(bodies[0], `"nested":{"a":2,"z":1}`) {
+			t.Fatalf("expected metadata keys in sorted order, got %s", bodies[0])
+		}
+	}
+}
+
+func TestCopyMessage(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/threads/thread_src/messages/msg_text", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"msg_text","object":"thread.message","thread_id":"thread_src","role":"user",
+			"content":[{"type":"text","text":{"value":"Hello there","annotations":[{"type":"file_path"}]}}],
+			"attachments":[{"file_id":"file_doc","tools":[{"type":"file_search"}]}],
+			"metadata":{"source":"import","attempt":2}}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_src/messages/msg_image", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"msg_image","object":"thread.message","thread_id":"thread_src","role":"user",
+			"content":[{"type":"text","text":{"value":"Look","annotations":[]}},
+				{"type":"image_file","image_file":{"file_id":"file_img"}},
+				{"type":"refusal","refusal":"not sent"}],
+			"metadata":{}}`)
+	})
+	var bodies []map[string]any
+	server.RegisterHandler("/v1/threads/thread_dst/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		var body map[string]any
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		bodies = append(bodies, body)
+		fmt.Fprint(w, `{"id":"msg_copy","object":"thread.message","thread_id":"thread_dst"}`)
+	})
+
+	ctx := context.Background()
+	msg, err := client.CopyMessage(ctx, "thread_src", "msg_text", "thread_dst")
+	checks.NoError(t, err, "CopyMessage error")
+	if msg.ID != "msg_copy" || msg.ThreadID != "thread_dst" {
+		t.Fatalf("unexpected copied message %+v", msg)
+	}
+	want := map[string]any{
+		"role":        "user",
+		"content":     "Hello there",
+		"attachments": []any{map[string]any{"file_id": "file_doc", "tools": []any{map[string]any{"type": "file_search"}}}},
+		"metadata":    map[string]any{"source": "import", "attempt": float64(2)},
+	}
+	if !reflect.DeepEqual(bodies[0], want) {
+		t.Fatalf("unexpected copy request %v", bodies[0])
+	}
+
+	_, err = client.CopyMessage(ctx, "thread_src", "msg_image", "thread_dst")
+	checks.NoError(t, err, "CopyMessage error")
+	want = map[string]any{
+		"role": "user",
+		"content": []any{
+			map[string]any{"type": "text", "text": "Look"},
+			map[string]any{"type": "image_file", "image_file": map[string]any{"file_id": "file_img"}},
+		},
+	}
+	if !reflect.DeepEqual(bodies[1], want) {
+		t.Fatalf("unexpected copy request %v", bodies[1])
+	}
+
+	_, err = client.CopyMessage(ctx, "thread_src", "msg_missing", "thread_dst")
+	checks.HasError(t, err, "expected a missing source message to fail")
+	if len(bodies) != 2 {
+		t.Fatalf("expected no message to be created for a failed retrieval, got %d", len(bodies))
+	}
+}
+
+func TestMessageRequestFileIDsByVersion(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var body map[string]json.RawMessage
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	ctx := context.Background()
+	request := openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Summarize the files").
+		WithAttachment(openai.NewThreadAttachment("file_doc", openai.AssistantToolTypeFileSearch)).
+		WithFileIDs("file_legacy")
+
+	_, err := client.CreateMessage(ctx, "thread_abc123", request)
+	checks.NoError(t, err, "CreateMessage error")
+	if _, ok := body["file_ids"]; ok {
+		t.Fatalf("expected file_ids to be omitted on v2, got %s", body["file_ids"])
+	}
+	want := `[{"file_id":"file_doc","tools":[{"type":"file_search"}]},` +
+		`{"file_id":"file_legacy","tools":[{"type":"file_search"}]}]`
+	if string(body["attachments"]) != want {
+		t.Fatalf("unexpected v2 attachments %s", body["attachments"])
+	}
+
+	v1, err := client.WithAssistantVersion("v1")
+	checks.NoError(t, err, "WithAssistantVersion error")
+	_, err = v1.CreateMessage(ctx, "thread_abc123", request)
+	checks.NoError(t, err, "CreateMessage error")
+	if string(body["file_ids"]) != `["file_legacy"]` {
+		t.Fatalf("expected file_ids to be sent unchanged on v1, got %s", body["file_ids"])
+	}
+	if string(body["attachments"]) != `[{"file_id":"file_doc","tools":[{"type":"file_search"}]}]` {
+		t.Fatalf("unexpected v1 attachments %s", body["attachments"])
+	}
+}
+
+func TestCreateMessageFileIDsOnly(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var body map[string]json.RawMessage
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":"run_abc123","object":"thread.run","thread_id":"thread_abc123"}`)
+	})
+
+	ctx := context.Background()
+	request := openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "").WithFileIDs("file-1")
+	_, err := client.CreateMessage(ctx, "thread_abc123", request)
+	checks.NoError(t, err, "expected a message with only file ids to be sent")
+	if string(body["attachments"]) != `[{"file_id":"file-1","tools":[{"type":"file_search"}]}]` {
+		t.Fatalf("unexpected v2 attachments %s", body["attachments"])
+	}
+
+	v1, err := client.WithAssistantVersion("v1")
+	checks.NoError(t, err, "WithAssistantVersion error")
+	_, err = v1.CreateMessage(ctx, "thread_abc123", request)
+	checks.NoError(t, err, "expected a v1 message with only file ids to be sent")
+	if string(body["file_ids"]) != `["file-1"]` {
+		t.Fatalf("expected file_ids to be sent on v1, got %s", body["file_ids"])
+	}
+
+	_, err = client.CreateRun(ctx, "thread_abc123", openai.RunRequest{
+		AssistantID:        "asst_abc123",
+		AdditionalMessages: []openai.ThreadMessage{request.ThreadMessage()},
+	})
+	checks.NoError(t, err, "expected an additional message with only file ids to be sent")
+}
+
+func TestListMessageNotModified(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	const etag = `"list-v1"`
+	var sendETag bool
+	var conditions []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		conditions = append(conditions, r.Header.Get("If-None-Match"))
+		if sendETag {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"msg_abc123","object":"thread.message"}],"has_more":false}`)
+	})
+
+	ctx := context.Background()
+	params := openai.ListMessagesParams{Limit: 10}
+	sendETag = true
+	list, err := client.ListMessagesWithParams(ctx, "thread_abc123", params)
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	if list.ETag() != etag || len(list.Messages) != 1 {
+		t.Fatalf("expected the list with its ETag, got %q and %d messages", list.ETag(), len(list.Messages))
+	}
+
+	unchanged, err := client.ListMessagesWithParams(ctx, "thread_abc123", params, openai.WithIfNoneMatch(list.ETag()))
+	checks.ErrorIs(t, err, openai.ErrNotModified, "expected an unchanged list to be reported")
+	if len(unchanged.Messages) != 0 || conditions[1] != etag {
+		t.Fatalf("expected a conditional request and no messages, got %q and %d messages",
+			conditions[1], len(unchanged.Messages))
+	}
+
+	sendETag = false
+	list, err = client.ListMessagesWithParams(ctx, "thread_abc123", params)
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	_, err = client.ListMessagesWithParams(ctx, "thread_abc123", params, openai.WithIfNoneMatch(list.ETag()))
+	checks.NoError(t, err, "expected a server without ETags to always return the list")
+	if list.ETag() != "" || conditions[3] != "" {
+		t.Fatalf("expected no ETag and no condition, got %q and %q", list.ETag(), conditions[3])
+	}
+}
+
+func TestResponseDecoder(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	// The gateway wraps every payload in an envelope and renames role to author.
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"payload":{"id":"msg_abc123","object":"thread.message","author":"assistant"}}`)
+	})
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"payload\":{\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	var decoded int
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.ResponseDecoder = func(data []byte, v any) error {
+		decoded++
+		var envelope struct {
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return err
+		}
+		payload := bytes.Replace(envelope.Payload, []byte(`"author":`), []byte(`"role":`), 1)
+		return json.Unmarshal(payload, v)
+	}
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+
+	msg, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "RetrieveMessage error")
+	if msg.ID != "msg_abc123" || msg.Role != "assistant" || decoded != 1 {
+		t.Fatalf("expected the message to be decoded by the hook, got %+v after %d calls", msg, decoded)
+	}
+
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	}
+	stream, err := client.CreateChatCompletionStream(ctx, request)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	response, err := stream.Recv()
+	stream.Close()
+	checks.NoError(t, err, "Recv error")
+	if len(response.Choices) != 0 || decoded != 1 {
+		t.Fatalf("expected streams not to use the hook by default, got %+v", response)
+	}
+
+	stream, err = client.CreateChatCompletionStream(ctx, request, openai.WithStreamResponseDecoder())
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	response, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if response.Choices[0].Delta.Content != "hi" || decoded != 2 {
+		t.Fatalf("expected the stream event to be decoded by the hook, got %+v", response)
+	}
+}
+
+func TestRetrieveMessageCacheBust(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var headers []http.Header
+	var queries []url.Values
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		queries = append(queries, r.URL.Query())
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message","metadata":{"reviewed":"true"}}`)
+	})
+
+	ctx := context.Background()
+	_, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "RetrieveMessage error")
+	if headers[0].Get("Cache-Control") != "" || headers[0].Get("Pragma") != "" {
+		t.Fatalf("expected no cache headers by default, got %v", headers[0])
+	}
+
+	msg, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123",
+		openai.WithCacheBust(), openai.WithRequestQuery("nonce", "1"))
+	checks.NoError(t, err, "RetrieveMessage error")
+	if headers[1].Get("Cache-Control") != "no-cache" || headers[1].Get("Pragma") != "no-cache" {
+		t.Fatalf("expected no-cache headers, got %v", headers[1])
+	}
+	if queries[1].Get("nonce") != "1" || msg.Metadata["reviewed"] != "true" {
+		t.Fatalf("unexpected query %v or message %+v", queries[1], msg)
+	}
+}
+
+func TestCreateMessageRawBody(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var sent []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = append(sent, string(body))
+		if len(sent) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"server error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Retry = openai.RetryConfig{MaxRetries: 1, InitialBackoff: time.Millisecond}
+	client := openai.NewClientWithConfig(config)
+
+	request := openai.MessageRequest{
+		Role: string(openai.ThreadMessageRoleUser),
+		ContentParts: []openai.MessageContent{
+			openai.NewTextMessageContent("what is in this image?"),
+			openai.NewImageURLMessageContent("https://example.com/cat.png", openai.ImageURLDetailHigh),
+		},
+		Metadata: map[string]any{"source": "test"},
+	}
+	var captured [][]byte
+	_, err := client.CreateMessage(context.Background(), "thread_abc123", request,
+		openai.WithRawBody(func(body []byte) { captured = append(captured, body) }))
+	checks.NoError(t, err, "CreateMessage error")
+
+	want, err := json.Marshal(request)
+	checks.NoError(t, err, "Marshal error")
+	if len(captured) != 1 || !bytes.Equal(bytes.TrimSpace(captured[0]), want) {
+		t.Fatalf("expected the body to be captured once as %s, got %q", want, captured)
+	}
+	if len(sent) != 2 || sent[0] != string(captured[0]) || sent[1] != sent[0] {
+		t.Fatalf("expected both attempts to send the captured body, got %q", sent)
+	}
+
+	captured = nil
+	_, err = client.RetrieveMessage(context.Background(), "thread_abc123", "msg_abc123",
+		openai.WithRawBody(func(body []byte) { captured = append(captured, body) }))
+	checks.HasError(t, err, "expected the unregistered path to fail")
+	if len(captured) != 1 || captured[0] != nil {
+		t.Fatalf("expected a nil body for a request without one, got %q", captured)
+	}
+}
+
+func TestMessageUnexpectedObjectType(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	body := `{"id":"run_abc123","object":"thread.run","status":"queued"}`
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	ctx := context.Background()
+	_, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.ErrorIs(t, err, openai.ErrUnexpectedObjectType, "expected a run to be rejected as a message")
+	if !strings.Contains(err.Error(), `expected thread.message, got "thread.run"`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, err = client.ListMessagesWithParams(ctx, "thread_abc123", openai.ListMessagesParams{})
+	checks.ErrorIs(t, err, openai.ErrUnexpectedObjectType, "expected a run to be rejected as a list")
+
+	body = `{"id":"msg_abc123","object":"thread.message"}`
+	_, err = client.DeleteMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.ErrorIs(t, err, openai.ErrUnexpectedObjectType, "expected a message to be rejected as a deletion status")
+	msg, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "RetrieveMessage error")
+	if msg.ID != "msg_abc123" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	body = `{"id":"msg_abc123"}`
+	_, err = client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "expected a response without an object field not to be checked")
+}
+
+func TestCreateMessageGzip(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var encodings []string
+	var contents []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			reader, err := gzip.NewReader(r.Body)
+			checks.NoError(t, err, "gzip.NewReader error")
+			body = reader
+		}
+		var request struct {
+			Content string `json:"content"`
+		}
+		checks.NoError(t, json.NewDecoder(body).Decode(&request), "Decode error")
+		contents = append(contents, request.Content)
+
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		fmt.Fprintf(writer, `{"id":"msg_%d","object":"thread.message"}`, len(contents))
+		writer.Close()
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.GzipMessageRequestsOver = 1024
+	client := openai.NewClientWithConfig(config)
+
+	large := strings.Repeat("a long seed document ", 1000)
+	// Setting Accept-Encoding keeps the transport from decompressing the response.
+	acceptGzip := openai.WithRequestHeader("Accept-Encoding", "gzip")
+	msg, err := client.CreateMessage(context.Background(), "thread_abc123",
+		openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), large), acceptGzip)
+	checks.NoError(t, err, "CreateMessage error")
+	if msg.ID != "msg_1" || encodings[0] != "gzip" || contents[0] != large {
+		t.Fatalf("expected a compressed round trip, got %q with encoding %q", msg.ID, encodings[0])
+	}
+
+	msg, err = client.CreateMessage(context.Background(), "thread_abc123",
+		openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "short"), acceptGzip)
+	checks.NoError(t, err, "CreateMessage error")
+	if msg.ID != "msg_2" || encodings[1] != "" || contents[1] != "short" {
+		t.Fatalf("expected a small body not to be compressed, got %q with encoding %q", msg.ID, encodings[1])
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	body := `{"object":"list","data":[{"id":"msg_abc123","object":"thread.message","content":[` +
+		`{"type":"text","text":{"value":"` + strings.Repeat("x", 4096) + `","annotations":[]}}]}],"has_more":false}`
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	for _, tc := range []struct {
+		name    string
+		limit   int
+		wantErr bool
+	}{
+		{name: "unlimited", limit: 0},
+		{name: "at the limit", limit: len(body)},
+		{name: "over the limit", limit: len(body) - 1, wantErr: true},
+		{name: "far over the limit", limit: 16, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := openai.DefaultConfig(test.GetTestToken())
+			config.BaseURL = ts.URL + "/v1"
+			config.MaxResponseBytes = tc.limit
+			client := openai.NewClientWithConfig(config)
+
+			list, err := client.ListMessagesWithParams(context.Background(), "thread_abc123", openai.ListMessagesParams{})
+			if tc.wantErr {
+				checks.ErrorIs(t, err, openai.ErrResponseTooLarge, "expected the body to be too large")
+				return
+			}
+			checks.NoError(t, err, "ListMessagesWithParams error")
+			if len(list.Messages) != 1 || len(list.Messages[0].TextContent()) != 4096 {
+				t.Fatalf("unexpected list %+v", list)
+			}
+		})
+	}
+}
+
+func TestMessageRequestID(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req_list")
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req_retrieve")
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req_missing")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"No message found","type":"invalid_request_error"}}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_gateway", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req_gateway")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "bad gateway")
+	})
+
+	ctx := context.Background()
+	list, err := client.ListMessagesWithParams(ctx, "thread_abc123", openai.ListMessagesParams{})
+	checks.NoError(t, err, "ListMessagesWithParams error")
+	msg, err := client.RetrieveMessage(ctx, "thread_abc123", "msg_abc123")
+	checks.NoError(t, err, "RetrieveMessage error")
+	if list.RequestID() != "req_list" || msg.RequestID() != "req_retrieve" {
+		t.Fatalf("unexpected request IDs %q and %q", list.RequestID(), msg.RequestID())
+	}
+
+	_, err = client.RetrieveMessage(ctx, "thread_abc123", "msg_missing")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req_missing" {
+		t.Fatalf("expected the request ID on the API error, got %#v", err)
+	}
+
+	_, err = client.RetrieveMessage(ctx, "thread_abc123", "msg_gateway")
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) || reqErr.RequestID != "req_gateway" {
+		t.Fatalf("expected the request ID on the request error, got %#v", err)
+	}
+}
+
+func TestMessageRequestAttachmentOnly(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var body map[string]json.RawMessage
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	ctx := context.Background()
+	attachment := openai.NewThreadAttachment("file_sheet", openai.AssistantToolTypeCodeInterpreter)
+	_, err := client.CreateMessage(ctx, "thread_abc123",
+		openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "").WithAttachment(attachment))
+	checks.NoError(t, err, "CreateMessage error")
+	if _, ok := body["content"]; ok {
+		t.Fatalf("expected no content for an attachment-only message, got %s", body["content"])
+	}
+	if len(body["attachments"]) == 0 {
+		t.Fatalf("expected the attachments to be sent, got %v", body)
+	}
+
+	_, err = client.CreateMessage(ctx, "thread_abc123",
+		openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Plot the sheet").WithAttachment(attachment))
+	checks.NoError(t, err, "CreateMessage error")
+	if string(body["content"]) != `"Plot the sheet"` {
+		t.Fatalf("expected the content with the attachments, got %s", body["content"])
+	}
+
+	_, err = client.CreateMessage(ctx, "thread_abc123", openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Hi"))
+	checks.NoError(t, err, "CreateMessage error")
+	if string(body["content"]) != `"Hi"` {
+		t.Fatalf("expected the content of a content-only message, got %s", body["content"])
+	}
+	if _, ok := body["attachments"]; ok {
+		t.Fatalf("expected no attachments, got %s", body["attachments"])
+	}
+
+	data, err := json.Marshal(openai.MessageRequest{Role: "user"})
+	checks.NoError(t, err, "Marshal error")
+	if string(data) != `{"role":"user","content":""}` {
+		t.Fatalf("expected an empty content without attachments to be kept, got %s", data)
+	}
+}
+
+func TestMessagesForRun(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	runA, runB := "run_a", "run_b"
+	pages := map[string][]openai.Message{
+		"": {
+			{ID: "msg_1", Role: "user"},
+			{ID: "msg_2", Role: "assistant", RunID: &runA},
+			{ID: "msg_3", Role: "assistant", RunID: &runB},
+		},
+		"msg_3": {
+			{ID: "msg_4", Role: "assistant", RunID: &runA},
+		},
+	}
+	var queries []string
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		queries = append(queries, r.URL.RawQuery)
+		if query.Get("run_id") != runA || query.Get("order") != "asc" || query.Get("limit") != "100" {
+			t.Fatalf("unexpected query %s", r.URL.RawQuery)
+		}
+		// The mock ignores the run_id filter and lists the messages of both runs.
+		list := openai.MessagesList{Messages: pages[query.Get("after")]}
+		list.LastID = &list.Messages[len(list.Messages)-1].ID
+		_, list.HasMore = pages[*list.LastID]
+		resBytes, _ := json.Marshal(list)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	messages, err := client.MessagesForRun(context.Background(), "thread_abc123", runA)
+	checks.NoError(t, err, "MessagesForRun error")
+	var ids []string
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+	if strings.Join(ids, ",") != "msg_2,msg_4" || len(queries) != 2 {
+		t.Fatalf("expected the messages of run_a over 2 pages, got %v after %d requests", ids, len(queries))
+	}
+
+	if id, ok := messages[0].RunIDValue(); !ok || id != runA {
+		t.Fatalf("unexpected run ID %q, %v", id, ok)
+	}
+	if _, ok := pages[""][0].RunIDValue(); ok {
+		t.Fatal("expected a user message to have no run")
+	}
+}
+
+func TestCreateMessageWithImageChecks(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var uploads int
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, _ *http.Request) {
+		uploads++
+		fmt.Fprint(w, `{"id":"file_img123","object":"file","purpose":"vision"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.MaxImageUploadBytes = 64
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		image   io.Reader
+		wantErr error
+	}{
+		{"png", strings.NewReader(pngHeader), nil},
+		{"jpeg", strings.NewReader("\xff\xd8\xff\xe0\x00\x10JFIF"), nil},
+		{"oversize", io.MultiReader(strings.NewReader(pngHeader), neverEndingReader{}), openai.ErrImageTooLarge},
+		{"pdf", strings.NewReader("%PDF-1.7\n"), openai.ErrImageTypeUnsupported},
+		{"svg", strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg"/>`), openai.ErrImageTypeUnsupported},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uploads = 0
+			_, fileID, err := client.CreateMessageWithImage(ctx, "thread_abc123", "", tc.image, "image.png")
+			if tc.wantErr == nil {
+				checks.NoError(t, err, "CreateMessageWithImage error")
+				if uploads != 1 || fileID == "" {
+					t.Fatalf("expected the image to be uploaded, got %d uploads", uploads)
+				}
+				return
+			}
+			checks.ErrorIs(t, err, tc.wantErr, "unexpected image error")
+			if uploads != 0 || fileID != "" {
+				t.Fatalf("expected no upload, got %d uploads and file %q", uploads, fileID)
+			}
+		})
+	}
+}
+
+// neverEndingReader is an endless stream of zeros.
+type neverEndingReader struct{}
+
+func (neverEndingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestMessagesConcurrentUse(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var bodies []openai.MessageRequest
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"msg_abc123","object":"thread.message"}]}`)
+			return
+		}
+		var body openai.MessageRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		fmt.Fprint(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.DefaultMetadata = map[string]any{"tenant": "acme"}
+	client := openai.NewClientWithConfig(config)
+
+	// One request value is shared by every goroutine; the client must only read it.
+	request := openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Hello").
+		WithMetadata("trace", "trace_abc123").
+		WithAttachment(openai.NewThreadAttachment("file_doc", openai.AssistantToolTypeFileSearch)).
+		WithFileIDs("file_legacy")
+	params := openai.ListMessagesParams{Limit: 10, Include: []string{"attachments"}}
+
+	const workers = 32
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	go func() {
+		// Changing the config after creating the client must not affect it.
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				config.DefaultMetadata["tenant"] = strconv.Itoa(i)
+			}
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.CreateMessage(ctx, "thread_abc123", request)
+			checks.NoError(t, err, "CreateMessage error")
+			_, err = client.ListMessagesWithParams(ctx, "thread_abc123", params)
+			checks.NoError(t, err, "ListMessagesWithParams error")
+		}()
+	}
+	wg.Wait()
+	close(done)
+
+	if len(bodies) != workers {
+		t.Fatalf("expected %d messages, got %d", workers, len(bodies))
+	}
+	for _, body := range bodies {
+		if body.Metadata["tenant"] != "acme" || body.Metadata["trace"] != "trace_abc123" || len(body.Attachments) != 2 {
+			t.Fatalf("unexpected message request %+v", body)
+		}
+	}
+	if len(request.Metadata) != 1 || len(request.Attachments) != 1 || len(request.FileIds) != 1 {
+		t.Fatalf("expected the shared request to be left unchanged, got %+v", request)
+	}
+}
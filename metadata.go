@@ -0,0 +1,138 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// maxMetadataKeys is the maximum number of key-value pairs the API accepts in metadata.
+	maxMetadataKeys        = 16
+	maxMetadataKeyLength   = 64
+	maxMetadataValueLength = 512
+)
+
+var (
+	ErrMetadataTooManyKeys   = errors.New("metadata can have at most 16 keys")
+	ErrMetadataKeyTooLong    = errors.New("metadata keys can have at most 64 characters")
+	ErrMetadataValueTooLong  = errors.New("metadata values can have at most 512 characters")
+	ErrMetadataValueNotValid = errors.New("metadata values must be strings")
+)
+
+// validateMetadata checks metadata against the limits enforced by the API.
+func validateMetadata(metadata map[string]any) error {
+	if len(metadata) > maxMetadataKeys {
+		return fmt.Errorf("%w: got %d keys", ErrMetadataTooManyKeys, len(metadata))
+	}
+	for k, v := range metadata {
+		if len(k) > maxMetadataKeyLength {
+			return fmt.Errorf("%w: %q", ErrMetadataKeyTooLong, k)
+		}
+		value, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%w: key %q has a %T value", ErrMetadataValueNotValid, k, v)
+		}
+		if len(value) > maxMetadataValueLength {
+			return fmt.Errorf("%w: key %q", ErrMetadataValueTooLong, k)
+		}
+	}
+	return nil
+}
+
+// validateMessageMetadata applies validateMetadata when ValidateMetadata is set.
+func (c *Client) validateMessageMetadata(metadata map[string]any) error {
+	if !c.config.ValidateMetadata {
+		return nil
+	}
+	return validateMetadata(metadata)
+}
+
+// mergeDefaultMetadata returns metadata extended with the client's DefaultMetadata.
+// Keys already present in metadata take precedence. The caller's map is never modified.
+func (c *Client) mergeDefaultMetadata(metadata map[string]any) (map[string]any, error) {
+	if len(c.config.DefaultMetadata) == 0 {
+		return metadata, nil
+	}
+
+	merged := make(map[string]any, len(metadata)+len(c.config.DefaultMetadata))
+	for k, v := range c.config.DefaultMetadata {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	if len(merged) > maxMetadataKeys {
+		return nil, fmt.Errorf("%w: got %d keys after merging default metadata", ErrMetadataTooManyKeys, len(merged))
+	}
+	return merged, nil
+}
+
+// mergeDefaultMessagesMetadata applies mergeDefaultMetadata to a copy of messages.
+func (c *Client) mergeDefaultMessagesMetadata(messages []ThreadMessage) ([]ThreadMessage, error) {
+	if len(c.config.DefaultMetadata) == 0 || len(messages) == 0 {
+		return messages, nil
+	}
+
+	merged := make([]ThreadMessage, len(messages))
+	for i, message := range messages {
+		metadata, err := c.mergeDefaultMetadata(message.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		message.Metadata = metadata
+		merged[i] = message
+	}
+	return merged, nil
+}
+
+// mergeDefaultThreadMetadata applies the client's DefaultMetadata to a thread request
+// and the messages it is created with.
+func (c *Client) mergeDefaultThreadMetadata(request ThreadRequest) (ThreadRequest, error) {
+	var err error
+	request.Metadata, err = c.mergeDefaultMetadata(request.Metadata)
+	if err != nil {
+		return request, err
+	}
+	request.Messages, err = c.mergeDefaultMessagesMetadata(request.Messages)
+	return request, err
+}
+
+// mergeDefaultRunMetadata applies the client's DefaultMetadata to a run request
+// and its additional messages.
+func (c *Client) mergeDefaultRunMetadata(request RunRequest) (RunRequest, error) {
+	var err error
+	request.Metadata, err = c.mergeDefaultMetadata(request.Metadata)
+	if err != nil {
+		return request, err
+	}
+	request.AdditionalMessages, err = c.mergeDefaultMessagesMetadata(request.AdditionalMessages)
+	return request, err
+}
+
+// metadataString returns the metadata value of key as a string. The server stores
+// metadata values as strings, but values decoded into map[string]any may arrive as
+// numbers or booleans; they are formatted back without exponent or trailing zeros.
+// It returns false when the key is missing or null.
+func metadataString(metadata map[string]any, key string) (string, bool) {
+	value, ok := metadata[key]
+	if !ok || value == nil {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value), true
+	}
+	return string(data), true
+}
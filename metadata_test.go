@@ -0,0 +1,215 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestDefaultMetadata(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.DefaultMetadata = map[string]any{"tenant": "acme", "source": "default"}
+	client := openai.NewClientWithConfig(config)
+
+	var received map[string]any
+	decode := func(r *http.Request) {
+		received = nil
+		err := json.NewDecoder(r.Body).Decode(&received)
+		checks.NoError(t, err, "Decode error")
+	}
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, r *http.Request) {
+		decode(r)
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/runs", func(w http.ResponseWriter, r *http.Request) {
+		decode(r)
+		fmt.Fprintln(w, `{"id":"run_abc123","object":"thread.run"}`)
+	})
+	server.RegisterHandler("/v1/threads", func(w http.ResponseWriter, r *http.Request) {
+		decode(r)
+		fmt.Fprintln(w, `{"id":"thread_abc123","object":"thread"}`)
+	})
+
+	ctx := context.Background()
+	requestMetadata := map[string]any{"source": "request"}
+	_, err := client.CreateMessage(ctx, "thread_abc123", openai.MessageRequest{
+		Role:     openai.ChatMessageRoleUser,
+		Content:  "Hello",
+		Metadata: requestMetadata,
+	})
+	checks.NoError(t, err, "CreateMessage error")
+	metadata, _ := received["metadata"].(map[string]any)
+	if metadata["tenant"] != "acme" || metadata["source"] != "request" {
+		t.Fatalf("unexpected merged message metadata: %v", metadata)
+	}
+	if len(requestMetadata) != 1 || requestMetadata["source"] != "request" {
+		t.Fatalf("caller metadata was modified: %v", requestMetadata)
+	}
+	if len(config.DefaultMetadata) != 2 || config.DefaultMetadata["source"] != "default" {
+		t.Fatalf("default metadata was modified: %v", config.DefaultMetadata)
+	}
+
+	_, err = client.CreateMessages(ctx, "thread_abc123", []openai.MessageRequest{
+		openai.NewMessageRequest(openai.ChatMessageRoleUser, "Hello"),
+	})
+	checks.NoError(t, err, "CreateMessages error")
+	metadata, _ = received["metadata"].(map[string]any)
+	if metadata["tenant"] != "acme" || metadata["source"] != "default" {
+		t.Fatalf("unexpected default message metadata: %v", metadata)
+	}
+
+	_, err = client.CreateRun(ctx, "thread_abc123", openai.RunRequest{AssistantID: "asst_abc123"})
+	checks.NoError(t, err, "CreateRun error")
+	metadata, _ = received["metadata"].(map[string]any)
+	if metadata["tenant"] != "acme" {
+		t.Fatalf("unexpected merged run metadata: %v", metadata)
+	}
+
+	_, err = client.CreateThread(ctx, openai.ThreadRequest{
+		Messages: []openai.ThreadMessage{{Role: openai.ThreadMessageRoleUser, Content: "Hello"}},
+	})
+	checks.NoError(t, err, "CreateThread error")
+	metadata, _ = received["metadata"].(map[string]any)
+	if metadata["tenant"] != "acme" {
+		t.Fatalf("unexpected merged thread metadata: %v", metadata)
+	}
+	messages, _ := received["messages"].([]any)
+	if len(messages) != 1 {
+		t.Fatalf("unexpected thread messages: %v", received["messages"])
+	}
+	if message, _ := messages[0].(map[string]any); message["metadata"] == nil {
+		t.Fatalf("expected default metadata on inline thread message")
+	}
+
+	tooMany := map[string]any{}
+	for i := 0; i < 15; i++ {
+		tooMany["key"+strconv.Itoa(i)] = i
+	}
+	_, err = client.CreateMessage(ctx, "thread_abc123", openai.MessageRequest{
+		Role:     openai.ChatMessageRoleUser,
+		Content:  "Hello",
+		Metadata: tooMany,
+	})
+	checks.ErrorIs(t, err, openai.ErrMetadataTooManyKeys, "expected too many keys error")
+}
+
+func TestMessageMetadataString(t *testing.T) {
+	var msg openai.Message
+	err := json.Unmarshal([]byte(`{"id":"msg_abc123","metadata":{"count":42,"big":12345678901,"ratio":0.5,
+		"flag":true,"name":"run-7","none":null}}`), &msg)
+	checks.NoError(t, err, "Unmarshal error")
+
+	for key, want := range map[string]string{
+		"count": "42",
+		"big":   "12345678901",
+		"ratio": "0.5",
+		"flag":  "true",
+		"name":  "run-7",
+	} {
+		got, ok := msg.MetadataString(key)
+		if !ok || got != want {
+			t.Errorf("%s: expected %q, got %q (%v)", key, want, got, ok)
+		}
+	}
+	if _, ok := msg.MetadataString("none"); ok {
+		t.Errorf("expected a null value to be reported as missing")
+	}
+	if _, ok := msg.MetadataString("missing"); ok {
+		t.Errorf("expected a missing key to be reported as missing")
+	}
+
+	msg.Metadata = map[string]any{"count": json.Number("42"), "int": 7}
+	if got, _ := msg.MetadataString("count"); got != "42" {
+		t.Errorf("expected json.Number to be kept, got %q", got)
+	}
+	if got, _ := msg.MetadataString("int"); got != "7" {
+		t.Errorf("expected an int to be formatted, got %q", got)
+	}
+}
+
+func TestValidateMessageMetadata(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var requests int
+	server.RegisterHandler("/v1/threads/thread_abc123/messages", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_abc123/messages/msg_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"id":"msg_abc123","object":"thread.message"}`)
+	})
+
+	tooMany := map[string]any{}
+	for i := 0; i < 17; i++ {
+		tooMany["key"+strconv.Itoa(i)] = "value"
+	}
+	testCases := []struct {
+		name     string
+		metadata map[string]any
+		wantErr  error
+		wantKey  string
+	}{
+		{"valid", map[string]any{"tenant": "acme", strings.Repeat("k", 64): strings.Repeat("v", 512)}, nil, ""},
+		{"too many keys", tooMany, openai.ErrMetadataTooManyKeys, ""},
+		{"key too long", map[string]any{strings.Repeat("k", 65): "value"}, openai.ErrMetadataKeyTooLong, "kkkk"},
+		{"value too long", map[string]any{"quote": strings.Repeat("v", 513)}, openai.ErrMetadataValueTooLong, "quote"},
+		{"value not a string", map[string]any{"attempt": 2}, openai.ErrMetadataValueNotValid, "attempt"},
+	}
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.ValidateMetadata = true
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = 0
+			_, createErr := client.CreateMessage(ctx, "thread_abc123", openai.MessageRequest{
+				Role:     openai.ChatMessageRoleUser,
+				Content:  "Hello",
+				Metadata: tc.metadata,
+			})
+			_, modifyErr := client.ModifyMessageWithMetadata(ctx, "thread_abc123", "msg_abc123", tc.metadata)
+			for _, err := range []error{createErr, modifyErr} {
+				if tc.wantErr == nil {
+					checks.NoError(t, err, "expected valid metadata to be sent")
+					continue
+				}
+				checks.ErrorIs(t, err, tc.wantErr, "unexpected validation error")
+				if !strings.Contains(err.Error(), tc.wantKey) {
+					t.Errorf("expected the error to name the key %q, got %q", tc.wantKey, err)
+				}
+			}
+			if tc.wantErr != nil && requests != 0 {
+				t.Fatalf("expected invalid metadata not to be sent, got %d requests", requests)
+			}
+		})
+	}
+
+	config.ValidateMetadata = false
+	client = openai.NewClientWithConfig(config)
+	requests = 0
+	_, err := client.ModifyMessageWithMetadata(ctx, "thread_abc123", "msg_abc123", map[string]any{"attempt": 2})
+	checks.NoError(t, err, "expected metadata to be left to the server without validation")
+	if requests != 1 {
+		t.Fatalf("expected the request to be sent, got %d requests", requests)
+	}
+}
@@ -0,0 +1,112 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	completionsSuffix         = "/completions"
+	embeddingsSuffix          = "/embeddings"
+	moderationsSuffix         = "/moderations"
+	audioTranscriptionsSuffix = "/audio/transcriptions"
+	audioTranslationsSuffix   = "/audio/translations"
+	audioSpeechSuffix         = "/audio/speech"
+	imagesGenerationsSuffix   = "/images/generations"
+	imagesEditsSuffix         = "/images/edits"
+)
+
+var ErrModelEndpointMismatch = errors.New("model is not supported by this endpoint")
+
+// ModelEndpointMismatchError names a known model sent to an endpoint which cannot serve it.
+type ModelEndpointMismatchError struct {
+	Model    string
+	Endpoint string
+	// Suggestion is a model known to work with Endpoint, if any.
+	Suggestion string
+}
+
+func (e *ModelEndpointMismatchError) Error() string {
+	msg := fmt.Sprintf("%s: %q cannot be used with %s", ErrModelEndpointMismatch, e.Model, e.Endpoint)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", try %q instead", e.Suggestion)
+	}
+	return msg
+}
+
+func (e *ModelEndpointMismatchError) Unwrap() error {
+	return ErrModelEndpointMismatch
+}
+
+// suggestedModelForEndpoint is the model proposed when a request targets the wrong endpoint.
+var suggestedModelForEndpoint = map[string]string{
+	chatCompletionsSuffix:     GPT4oMini,
+	completionsSuffix:         GPT3Dot5TurboInstruct,
+	embeddingsSuffix:          string(SmallEmbedding3),
+	moderationsSuffix:         ModerationOmniLatest,
+	audioTranscriptionsSuffix: Whisper1,
+	audioTranslationsSuffix:   Whisper1,
+	audioSpeechSuffix:         string(TTSModel1),
+	imagesGenerationsSuffix:   CreateImageModelDallE3,
+	imagesEditsSuffix:         CreateImageModelGptImage1,
+}
+
+// modelEndpoints maps known models to the endpoints able to serve them.
+var modelEndpoints = buildModelEndpoints()
+
+func buildModelEndpoints() map[string][]string {
+	endpoints := map[string][]string{}
+	add := func(endpoint string, models ...string) {
+		for _, model := range models {
+			endpoints[model] = append(endpoints[model], endpoint)
+		}
+	}
+
+	// Chat models are exactly the ones disabled on the legacy completions endpoint, and vice versa.
+	for model := range disabledModelsForEndpoints[completionsSuffix] {
+		add(chatCompletionsSuffix, model)
+	}
+	for model := range disabledModelsForEndpoints[chatCompletionsSuffix] {
+		add(completionsSuffix, model)
+	}
+	add(completionsSuffix, GPT3Dot5TurboInstruct)
+	add(embeddingsSuffix, string(AdaEmbeddingV2), string(SmallEmbedding3), string(LargeEmbedding3))
+	for model := range validModerationModel {
+		add(moderationsSuffix, model)
+	}
+	add(audioTranscriptionsSuffix, Whisper1)
+	add(audioTranslationsSuffix, Whisper1)
+	add(audioSpeechSuffix, string(TTSModel1), string(TTSModel1HD), string(TTSModelGPT4oMini))
+	add(imagesGenerationsSuffix, CreateImageModelDallE2, CreateImageModelDallE3, CreateImageModelGptImage1)
+	add(imagesEditsSuffix, CreateImageModelDallE2, CreateImageModelGptImage1)
+	return endpoints
+}
+
+// ValidateModelForEndpoint checks that model can be used with endpoint, given as the API
+// path without the version prefix, e.g. "/chat/completions" or "/embeddings".
+// Models missing from the capability table are accepted, so new models keep working.
+// A known mismatch returns a *ModelEndpointMismatchError wrapping ErrModelEndpointMismatch.
+func ValidateModelForEndpoint(model, endpoint string) error {
+	supported, ok := modelEndpoints[model]
+	if !ok {
+		return nil
+	}
+	for _, e := range supported {
+		if e == endpoint {
+			return nil
+		}
+	}
+	return &ModelEndpointMismatchError{
+		Model:      model,
+		Endpoint:   endpoint,
+		Suggestion: suggestedModelForEndpoint[endpoint],
+	}
+}
+
+// validateModelEndpoint runs ValidateModelForEndpoint when enabled in the client config.
+func (c *Client) validateModelEndpoint(model, endpoint string) error {
+	if !c.config.ValidateModelEndpoints {
+		return nil
+	}
+	return ValidateModelForEndpoint(model, endpoint)
+}
@@ -0,0 +1,36 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestValidateModelForEndpoint(t *testing.T) {
+	if err := openai.ValidateModelForEndpoint(openai.GPT4o, "/chat/completions"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := openai.ValidateModelForEndpoint("my-fine-tuned-model", "/embeddings"); err != nil {
+		t.Fatalf("unknown models should be accepted, got %v", err)
+	}
+
+	err := openai.ValidateModelForEndpoint(string(openai.SmallEmbedding3), "/chat/completions")
+	var mismatch *openai.ModelEndpointMismatchError
+	if !errors.As(err, &mismatch) || !errors.Is(err, openai.ErrModelEndpointMismatch) {
+		t.Fatalf("expected a ModelEndpointMismatchError, got %v", err)
+	}
+	if mismatch.Suggestion != openai.GPT4oMini {
+		t.Fatalf("unexpected suggestion: %s", mismatch.Suggestion)
+	}
+
+	config := openai.DefaultConfig("token")
+	config.BaseURL = "http://localhost/v1"
+	config.ValidateModelEndpoints = true
+	client := openai.NewClientWithConfig(config)
+	_, err = client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{Model: openai.EmbeddingModel(openai.GPT4o)})
+	if !errors.Is(err, openai.ErrModelEndpointMismatch) {
+		t.Fatalf("expected CreateEmbeddings to reject a chat model, got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 )
 
 // Model struct represents an OpenAPI model.
@@ -19,6 +21,11 @@ type Model struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (m Model) CreatedTime() time.Time {
+	return time.Unix(m.CreatedAt, 0)
+}
+
 // Permission struct represents an OpenAPI permission.
 type Permission struct {
 	CreatedAt          int64       `json:"created"`
@@ -35,6 +42,11 @@ type Permission struct {
 	IsBlocking         bool        `json:"is_blocking"`
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (p Permission) CreatedTime() time.Time {
+	return time.Unix(p.CreatedAt, 0)
+}
+
 // FineTuneModelDeleteResponse represents the deletion status of a fine-tuned model.
 type FineTuneModelDeleteResponse struct {
 	ID      string `json:"id"`
@@ -51,18 +63,73 @@ type ModelsList struct {
 	httpHeader
 }
 
+// modelsCache holds the last ListModels result while ClientConfig.ModelsCacheTTL allows.
+type modelsCache struct {
+	mu      sync.Mutex
+	models  ModelsList
+	expires time.Time
+}
+
+func (m *modelsCache) get(now time.Time) (ModelsList, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.expires.IsZero() || now.After(m.expires) {
+		return ModelsList{}, false
+	}
+	return m.models.clone(), true
+}
+
+func (m *modelsCache) set(models ModelsList, expires time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.models = models.clone()
+	m.expires = expires
+}
+
+func (m *modelsCache) invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.models = ModelsList{}
+	m.expires = time.Time{}
+}
+
+func (l ModelsList) clone() ModelsList {
+	models := make([]Model, len(l.Models))
+	copy(models, l.Models)
+	l.Models = models
+	return l
+}
+
 // ListModels Lists the currently available models,
 // and provides basic information about each model such as the model id and parent.
+// When ClientConfig.ModelsCacheTTL is set, the list is served from an in-client cache
+// until the TTL expires.
 func (c *Client) ListModels(ctx context.Context) (models ModelsList, err error) {
+	ttl := c.config.ModelsCacheTTL
+	if ttl > 0 {
+		if cached, ok := c.modelsCache.get(c.now()); ok {
+			return cached, nil
+		}
+	}
+
 	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/models"))
 	if err != nil {
 		return
 	}
 
 	err = c.sendRequest(req, &models)
+	if err == nil && ttl > 0 {
+		c.modelsCache.set(models, c.now().Add(ttl))
+	}
 	return
 }
 
+// InvalidateModelsCache drops the cached models list, so the next ListModels call
+// fetches it from the API.
+func (c *Client) InvalidateModelsCache() {
+	c.modelsCache.invalidate()
+}
+
 // GetModel Retrieves a model instance, providing basic information about
 // the model such as the owner and permissioning.
 func (c *Client) GetModel(ctx context.Context, modelID string) (model Model, err error) {
@@ -86,5 +153,8 @@ func (c *Client) DeleteFineTuneModel(ctx context.Context, modelID string) (
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.InvalidateModelsCache()
+	}
 	return
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
 	"github.com/sashabaranov/go-openai/internal/test/checks"
 )
 
@@ -24,6 +25,53 @@ func TestListModels(t *testing.T) {
 	checks.NoError(t, err, "ListModels error")
 }
 
+func TestListModelsCache(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var requests int
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		handleListModelsEndpoint(w, r)
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.ModelsCacheTTL = time.Minute
+	client := openai.NewClientWithConfig(config)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, err := client.ListModels(ctx)
+		checks.NoError(t, err, "ListModels error")
+	}
+	if requests != 1 {
+		t.Fatalf("expected a single request within the TTL, got %d", requests)
+	}
+
+	client.InvalidateModelsCache()
+	_, err := client.ListModels(ctx)
+	checks.NoError(t, err, "ListModels error")
+	if requests != 2 {
+		t.Fatalf("expected the invalidated cache to be refreshed, got %d requests", requests)
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	config.Now = func() time.Time { return now }
+	client = openai.NewClientWithConfig(config)
+	requests = 0
+	for _, elapsed := range []time.Duration{0, 30 * time.Second, time.Minute, time.Minute + time.Second} {
+		now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Add(elapsed)
+		_, err = client.ListModels(ctx)
+		checks.NoError(t, err, "ListModels error")
+	}
+	if requests != 2 {
+		t.Fatalf("expected the cache to expire by the configured clock, got %d requests", requests)
+	}
+}
+
 func TestAzureListModels(t *testing.T) {
 	client, server, teardown := setupAzureTestServer()
 	defer teardown()
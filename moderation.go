@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 )
@@ -22,7 +23,10 @@ const (
 	ModerationText001 = "text-moderation-001"
 )
 
-var ErrModerationInvalidModel = errors.New("this model is not supported with moderation, please use text-moderation-stable or text-moderation-latest instead") //nolint:lll
+var (
+	ErrModerationInvalidModel       = errors.New("this model is not supported with moderation, please use text-moderation-stable or text-moderation-latest instead") //nolint:lll
+	ErrModerationInputFieldsMisused = errors.New("can't use both Input and MultiInput properties simultaneously")
+)
 
 var validModerationModel = map[string]struct{}{
 	ModerationOmniLatest:   {},
@@ -33,13 +37,70 @@ var validModerationModel = map[string]struct{}{
 
 // ModerationRequest represents a request structure for moderation API.
 type ModerationRequest struct {
-	Input        string            `json:"input,omitempty"`
+	Input string `json:"input,omitempty"`
+	// MultiInput sends a list of text and image inputs. Images are only supported by omni-moderation models.
+	MultiInput   []ModerationInput `json:"-"`
 	Model        string            `json:"model,omitempty"`
 	ExtraHeaders map[string]string `json:"extra_headers,omitempty"`
 	ExtraQuery   map[string]string `json:"extra_query,omitempty"`
 	ExtraBody    map[string]any    `json:"extra_body,omitempty"`
 }
 
+type ModerationInputType string
+
+const (
+	ModerationInputTypeText     ModerationInputType = "text"
+	ModerationInputTypeImageURL ModerationInputType = "image_url"
+)
+
+type ModerationInputImageURL struct {
+	URL string `json:"url"`
+}
+
+// ModerationInput is one element of a multi-modal moderation input.
+type ModerationInput struct {
+	Type     ModerationInputType      `json:"type"`
+	Text     string                   `json:"text,omitempty"`
+	ImageURL *ModerationInputImageURL `json:"image_url,omitempty"`
+}
+
+func (r ModerationRequest) MarshalJSON() ([]byte, error) {
+	if r.Input != "" && r.MultiInput != nil {
+		return nil, ErrModerationInputFieldsMisused
+	}
+	type Alias ModerationRequest
+	if len(r.MultiInput) > 0 {
+		return json.Marshal(struct {
+			Input []ModerationInput `json:"input"`
+			Alias
+		}{
+			Input: r.MultiInput,
+			Alias: Alias(r),
+		})
+	}
+	return json.Marshal(Alias(r))
+}
+
+func (r *ModerationRequest) UnmarshalJSON(data []byte) error {
+	type Alias ModerationRequest
+	aux := struct {
+		Input json.RawMessage `json:"input,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Input) == 0 {
+		return nil
+	}
+	if aux.Input[0] == '[' {
+		return json.Unmarshal(aux.Input, &r.MultiInput)
+	}
+	return json.Unmarshal(aux.Input, &r.Input)
+}
+
 // Result represents one of possible moderation results.
 type Result struct {
 	Categories     ResultCategories     `json:"categories"`
@@ -109,3 +170,43 @@ func (c *Client) Moderations(ctx context.Context, request ModerationRequest) (re
 	err = c.sendRequest(req, &response)
 	return
 }
+
+// ModerateMessage runs the text and image URL content of an assistant message through the
+// omni-moderation model and reports whether any of it was flagged. Image files referenced by
+// file ID cannot be moderated and are skipped. A message without moderatable content is
+// reported as not flagged without calling the API.
+func (c *Client) ModerateMessage(ctx context.Context, m Message) (response ModerationResponse, flagged bool, err error) {
+	var inputs []ModerationInput
+	for _, content := range m.Content {
+		switch {
+		case content.Text != nil && content.Text.Value != "":
+			inputs = append(inputs, ModerationInput{
+				Type: ModerationInputTypeText,
+				Text: content.Text.Value,
+			})
+		case content.ImageURL != nil && content.ImageURL.URL != "":
+			inputs = append(inputs, ModerationInput{
+				Type:     ModerationInputTypeImageURL,
+				ImageURL: &ModerationInputImageURL{URL: content.ImageURL.URL},
+			})
+		}
+	}
+	if len(inputs) == 0 {
+		return
+	}
+
+	response, err = c.Moderations(ctx, ModerationRequest{
+		Model:      ModerationOmniLatest,
+		MultiInput: inputs,
+	})
+	if err != nil {
+		return
+	}
+	for _, result := range response.Results {
+		if result.Flagged {
+			flagged = true
+			break
+		}
+	}
+	return
+}
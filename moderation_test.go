@@ -153,3 +153,47 @@ func getModerationBody(r *http.Request) (openai.ModerationRequest, error) {
 	}
 	return moderation, nil
 }
+
+func TestModerateMessage(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/moderations", func(w http.ResponseWriter, r *http.Request) {
+		moderationReq, err := getModerationBody(r)
+		checks.NoError(t, err, "getModerationBody error")
+		if moderationReq.Model != openai.ModerationOmniLatest {
+			t.Fatalf("expected omni moderation model, got %s", moderationReq.Model)
+		}
+		if len(moderationReq.MultiInput) != 2 ||
+			moderationReq.MultiInput[0].Text != "I want to kill them." ||
+			moderationReq.MultiInput[1].ImageURL == nil {
+			t.Fatalf("unexpected moderation input: %+v", moderationReq.MultiInput)
+		}
+
+		resBytes, _ := json.Marshal(openai.ModerationResponse{
+			Model:   moderationReq.Model,
+			Results: []openai.Result{{Flagged: false}, {Flagged: true}},
+		})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	_, flagged, err := client.ModerateMessage(context.Background(), openai.Message{
+		Role: openai.ChatMessageRoleAssistant,
+		Content: []openai.MessageContent{
+			{Type: "text", Text: &openai.MessageText{Value: "I want to kill them."}},
+			{Type: "image_url", ImageURL: &openai.ImageURL{URL: "https://example.com/image.png"}},
+			{Type: "image_file", ImageFile: &openai.ImageFile{FileID: "file_abc123"}},
+		},
+	})
+	checks.NoError(t, err, "ModerateMessage error")
+	if !flagged {
+		t.Fatalf("expected message to be flagged")
+	}
+}
+
+func TestModerationRequestMultiInputMisused(t *testing.T) {
+	_, err := json.Marshal(openai.ModerationRequest{
+		Input:      "text",
+		MultiInput: []openai.ModerationInput{{Type: openai.ModerationInputTypeText, Text: "text"}},
+	})
+	checks.ErrorIs(t, err, openai.ErrModerationInputFieldsMisused, "expected misused fields error")
+}
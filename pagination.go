@@ -0,0 +1,92 @@
+package openai
+
+// PaginationOption sets a cursor pagination parameter of a list request.
+type PaginationOption func(*pagination)
+
+type pagination struct {
+	limit  *int
+	order  *string
+	after  *string
+	before *string
+}
+
+func newPagination(opts []PaginationOption) pagination {
+	var p pagination
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// WithPageLimit sets the number of objects returned per page.
+func WithPageLimit(limit int) PaginationOption {
+	return func(p *pagination) {
+		p.limit = &limit
+	}
+}
+
+// WithPageOrder sets the sort order by created_at, either "asc" or "desc".
+func WithPageOrder(order string) PaginationOption {
+	return func(p *pagination) {
+		p.order = &order
+	}
+}
+
+// WithPageAfter lists the objects following the object with the given ID.
+func WithPageAfter(after string) PaginationOption {
+	return func(p *pagination) {
+		p.after = &after
+	}
+}
+
+// WithPageBefore lists the objects preceding the object with the given ID.
+func WithPageBefore(before string) PaginationOption {
+	return func(p *pagination) {
+		p.before = &before
+	}
+}
+
+// Page is a page of a cursor paginated list endpoint, such as the messages or message
+// files of a thread. Any list response can be decoded into the Page of its item type,
+// and MessagesList, MessageFilesList and MessageRefsList convert to one with Page.
+type Page[T any] struct {
+	Items []T `json:"data"`
+
+	Object  string  `json:"object"`
+	FirstID *string `json:"first_id"`
+	LastID  *string `json:"last_id"`
+	HasMore bool    `json:"has_more"`
+}
+
+// NextAfter returns the after cursor of the page following p, its LastID. It returns
+// false when p is the last page.
+func (p Page[T]) NextAfter() (after string, ok bool) {
+	if !p.HasMore || p.LastID == nil {
+		return
+	}
+	return *p.LastID, true
+}
+
+// PrevBefore returns the before cursor of the page preceding p, its FirstID. It returns
+// false when p is empty.
+func (p Page[T]) PrevBefore() (before string, ok bool) {
+	if p.FirstID == nil {
+		return
+	}
+	return *p.FirstID, true
+}
+
+// Page returns the list as a Page of messages sharing its slice.
+func (l MessagesList) Page() Page[Message] {
+	return Page[Message]{l.Messages, l.Object, l.FirstID, l.LastID, l.HasMore}
+}
+
+// Page returns the list as a Page of message files sharing its slice.
+func (l MessageFilesList) Page() Page[MessageFile] {
+	return Page[MessageFile]{l.MessageFiles, l.Object, l.FirstID, l.LastID, l.HasMore}
+}
+
+// Page returns the list as a Page of message refs sharing its slice.
+func (l MessageRefsList) Page() Page[MessageRef] {
+	return Page[MessageRef]{Items: l.Messages, FirstID: l.FirstID, LastID: l.LastID, HasMore: l.HasMore}
+}
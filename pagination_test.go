@@ -0,0 +1,54 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestPage(t *testing.T) {
+	body := []byte(`{"object":"list","data":[
+		{"id":"msg_1","object":"thread.message","role":"user"},
+		{"id":"msg_2","object":"thread.message","role":"assistant"}
+	],"first_id":"msg_1","last_id":"msg_2","has_more":true}`)
+
+	var page openai.Page[openai.Message]
+	checks.NoError(t, json.Unmarshal(body, &page), "Unmarshal error")
+	if len(page.Items) != 2 || page.Items[1].Role != "assistant" || page.Object != "list" {
+		t.Fatalf("unexpected page %+v", page)
+	}
+	after, ok := page.NextAfter()
+	if !ok || after != "msg_2" {
+		t.Fatalf("unexpected next cursor %q, %v", after, ok)
+	}
+	before, ok := page.PrevBefore()
+	if !ok || before != "msg_1" {
+		t.Fatalf("unexpected previous cursor %q, %v", before, ok)
+	}
+
+	var list openai.MessagesList
+	checks.NoError(t, json.Unmarshal(body, &list), "Unmarshal error")
+	if converted := list.Page(); len(converted.Items) != 2 || *converted.LastID != "msg_2" || !converted.HasMore {
+		t.Fatalf("unexpected converted page %+v", converted)
+	}
+
+	var files openai.Page[openai.MessageFile]
+	checks.NoError(t, json.Unmarshal([]byte(`{"object":"list","data":[
+		{"id":"file_1","object":"thread.message.file","message_id":"msg_1"}
+	],"first_id":"file_1","last_id":"file_1","has_more":false}`), &files), "Unmarshal error")
+	if len(files.Items) != 1 || files.Items[0].MessageID != "msg_1" {
+		t.Fatalf("unexpected files page %+v", files)
+	}
+	if _, ok = files.NextAfter(); ok {
+		t.Fatal("expected the last page to have no next cursor")
+	}
+
+	var empty openai.Page[openai.Message]
+	checks.NoError(t, json.Unmarshal([]byte(`{"object":"list","data":[],"first_id":null,"last_id":null,"has_more":false}`),
+		&empty), "Unmarshal error")
+	if _, ok = empty.PrevBefore(); ok {
+		t.Fatal("expected an empty page to have no previous cursor")
+	}
+}
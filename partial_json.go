@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrPartialJSONInvalid = errors.New("partial JSON has no parsable prefix")
+
+// PartialJSON is the best-effort parse of a JSON fragment, such as the arguments of a
+// tool call which are still being streamed.
+type PartialJSON struct {
+	// JSON is the fragment completed into valid JSON.
+	JSON string
+	// Value is JSON decoded into maps, slices and scalars as by encoding/json.
+	Value any
+	// Final is true when the fragment is valid JSON as is. Otherwise the result is
+	// provisional: open strings, arrays and objects were closed and incomplete trailing
+	// tokens, such as a key without a value, were dropped.
+	Final bool
+}
+
+// Unmarshal decodes the completed JSON into v.
+func (p PartialJSON) Unmarshal(v any) error {
+	return json.Unmarshal([]byte(p.JSON), v)
+}
+
+// ParsePartialJSON parses a possibly incomplete JSON fragment. It returns
+// ErrPartialJSONInvalid when no prefix of the fragment can be completed into valid JSON.
+func ParsePartialJSON(fragment string) (result PartialJSON, err error) {
+	if json.Valid([]byte(fragment)) {
+		result = PartialJSON{JSON: fragment, Final: true}
+		err = json.Unmarshal([]byte(fragment), &result.Value)
+		return
+	}
+
+	fragment = strings.TrimRight(fragment, " \t\r\n")
+	for end := len(fragment); end > 0; end-- {
+		completed := completePartialJSON(fragment[:end])
+		if !json.Valid([]byte(completed)) {
+			continue
+		}
+		result = PartialJSON{JSON: completed}
+		err = json.Unmarshal([]byte(completed), &result.Value)
+		return
+	}
+	err = ErrPartialJSONInvalid
+	return
+}
+
+// PartialArguments parses the arguments of a function call which may still be streaming.
+func (f FunctionCall) PartialArguments() (PartialJSON, error) {
+	return ParsePartialJSON(f.Arguments)
+}
+
+// completePartialJSON closes the string, arrays and objects left open by prefix.
+func completePartialJSON(prefix string) string {
+	var (
+		closers  []byte
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(prefix); i++ {
+		ch := prefix[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			closers = append(closers, '}')
+		case '[':
+			closers = append(closers, ']')
+		case '}', ']':
+			if len(closers) > 0 {
+				closers = closers[:len(closers)-1]
+			}
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(prefix)
+	if inString {
+		builder.WriteByte('"')
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		builder.WriteByte(closers[i])
+	}
+	return builder.String()
+}
@@ -0,0 +1,57 @@
+package openai_test
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestParsePartialJSON(t *testing.T) {
+	testCases := []struct {
+		fragment string
+		expected string
+		final    bool
+	}{
+		{`{"city": "Paris", "days": [1, 2]}`, `{"city": "Paris", "days": [1, 2]}`, true},
+		{`{"city": "Par`, `{"city": "Par"}`, false},
+		{`{"city": "Paris", "da`, `{"city": "Paris"}`, false},
+		{`{"city": "Paris", "days":`, `{"city": "Paris"}`, false},
+		{`{"city": "Paris", "days": [1, `, `{"city": "Paris", "days": [1]}`, false},
+		{`{"ok": tr`, `{}`, false},
+		{`{"quote": "say \`, `{"quote": "say "}`, false},
+		{`{"a": {"b": [{"c": 1`, `{"a": {"b": [{"c": 1}]}}`, false},
+	}
+	for _, tc := range testCases {
+		result, err := openai.ParsePartialJSON(tc.fragment)
+		checks.NoError(t, err, "ParsePartialJSON error")
+		if result.JSON != tc.expected || result.Final != tc.final {
+			t.Errorf("%s: expected %s (final %v), got %s (final %v)",
+				tc.fragment, tc.expected, tc.final, result.JSON, result.Final)
+		}
+	}
+
+	_, err := openai.ParsePartialJSON("not json")
+	checks.ErrorIs(t, err, openai.ErrPartialJSONInvalid, "expected invalid fragment error")
+}
+
+func TestFunctionCallPartialArguments(t *testing.T) {
+	call := openai.FunctionCall{Name: "book", Arguments: `{"city": "Paris", "nights": 3, "guests": [{"name": "Al`}
+	result, err := call.PartialArguments()
+	checks.NoError(t, err, "PartialArguments error")
+
+	var args struct {
+		City   string `json:"city"`
+		Nights int    `json:"nights"`
+		Guests []struct {
+			Name string `json:"name"`
+		} `json:"guests"`
+	}
+	checks.NoError(t, result.Unmarshal(&args), "Unmarshal error")
+	if result.Final || args.City != "Paris" || args.Nights != 3 || len(args.Guests) != 1 || args.Guests[0].Name != "Al" {
+		t.Fatalf("unexpected provisional arguments: %+v (final %v)", args, result.Final)
+	}
+	if value, ok := result.Value.(map[string]any); !ok || value["city"] != "Paris" {
+		t.Fatalf("unexpected decoded value: %v", result.Value)
+	}
+}
@@ -23,8 +23,17 @@ func (r ResetTime) String() string {
 }
 
 func (r ResetTime) Time() time.Time {
-	d, _ := time.ParseDuration(string(r))
-	return time.Now().Add(d)
+	return time.Now().Add(r.Duration())
+}
+
+// Duration returns the time until the limit resets, such as 6m0s for "6m0s".
+// A missing or malformed value yields zero.
+func (r ResetTime) Duration() time.Duration {
+	d, err := time.ParseDuration(string(r))
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
 }
 
 func newRateLimitHeaders(h http.Header) RateLimitHeaders {
@@ -0,0 +1,151 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RequestOption customizes a single request sent with PostRaw or one of the message
+// methods, such as CreateMessage, without changing the client configuration.
+type RequestOption func(*rawRequestOptions)
+
+type rawRequestOptions struct {
+	header  map[string]string
+	query   url.Values
+	onBody  func(body []byte)
+	apiType APIType
+}
+
+// WithRequestHeader sets a header on the request. Authentication and organization
+// headers set by the client take precedence.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(o *rawRequestOptions) {
+		if o.header == nil {
+			o.header = make(map[string]string)
+		}
+		o.header[key] = value
+	}
+}
+
+// WithRequestQuery adds a query parameter to the request.
+func WithRequestQuery(key, value string) RequestOption {
+	return func(o *rawRequestOptions) {
+		if o.query == nil {
+			o.query = url.Values{}
+		}
+		o.query.Add(key, value)
+	}
+}
+
+// WithIfNoneMatch makes the request conditional on etag, typically the ETag of a
+// previous response: when the resource is unchanged the call fails with
+// ErrNotModified. An empty etag, from a server which sends no ETags, is ignored.
+func WithIfNoneMatch(etag string) RequestOption {
+	return func(o *rawRequestOptions) {
+		if etag != "" {
+			WithRequestHeader("If-None-Match", etag)(o)
+		}
+	}
+}
+
+// WithCacheBust asks caches between the client and the API, such as gateway caches, to
+// revalidate the response instead of serving a stored one, for instance to read a
+// message back right after ModifyMessage. It sets Cache-Control and Pragma to no-cache.
+// Caches keyed on the URL alone can be bypassed by also adding a unique query parameter
+// with WithRequestQuery.
+func WithCacheBust() RequestOption {
+	return func(o *rawRequestOptions) {
+		WithRequestHeader("Cache-Control", "no-cache")(o)
+		WithRequestHeader("Pragma", "no-cache")(o)
+	}
+}
+
+// WithRawBody calls fn with the encoded request body right before the request is sent,
+// to inspect the exact payload. Requests without a body are passed a nil body. Retries
+// resend the same body and do not call fn again.
+func WithRawBody(fn func(body []byte)) RequestOption {
+	return func(o *rawRequestOptions) {
+		o.onBody = fn
+	}
+}
+
+// WithAPIType sends the request the way apiType expects instead of the configured
+// APIType, to route a single call to another backend behind the same BaseURL: its URL
+// is built and its credentials are sent for apiType. Azure API types need the client to
+// have an APIVersion, otherwise the call fails with ErrAzureAPIVersionRequired before a
+// request is sent; without an AzureModelMapperFunc, models are used as deployment names.
+func WithAPIType(apiType APIType) RequestOption {
+	return func(o *rawRequestOptions) {
+		o.apiType = apiType
+	}
+}
+
+// applyRequestOptions adds the query parameters of opts to suffix and returns the
+// URL option and the request option applying the rest of them.
+func applyRequestOptions(suffix string, opts []RequestOption) (string, fullURLOption, requestOption) {
+	var options rawRequestOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if len(options.query) > 0 {
+		separator := "?"
+		if strings.Contains(suffix, "?") {
+			separator = "&"
+		}
+		suffix += separator + options.query.Encode()
+	}
+	urlOption := func(args *fullURLOptions) {
+		args.apiType = options.apiType
+	}
+	return suffix, urlOption, func(args *requestOptions) {
+		withExtraHeaders(options.header)(args)
+		args.onBody = options.onBody
+		args.apiType = options.apiType
+	}
+}
+
+// rawResponse decodes a response into any out value while recording the headers.
+type rawResponse struct {
+	out any
+
+	httpHeader
+}
+
+func (r *rawResponse) UnmarshalJSON(data []byte) error {
+	if raw, ok := r.out.(*[]byte); ok {
+		*raw = append((*raw)[:0], data...)
+		return nil
+	}
+	return json.Unmarshal(data, r.out)
+}
+
+// PostRaw sends body as JSON to path, relative to BaseURL, and decodes the JSON
+// response into out, for endpoints or parameters the package does not model yet.
+// It goes through the same authentication, headers, retries and error decoding as
+// the typed methods. A []byte or json.RawMessage body is sent as is, and a *[]byte
+// out receives the raw response body. A nil body sends no body and a nil out
+// discards the response.
+//
+// PostRaw is an experimental escape hatch: its behavior may change and requests
+// sent through it are not validated.
+func (c *Client) PostRaw(ctx context.Context, path string, body any, out any, opts ...RequestOption) error {
+	path, urlOption, extra := applyRequestOptions(path, opts)
+	if raw, ok := body.([]byte); ok {
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(path, urlOption), withBody(body), extra)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return c.sendRequest(req, nil)
+	}
+	return c.sendRequest(req, &rawResponse{out: out})
+}
@@ -0,0 +1,71 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestPostRaw(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/experimental/things", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		checks.NoError(t, err, "ReadAll error")
+		if r.Header.Get("OpenAI-Beta") != "things=v1" || r.URL.Query().Get("mode") != "fast" {
+			t.Errorf("expected custom header and query, got %v %v", r.Header, r.URL.Query())
+		}
+		if r.Header.Get("Authorization") == "" {
+			t.Errorf("expected the client authentication header")
+		}
+		fmt.Fprintf(w, `{"echo":%s,"id":12}`, body)
+	})
+
+	ctx := context.Background()
+	var out struct {
+		Echo map[string]any `json:"echo"`
+		ID   int            `json:"id"`
+	}
+	err := client.PostRaw(ctx, "/experimental/things", map[string]any{"new_param": true}, &out,
+		openai.WithRequestHeader("OpenAI-Beta", "things=v1"), openai.WithRequestQuery("mode", "fast"))
+	checks.NoError(t, err, "PostRaw error")
+	if out.Echo["new_param"] != true || out.ID != 12 {
+		t.Fatalf("unexpected response: %+v", out)
+	}
+
+	var raw []byte
+	err = client.PostRaw(ctx, "/experimental/things", []byte(`{"pre":"marshaled"}`), &raw,
+		openai.WithRequestHeader("OpenAI-Beta", "things=v1"), openai.WithRequestQuery("mode", "fast"))
+	checks.NoError(t, err, "PostRaw error")
+	if string(raw) != `{"echo":{"pre":"marshaled"},"id":12}` {
+		t.Fatalf("unexpected raw response: %s", raw)
+	}
+
+	err = client.PostRaw(ctx, "/experimental/things", json.RawMessage(`{}`), nil,
+		openai.WithRequestHeader("OpenAI-Beta", "things=v1"), openai.WithRequestQuery("mode", "fast"))
+	checks.NoError(t, err, "PostRaw error")
+}
+
+func TestPostRawError(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/experimental/things", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"error":{"message":"unknown parameter","type":"invalid_request_error"}}`)
+	})
+
+	err := client.PostRaw(context.Background(), "/experimental/things", map[string]any{}, nil)
+	apiErr := &openai.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+)
+
+const realtimeTranscriptionSessionsSuffix = "/realtime/transcription_sessions"
+
+type RealtimeAudioFormat string
+
+const (
+	RealtimeAudioFormatPCM16    RealtimeAudioFormat = "pcm16"
+	RealtimeAudioFormatG711ULaw RealtimeAudioFormat = "g711_ulaw"
+	RealtimeAudioFormatG711ALaw RealtimeAudioFormat = "g711_alaw"
+)
+
+// RealtimeInputAudioTranscription configures the model transcribing the input audio.
+type RealtimeInputAudioTranscription struct {
+	Model    string `json:"model,omitempty"`
+	Language string `json:"language,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+}
+
+type RealtimeTurnDetectionType string
+
+const (
+	RealtimeTurnDetectionServerVAD   RealtimeTurnDetectionType = "server_vad"
+	RealtimeTurnDetectionSemanticVAD RealtimeTurnDetectionType = "semantic_vad"
+)
+
+// RealtimeTurnDetection configures voice activity detection.
+type RealtimeTurnDetection struct {
+	Type              RealtimeTurnDetectionType `json:"type"`
+	Threshold         float32                   `json:"threshold,omitempty"`
+	PrefixPaddingMs   int                       `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs int                       `json:"silence_duration_ms,omitempty"`
+}
+
+// RealtimeTranscriptionSessionRequest represents a request structure for creating
+// a realtime transcription session.
+type RealtimeTranscriptionSessionRequest struct {
+	InputAudioFormat        RealtimeAudioFormat              `json:"input_audio_format,omitempty"`
+	InputAudioTranscription *RealtimeInputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *RealtimeTurnDetection           `json:"turn_detection,omitempty"`
+	Include                 []string                         `json:"include,omitempty"`
+}
+
+// RealtimeClientSecret is an ephemeral key usable by browser clients until ExpiresAt.
+type RealtimeClientSecret struct {
+	Value     string `json:"value"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// RealtimeTranscriptionSession is a minted transcription session, echoing its configuration.
+type RealtimeTranscriptionSession struct {
+	ID                      string                           `json:"id"`
+	Object                  string                           `json:"object"`
+	InputAudioFormat        RealtimeAudioFormat              `json:"input_audio_format"`
+	InputAudioTranscription *RealtimeInputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *RealtimeTurnDetection           `json:"turn_detection,omitempty"`
+	ClientSecret            RealtimeClientSecret             `json:"client_secret"`
+
+	httpHeader
+}
+
+// CreateRealtimeTranscriptionSession — API call to mint an ephemeral client secret for
+// a realtime transcription session. The realtime transport itself is not implemented.
+func (c *Client) CreateRealtimeTranscriptionSession(
+	ctx context.Context,
+	request RealtimeTranscriptionSessionRequest,
+) (session RealtimeTranscriptionSession, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(realtimeTranscriptionSessionsSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &session)
+	return
+}
@@ -0,0 +1,43 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateRealtimeTranscriptionSession(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/realtime/transcription_sessions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.RealtimeTranscriptionSessionRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		checks.NoError(t, err, "Decode error")
+		if request.InputAudioTranscription.Model != "gpt-4o-transcribe" {
+			t.Fatalf("unexpected transcription model: %+v", request.InputAudioTranscription)
+		}
+		fmt.Fprintln(w, `{"id":"sess_1","object":"realtime.transcription_session","input_audio_format":"pcm16",
+			"input_audio_transcription":{"model":"gpt-4o-transcribe"},"turn_detection":{"type":"server_vad"},
+			"client_secret":{"value":"ek_abc123","expires_at":1700000000}}`)
+	})
+
+	session, err := client.CreateRealtimeTranscriptionSession(context.Background(),
+		openai.RealtimeTranscriptionSessionRequest{
+			InputAudioFormat:        openai.RealtimeAudioFormatPCM16,
+			InputAudioTranscription: &openai.RealtimeInputAudioTranscription{Model: "gpt-4o-transcribe"},
+			TurnDetection:           &openai.RealtimeTurnDetection{Type: openai.RealtimeTurnDetectionServerVAD},
+		})
+	checks.NoError(t, err, "CreateRealtimeTranscriptionSession error")
+	if session.ClientSecret.Value != "ek_abc123" || session.ClientSecret.ExpiresAt != 1700000000 {
+		t.Fatalf("unexpected client secret: %+v", session.ClientSecret)
+	}
+	if session.TurnDetection.Type != openai.RealtimeTurnDetectionServerVAD {
+		t.Fatalf("unexpected turn detection: %+v", session.TurnDetection)
+	}
+}
@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// messageRequestBody encodes a MessageRequest straight into the request buffer. Each
+// field is encoded by a json.Encoder writing into the buffer, so a large string content
+// is held once in the request and once in its body, instead of being marshaled with the
+// whole request and copied again. The fields are written in the order and with the
+// omission rules of MessageRequest.MarshalJSON, so the bytes are the same as
+// json.Marshal's.
+type messageRequestBody struct {
+	request MessageRequest
+}
+
+// MarshalJSON is used instead of WriteBody when extra body fields are merged.
+func (b messageRequestBody) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.request)
+}
+
+func (b messageRequestBody) WriteBody(buf *bytes.Buffer) error {
+	r := b.request
+	content, err := messageRequestContent(r.Content, r.ContentParts)
+	if err != nil {
+		return err
+	}
+	if content == "" && len(r.Attachments) > 0 {
+		content = nil
+	}
+
+	object := &jsonObjectWriter{buf: buf}
+	object.field("role", r.Role)
+	if len(r.FileIds) > 0 {
+		object.field("file_ids", r.FileIds)
+	}
+	if len(r.Metadata) > 0 {
+		object.field("metadata", r.Metadata)
+	}
+	if len(r.Attachments) > 0 {
+		object.field("attachments", r.Attachments)
+	}
+	if content != nil {
+		object.field("content", content)
+	}
+	return object.close()
+}
+
+// jsonObjectWriter writes a JSON object into buf field by field. The first error stops
+// the writing and is returned by close.
+type jsonObjectWriter struct {
+	buf    *bytes.Buffer
+	fields int
+	err    error
+}
+
+func (o *jsonObjectWriter) field(name string, value any) {
+	if o.err != nil {
+		return
+	}
+	if o.fields == 0 {
+		o.buf.WriteByte('{')
+	} else {
+		o.buf.WriteByte(',')
+	}
+	o.fields++
+	o.buf.WriteString(`"` + name + `":`)
+	if o.err = json.NewEncoder(o.buf).Encode(value); o.err != nil {
+		return
+	}
+	// Encode ends every value with a newline, which is no part of the object.
+	if data := o.buf.Bytes(); len(data) > 0 && data[len(data)-1] == '\n' {
+		o.buf.Truncate(len(data) - 1)
+	}
+}
+
+func (o *jsonObjectWriter) close() error {
+	if o.err != nil {
+		return o.err
+	}
+	if o.fields == 0 {
+		o.buf.WriteByte('{')
+	}
+	o.buf.WriteByte('}')
+	return nil
+}
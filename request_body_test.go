@@ -0,0 +1,93 @@
+package openai //nolint:testpackage // testing private type
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// messageRequestBodyCases are requests whose WriteBody bytes must equal json.Marshal's.
+var messageRequestBodyCases = []MessageRequest{
+	{Role: "user", Content: "Hello"},
+	{Role: "user", Content: "<b>\"quoted\"</b> & \\ back\nslash\r\t\x01\x1f café \u2028\u2029 \U0001F600"},
+	{Role: "user", Content: "\b\f\x00\x7f control characters"},
+	{Role: "user", Content: "invalid \xff\xfe utf-8 \xe2\x82"},
+	{Role: "user", Content: ""},
+	{Role: "assistant", Content: "Hi", Metadata: map[string]any{"k": "v"},
+		Attachments: []ThreadAttachment{{FileID: "file_abc123"}}},
+	{Role: "user", Attachments: []ThreadAttachment{NewThreadAttachment("file_abc123", AssistantToolTypeFileSearch)}},
+	{Role: "user", Content: "legacy", FileIds: []string{"file_abc123"}},
+	{Role: "user", ContentParts: []MessageContent{NewTextMessageContent("single <part>\b")}},
+	{Role: "user", ContentParts: []MessageContent{
+		NewTextMessageContent("two"), NewImageFileMessageContent("file_abc123"),
+	}},
+	{Role: "user", ContentParts: []MessageContent{NewImageURLMessageContent("https://example.com/a.png", "")},
+		Attachments: []ThreadAttachment{{FileID: "file_abc123"}}},
+}
+
+func checkMessageRequestBody(t *testing.T, request MessageRequest) {
+	t.Helper()
+	want, wantErr := json.Marshal(request)
+	var buf bytes.Buffer
+	err := messageRequestBody{request}.WriteBody(&buf)
+	if (err != nil) != (wantErr != nil) {
+		t.Fatalf("WriteBody error %v, json.Marshal error %v", err, wantErr)
+	}
+	if err == nil && buf.String() != string(want) {
+		t.Fatalf("unexpected body\n got %s\nwant %s", buf.String(), want)
+	}
+}
+
+func TestMessageRequestBody(t *testing.T) {
+	for _, request := range messageRequestBodyCases {
+		checkMessageRequestBody(t, request)
+	}
+
+	var buf bytes.Buffer
+	err := messageRequestBody{MessageRequest{Content: "a", ContentParts: []MessageContent{{}}}}.WriteBody(&buf)
+	checks.ErrorIs(t, err, ErrContentFieldsMisused, "expected misused content fields to fail")
+}
+
+func FuzzMessageRequestBody(f *testing.F) {
+	for _, request := range messageRequestBodyCases {
+		f.Add(request.Content, len(request.Attachments) > 0, len(request.ContentParts) > 0)
+	}
+	f.Fuzz(func(t *testing.T, content string, attachment, asPart bool) {
+		request := MessageRequest{Role: "user", Content: content}
+		if asPart {
+			request.Content, request.ContentParts = "", []MessageContent{NewTextMessageContent(content)}
+		}
+		if attachment {
+			request.Attachments = []ThreadAttachment{{FileID: "file_abc123"}}
+		}
+		checkMessageRequestBody(t, request)
+	})
+}
+
+func benchmarkRequestBody(b *testing.B, write func(MessageRequest) error) {
+	request := MessageRequest{Role: "user", Content: strings.Repeat("Some <large> content.\n", 1<<16)}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(request.Content)))
+	for i := 0; i < b.N; i++ {
+		if err := write(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageRequestMarshal(b *testing.B) {
+	benchmarkRequestBody(b, func(request MessageRequest) error {
+		data, err := json.Marshal(request)
+		bytes.NewBuffer(data)
+		return err
+	})
+}
+
+func BenchmarkMessageRequestWriteBody(b *testing.B) {
+	benchmarkRequestBody(b, func(request MessageRequest) error {
+		return messageRequestBody{request}.WriteBody(new(bytes.Buffer))
+	})
+}
@@ -0,0 +1,83 @@
+package openai
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const redactedHeaderValue = "[REDACTED]"
+
+// credentialHeaders are replaced by redactedHeaderValue in a RequestLog.
+var credentialHeaders = []string{"Authorization", AzureAPIKeyHeader}
+
+// RequestLog describes one request sent to the API and its response.
+type RequestLog struct {
+	Method string
+	URL    string
+	// Header is a copy of the request headers with the credentials redacted.
+	Header http.Header
+	// RequestBody is nil for requests whose body cannot be replayed.
+	RequestBody []byte
+	StatusCode  int
+	// ResponseBody is nil for streamed responses and successful downloads, such as
+	// file contents, which are not buffered.
+	ResponseBody []byte
+}
+
+// RequestLogger receives a RequestLog for every request which got a response,
+// see ClientConfig.RequestLogger.
+type RequestLogger func(entry RequestLog)
+
+// logRequest passes req and res to the configured RequestLogger. The response body
+// is read and replaced with an in-memory copy unless stream is set.
+func (c *Client) logRequest(req *http.Request, res *http.Response, stream bool) error {
+	if c.config.RequestLogger == nil {
+		return nil
+	}
+
+	entry := RequestLog{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Header:      req.Header.Clone(),
+		RequestBody: requestBodyBytes(req),
+		StatusCode:  res.StatusCode,
+	}
+	for _, name := range credentialHeaders {
+		if entry.Header.Get(name) != "" {
+			entry.Header.Set(name, redactedHeaderValue)
+		}
+	}
+
+	if !stream {
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error, reading response body: %w", err)
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		entry.ResponseBody = body
+	}
+
+	c.config.RequestLogger(entry)
+	return nil
+}
+
+// requestBodyBytes returns a copy of the body of req, nil when it has none or it
+// cannot be replayed.
+func requestBodyBytes(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,90 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestRequestLogger(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	server.RegisterHandler("/v1/models/gpt-4o", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"gpt-4o","object":"model"}`)
+	})
+	server.RegisterHandler("/v1/models/missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"model not found","type":"invalid_request_error"}}`)
+	})
+
+	var entries []openai.RequestLog
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.RequestLogger = func(entry openai.RequestLog) {
+		entries = append(entries, entry)
+	}
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+
+	model, err := client.GetModel(ctx, "gpt-4o")
+	checks.NoError(t, err, "GetModel error")
+	if model.ID != "gpt-4o" {
+		t.Fatalf("expected the logged response to still be decoded, got %q", model.ID)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one log entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Method != http.MethodGet || !strings.HasSuffix(entry.URL, "/v1/models/gpt-4o") {
+		t.Errorf("unexpected request in log: %s %s", entry.Method, entry.URL)
+	}
+	if got := entry.Header.Get("Authorization"); got != "[REDACTED]" {
+		t.Errorf("expected the Authorization header to be redacted, got %q", got)
+	}
+	if entry.StatusCode != http.StatusOK || !strings.Contains(string(entry.ResponseBody), `"gpt-4o"`) {
+		t.Errorf("unexpected response in log: %d %s", entry.StatusCode, entry.ResponseBody)
+	}
+
+	_, err = client.GetModel(ctx, "missing")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "model not found" {
+		t.Fatalf("expected the logged error response to still be decoded, got %v", err)
+	}
+	if !strings.Contains(string(entries[1].ResponseBody), "model not found") {
+		t.Errorf("expected the error body in log, got %s", entries[1].ResponseBody)
+	}
+
+	stream, err := client.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	response, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if response.Choices[0].Delta.Content != "hi" {
+		t.Fatalf("unexpected stream content %q", response.Choices[0].Delta.Content)
+	}
+	entry = entries[2]
+	if !strings.Contains(string(entry.RequestBody), `"hello"`) {
+		t.Errorf("expected the request body in log, got %s", entry.RequestBody)
+	}
+	if entry.ResponseBody != nil {
+		t.Errorf("expected a streamed response not to be buffered, got %s", entry.ResponseBody)
+	}
+}
@@ -0,0 +1,367 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const responsesSuffix = "/responses"
+
+var (
+	ErrResponsesPreviousIDRequired = errors.New("previous response id is required to continue a response")
+	ErrResponsesInputFieldsMisused = errors.New("can't use both Text and Items properties simultaneously")
+	ErrResponseInputItemInvalid    = errors.New("invalid response input item")
+)
+
+type ResponseInputItemType string
+
+const (
+	ResponseInputItemTypeMessage            ResponseInputItemType = "message"
+	ResponseInputItemTypeFunctionCall       ResponseInputItemType = "function_call"
+	ResponseInputItemTypeFunctionCallOutput ResponseInputItemType = "function_call_output"
+)
+
+// ResponseInputItem is one item of a conversation sent as Responses API input.
+type ResponseInputItem struct {
+	Type ResponseInputItemType `json:"type"`
+	// Role and Content are set on message items.
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+	// CallID links a function call to its output.
+	CallID string `json:"call_id,omitempty"`
+	// Name and Arguments are set on function call items.
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	// Output is set on function call output items.
+	Output string `json:"output,omitempty"`
+}
+
+// NewResponseInputMessage returns a message item with text content.
+func NewResponseInputMessage(role, content string) ResponseInputItem {
+	return ResponseInputItem{Type: ResponseInputItemTypeMessage, Role: role, Content: content}
+}
+
+// NewResponseFunctionCallOutput returns the item reporting the result of the function call callID.
+func NewResponseFunctionCallOutput(callID, output string) ResponseInputItem {
+	return ResponseInputItem{Type: ResponseInputItemTypeFunctionCallOutput, CallID: callID, Output: output}
+}
+
+func (i ResponseInputItem) validate() error {
+	switch i.Type {
+	case ResponseInputItemTypeMessage:
+		switch i.Role {
+		case ChatMessageRoleUser, ChatMessageRoleAssistant, ChatMessageRoleSystem, ChatMessageRoleDeveloper:
+		default:
+			return fmt.Errorf("%w: unsupported message role %q", ErrResponseInputItemInvalid, i.Role)
+		}
+		if i.Content == "" {
+			return fmt.Errorf("%w: message content is empty", ErrResponseInputItemInvalid)
+		}
+	case ResponseInputItemTypeFunctionCall:
+		if i.CallID == "" || i.Name == "" {
+			return fmt.Errorf("%w: function call requires call_id and name", ErrResponseInputItemInvalid)
+		}
+	case ResponseInputItemTypeFunctionCallOutput:
+		if i.CallID == "" {
+			return fmt.Errorf("%w: function call output requires call_id", ErrResponseInputItemInvalid)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %q", ErrResponseInputItemInvalid, i.Type)
+	}
+	return nil
+}
+
+// ResponsesInput is the input of a Responses API request: either plain Text or a
+// list of conversation Items. It is marshaled as a JSON string or array respectively.
+type ResponsesInput struct {
+	Text  string
+	Items []ResponseInputItem
+}
+
+// NewResponsesTextInput returns a plain text input.
+func NewResponsesTextInput(text string) ResponsesInput {
+	return ResponsesInput{Text: text}
+}
+
+// NewResponsesItemsInput returns an input made of conversation items.
+func NewResponsesItemsInput(items ...ResponseInputItem) ResponsesInput {
+	return ResponsesInput{Items: items}
+}
+
+func (in ResponsesInput) validate() error {
+	if in.Text != "" && len(in.Items) > 0 {
+		return ErrResponsesInputFieldsMisused
+	}
+	for i, item := range in.Items {
+		if err := item.validate(); err != nil {
+			return fmt.Errorf("input[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (in ResponsesInput) MarshalJSON() ([]byte, error) {
+	if len(in.Items) > 0 {
+		return json.Marshal(in.Items)
+	}
+	return json.Marshal(in.Text)
+}
+
+func (in *ResponsesInput) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		in.Text = ""
+		return json.Unmarshal(data, &in.Items)
+	}
+	in.Items = nil
+	return json.Unmarshal(data, &in.Text)
+}
+
+// ResponsesRequest represents a request structure for the Responses API.
+type ResponsesRequest struct {
+	Model        string         `json:"model,omitempty"`
+	Input        ResponsesInput `json:"input"`
+	Instructions string         `json:"instructions,omitempty"`
+	// PreviousResponseID continues the conversation of a stored response,
+	// so the earlier turns do not need to be sent again.
+	PreviousResponseID string              `json:"previous_response_id,omitempty"`
+	Store              *bool               `json:"store,omitempty"`
+	Metadata           map[string]any      `json:"metadata,omitempty"`
+	Reasoning          *ResponsesReasoning `json:"reasoning,omitempty"`
+	// Stream is set by CreateResponseStream.
+	Stream bool `json:"stream,omitempty"`
+}
+
+type ResponseOutputContent struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+const ResponseOutputItemTypeReasoning = "reasoning"
+
+type ResponseOutputItem struct {
+	ID      string                  `json:"id"`
+	Type    string                  `json:"type"`
+	Role    string                  `json:"role,omitempty"`
+	Status  string                  `json:"status,omitempty"`
+	Content []ResponseOutputContent `json:"content,omitempty"`
+	// Summary is set on reasoning items when a reasoning summary was requested.
+	Summary []ResponseOutputContent `json:"summary,omitempty"`
+	// CallID, Name and Arguments are set on function call items.
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// ResponseReasoningItem is a reasoning output item with its summary texts.
+type ResponseReasoningItem struct {
+	ID      string
+	Summary []string
+}
+
+type ResponsesOutputTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+type ResponsesUsage struct {
+	InputTokens         int                           `json:"input_tokens"`
+	OutputTokens        int                           `json:"output_tokens"`
+	TotalTokens         int                           `json:"total_tokens"`
+	OutputTokensDetails *ResponsesOutputTokensDetails `json:"output_tokens_details,omitempty"`
+}
+
+// ResponsesReasoning configures reasoning models.
+type ResponsesReasoning struct {
+	// Effort is one of "low", "medium" or "high".
+	Effort string `json:"effort,omitempty"`
+	// Summary requests a reasoning summary, e.g. "auto", "concise" or "detailed".
+	Summary string `json:"summary,omitempty"`
+}
+
+// ResponsesResponse represents a response structure for the Responses API.
+type ResponsesResponse struct {
+	ID                 string               `json:"id"`
+	Object             string               `json:"object"`
+	CreatedAt          int64                `json:"created_at"`
+	Model              string               `json:"model"`
+	Status             string               `json:"status"`
+	PreviousResponseID *string              `json:"previous_response_id,omitempty"`
+	Output             []ResponseOutputItem `json:"output"`
+	Usage              *ResponsesUsage      `json:"usage,omitempty"`
+	Metadata           map[string]any       `json:"metadata,omitempty"`
+
+	httpHeader
+}
+
+// CreatedTime returns CreatedAt as a time.Time.
+func (r ResponsesResponse) CreatedTime() time.Time {
+	return time.Unix(r.CreatedAt, 0)
+}
+
+// Reasoning returns the reasoning items of the output.
+func (r ResponsesResponse) Reasoning() []ResponseReasoningItem {
+	var items []ResponseReasoningItem
+	for _, output := range r.Output {
+		if output.Type != ResponseOutputItemTypeReasoning {
+			continue
+		}
+		item := ResponseReasoningItem{ID: output.ID}
+		for _, summary := range output.Summary {
+			item.Summary = append(item.Summary, summary.Text)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// ReasoningSummary joins the summary texts of all reasoning items with blank lines.
+// It is empty unless a summary was requested through ResponsesRequest.Reasoning.
+func (r ResponsesResponse) ReasoningSummary() string {
+	var parts []string
+	for _, item := range r.Reasoning() {
+		parts = append(parts, item.Summary...)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// CreateResponse — API call to create a model response.
+func (c *Client) CreateResponse(
+	ctx context.Context,
+	request ResponsesRequest,
+) (response ResponsesResponse, err error) {
+	if err = request.Input.validate(); err != nil {
+		return
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(responsesSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// RetrieveResponse — API call to retrieve a stored model response.
+func (c *Client) RetrieveResponse(
+	ctx context.Context,
+	responseID string,
+) (response ResponsesResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(responsesSuffix+"/"+responseID))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// ContinueResponse continues a stored conversation from previousResponseID with new input
+// answered by model. The new response is stored as well, so it can be continued in turn.
+// The API requires a model on every request, continuations included, and does not take
+// it from the previous response, so model is a parameter: reading it back with
+// RetrieveResponse would cost a request per turn. It may differ from the previous model.
+func (c *Client) ContinueResponse(
+	ctx context.Context,
+	model string,
+	previousResponseID string,
+	input ResponsesInput,
+) (response ResponsesResponse, err error) {
+	if previousResponseID == "" {
+		err = ErrResponsesPreviousIDRequired
+		return
+	}
+
+	store := true
+	return c.CreateResponse(ctx, ResponsesRequest{
+		Model:              model,
+		Input:              input,
+		PreviousResponseID: previousResponseID,
+		Store:              &store,
+	})
+}
+
+// ResponseStreamEventType is the type of an event emitted by a streamed response.
+type ResponseStreamEventType string
+
+const (
+	ResponseStreamEventTypeCreated                    ResponseStreamEventType = "response.created"
+	ResponseStreamEventTypeInProgress                 ResponseStreamEventType = "response.in_progress"
+	ResponseStreamEventTypeOutputItemAdded            ResponseStreamEventType = "response.output_item.added"
+	ResponseStreamEventTypeOutputItemDone             ResponseStreamEventType = "response.output_item.done"
+	ResponseStreamEventTypeOutputTextDelta            ResponseStreamEventType = "response.output_text.delta"
+	ResponseStreamEventTypeOutputTextDone             ResponseStreamEventType = "response.output_text.done"
+	ResponseStreamEventTypeFunctionCallArgumentsDelta ResponseStreamEventType = "response.function_call_arguments.delta"
+	ResponseStreamEventTypeFunctionCallArgumentsDone  ResponseStreamEventType = "response.function_call_arguments.done"
+	ResponseStreamEventTypeCompleted                  ResponseStreamEventType = "response.completed"
+	ResponseStreamEventTypeFailed                     ResponseStreamEventType = "response.failed"
+	ResponseStreamEventTypeIncomplete                 ResponseStreamEventType = "response.incomplete"
+)
+
+// ResponseStreamEvent is an event of a streamed response. Which fields are set depends
+// on Type; events of other types are delivered too, with their payload in Raw.
+type ResponseStreamEvent struct {
+	Type           ResponseStreamEventType `json:"type"`
+	SequenceNumber int                     `json:"sequence_number"`
+	OutputIndex    int                     `json:"output_index"`
+	ContentIndex   int                     `json:"content_index"`
+	ItemID         string                  `json:"item_id,omitempty"`
+	// Item is set on output_item events.
+	Item *ResponseOutputItem `json:"item,omitempty"`
+	// Delta is set on delta events, Text and Arguments on the matching done events.
+	Delta     string `json:"delta,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	// Response is set on the lifecycle events. On completed it carries the usage.
+	Response *ResponsesResponse `json:"response,omitempty"`
+	// Raw is the undecoded event.
+	Raw json.RawMessage `json:"-"`
+}
+
+func (e *ResponseStreamEvent) UnmarshalJSON(data []byte) error {
+	type alias ResponseStreamEvent
+	var event alias
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	*e = ResponseStreamEvent(event)
+	e.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// ResponseStream streams the events of a response until it is completed.
+type ResponseStream struct {
+	*streamReader[ResponseStreamEvent]
+}
+
+// CreateResponseStream — API call to create a model response with streaming support.
+// The stream ends with io.EOF after the connection is closed by the server, which
+// follows the response.completed, response.failed or response.incomplete event.
+func (c *Client) CreateResponseStream(
+	ctx context.Context,
+	request ResponsesRequest,
+	opts ...StreamOption,
+) (stream *ResponseStream, err error) {
+	if err = request.Input.validate(); err != nil {
+		return
+	}
+
+	request.Stream = true
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(responsesSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	resp, err := sendRequestStream[ResponseStreamEvent](c, req, opts...)
+	if err != nil {
+		return
+	}
+	stream = &ResponseStream{
+		streamReader: resp,
+	}
+	return
+}
@@ -0,0 +1,188 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestContinueResponse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/responses/resp_1", func(http.ResponseWriter, *http.Request) {
+		t.Fatal("expected the previous response not to be retrieved")
+	})
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ResponsesRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		checks.NoError(t, err, "Decode error")
+		if request.PreviousResponseID != "resp_1" || request.Store == nil || !*request.Store {
+			t.Fatalf("expected a stored continuation of resp_1, got %+v", request)
+		}
+		if request.Model != "gpt-4o" || request.Input.Text != "And then?" {
+			t.Fatalf("unexpected request: %+v", request)
+		}
+		fmt.Fprintln(w, `{"id":"resp_2","object":"response","model":"gpt-4o","status":"completed",
+			"previous_response_id":"resp_1","output":[{"id":"msg_1","type":"message","role":"assistant",
+			"content":[{"type":"output_text","text":"Then it ended."}]}]}`)
+	})
+
+	ctx := context.Background()
+	response, err := client.ContinueResponse(ctx, openai.GPT4o, "resp_1", openai.NewResponsesTextInput("And then?"))
+	checks.NoError(t, err, "ContinueResponse error")
+	if response.ID != "resp_2" || response.Output[0].Content[0].Text != "Then it ended." {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	_, err = client.ContinueResponse(ctx, openai.GPT4o, "", openai.NewResponsesTextInput("And then?"))
+	checks.ErrorIs(t, err, openai.ErrResponsesPreviousIDRequired, "expected missing previous id error")
+}
+
+func TestResponsesInputItems(t *testing.T) {
+	input := openai.NewResponsesItemsInput(
+		openai.NewResponseInputMessage(openai.ChatMessageRoleUser, "What is the weather?"),
+		openai.ResponseInputItem{
+			Type:      openai.ResponseInputItemTypeFunctionCall,
+			CallID:    "call_1",
+			Name:      "get_weather",
+			Arguments: `{"city":"Paris"}`,
+		},
+		openai.NewResponseFunctionCallOutput("call_1", `{"temperature":21}`),
+	)
+	data, err := json.Marshal(openai.ResponsesRequest{Model: openai.GPT4o, Input: input})
+	checks.NoError(t, err, "Marshal error")
+
+	var decoded openai.ResponsesRequest
+	err = json.Unmarshal(data, &decoded)
+	checks.NoError(t, err, "Unmarshal error")
+	if len(decoded.Input.Items) != 3 || decoded.Input.Items[2].Output != `{"temperature":21}` {
+		t.Fatalf("unexpected round trip of input items: %s", data)
+	}
+
+	data, _ = json.Marshal(openai.NewResponsesTextInput("Hello"))
+	if string(data) != `"Hello"` {
+		t.Fatalf("expected text input to marshal as a string, got %s", data)
+	}
+
+	client, _, teardown := setupOpenAITestServer()
+	defer teardown()
+	_, err = client.CreateResponse(context.Background(), openai.ResponsesRequest{
+		Model: openai.GPT4o,
+		Input: openai.NewResponsesItemsInput(openai.NewResponseFunctionCallOutput("", "{}")),
+	})
+	checks.ErrorIs(t, err, openai.ErrResponseInputItemInvalid, "expected an invalid item error")
+}
+
+func TestResponsesReasoningSummary(t *testing.T) {
+	var response openai.ResponsesResponse
+	err := json.Unmarshal([]byte(`{"id":"resp_1","output":[
+		{"id":"rs_1","type":"reasoning","summary":[{"type":"summary_text","text":"First."},
+			{"type":"summary_text","text":"Second."}]},
+		{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"output_text","text":"Done."}]}],
+		"usage":{"input_tokens":5,"output_tokens":40,"total_tokens":45,"output_tokens_details":{"reasoning_tokens":32}}}`),
+		&response)
+	checks.NoError(t, err, "Unmarshal error")
+
+	if summary := response.ReasoningSummary(); summary != "First.\n\nSecond." {
+		t.Fatalf("unexpected reasoning summary: %q", summary)
+	}
+	if items := response.Reasoning(); len(items) != 1 || items[0].ID != "rs_1" {
+		t.Fatalf("unexpected reasoning items: %+v", items)
+	}
+	if response.Usage.OutputTokensDetails.ReasoningTokens != 32 {
+		t.Fatalf("unexpected reasoning tokens: %+v", response.Usage.OutputTokensDetails)
+	}
+}
+
+func TestCreateResponseStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		err := json.NewDecoder(r.Body).Decode(&request)
+		checks.NoError(t, err, "Decode error")
+		if request["stream"] != true {
+			t.Errorf("expected stream to be requested, got %v", request["stream"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1","status":"in_progress"}}`,
+			`{"type":"response.output_item.added","sequence_number":1,"output_index":0,` +
+				`"item":{"id":"fc_1","type":"function_call","call_id":"call_1","name":"lookup"}}`,
+			`{"type":"response.function_call_arguments.delta","sequence_number":2,"item_id":"fc_1","delta":"{\"q\":"}`,
+			`{"type":"response.function_call_arguments.delta","sequence_number":3,"item_id":"fc_1","delta":"\"x\"}"}`,
+			`{"type":"response.output_text.delta","sequence_number":4,"item_id":"msg_1","delta":"Hi"}`,
+			`{"type":"response.custom.event","sequence_number":5,"detail":"kept"}`,
+			`{"type":"response.completed","sequence_number":6,"response":{"id":"resp_1","status":"completed",` +
+				`"usage":{"input_tokens":3,"output_tokens":5,"total_tokens":8}}}`,
+		}
+		for _, event := range events {
+			var typed struct {
+				Type string `json:"type"`
+			}
+			checks.NoError(t, json.Unmarshal([]byte(event), &typed), "Unmarshal error")
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, event)
+		}
+	})
+
+	stream, err := client.CreateResponseStream(context.Background(), openai.ResponsesRequest{
+		Model: openai.GPT4o,
+		Input: openai.NewResponsesTextInput("Hello"),
+	})
+	checks.NoError(t, err, "CreateResponseStream error")
+	defer stream.Close()
+
+	var (
+		types     []string
+		arguments strings.Builder
+		completed *openai.ResponsesResponse
+		custom    json.RawMessage
+		item      *openai.ResponseOutputItem
+	)
+	for {
+		event, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		types = append(types, string(event.Type))
+		switch event.Type {
+		case openai.ResponseStreamEventTypeOutputItemAdded:
+			item = event.Item
+		case openai.ResponseStreamEventTypeFunctionCallArgumentsDelta:
+			arguments.WriteString(event.Delta)
+		case openai.ResponseStreamEventTypeCompleted:
+			completed = event.Response
+		case openai.ResponseStreamEventTypeCreated, openai.ResponseStreamEventTypeOutputTextDelta:
+		default:
+			custom = event.Raw
+		}
+	}
+
+	if len(types) != 7 {
+		t.Fatalf("expected 7 events, got %v", types)
+	}
+	if item == nil || item.CallID != "call_1" || item.Name != "lookup" {
+		t.Fatalf("unexpected function call item: %+v", item)
+	}
+	if arguments.String() != `{"q":"x"}` {
+		t.Fatalf("unexpected streamed arguments: %s", arguments.String())
+	}
+	if completed == nil || completed.Usage == nil || completed.Usage.TotalTokens != 8 {
+		t.Fatalf("expected usage on the completed event, got %+v", completed)
+	}
+	if !strings.Contains(string(custom), `"detail":"kept"`) {
+		t.Fatalf("expected unknown events to be passed through, got %s", custom)
+	}
+}
@@ -0,0 +1,197 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls automatic retries of failed requests.
+// Retries are disabled when MaxRetries is zero.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry, doubled on every following attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. Zero means no cap.
+	MaxBackoff time.Duration
+	// Classifier decides whether a failed attempt is retried and how long to wait.
+	// Defaults to DefaultRetryClassifier.
+	Classifier RetryClassifier
+	// HonorRetryAfter waits for the delay of the Retry-After header of a retried
+	// response, in seconds or as an HTTP date, instead of the exponential backoff.
+	HonorRetryAfter bool
+	// Jitter randomizes every exponential backoff between half and all of its delay,
+	// so clients failing together do not retry in lockstep.
+	Jitter bool
+}
+
+// RetryError is returned when a request sent more than once still failed. It wraps
+// the error of the last attempt, such as an *APIError or a *RequestError.
+type RetryError struct {
+	// Attempts is the number of times the request was sent.
+	Attempts int
+	// HTTPStatusCode is the status code of the last response, zero when the last
+	// attempt failed without a response.
+	HTTPStatusCode int
+	Err            error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// retryFailure wraps err in a *RetryError when the request took more than one attempt.
+// resp is the last response, nil when there is none.
+func retryFailure(err error, attempts int, resp *http.Response) error {
+	if attempts <= 1 {
+		return err
+	}
+	retryErr := &RetryError{Attempts: attempts, Err: err}
+	if resp != nil {
+		retryErr.HTTPStatusCode = resp.StatusCode
+	}
+	return retryErr
+}
+
+// RetryDecision is the outcome of a RetryClassifier.
+type RetryDecision struct {
+	// Retry reports whether the request should be sent again.
+	Retry bool
+	// Delay overrides the exponential backoff before the retry when positive.
+	Delay time.Duration
+}
+
+// RetryClassifier decides whether a request which produced resp and err is retried.
+// resp is nil when err is set. The classifier must not consume the response body.
+type RetryClassifier func(resp *http.Response, err error) RetryDecision
+
+// DefaultRetryClassifier retries transport errors other than context cancellation,
+// 429 Too Many Requests and 5xx statuses, using the exponential backoff.
+func DefaultRetryClassifier(resp *http.Response, err error) RetryDecision {
+	return RetryDecision{Retry: isRetryable(resp, err)}
+}
+
+// backoff returns the delay to wait before the given retry attempt (zero based).
+func (r RetryConfig) backoff(attempt int) time.Duration {
+	delay := r.InitialBackoff
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if r.MaxBackoff > 0 && delay >= r.MaxBackoff {
+			break
+		}
+	}
+	if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+	if r.Jitter && delay > 1 {
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(delay-half)+1)) //nolint:gosec // jitter needs no crypto
+	}
+	return delay
+}
+
+// retryAfter returns the delay requested by the Retry-After header of resp, zero when
+// the header is missing, invalid or in the past.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// isRetryable reports whether a request which produced resp and err should be tried again.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// fitsDeadline reports whether waiting for delay still leaves the context alive.
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	return time.Until(deadline) > delay
+}
+
+// rewindBody prepares req to be sent again. Requests whose body cannot be
+// replayed are reported as not rewindable.
+func rewindBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+// doRequest sends req through the configured HTTPClient, retrying transient
+// failures according to the RetryConfig, and returns the number of attempts made.
+// A retry whose delay would run past the context deadline is skipped and the last
+// result is returned immediately.
+func (c *Client) doRequest(req *http.Request) (*http.Response, int, error) {
+	retry := c.config.Retry
+	classify := retry.Classifier
+	if classify == nil {
+		classify = DefaultRetryClassifier
+	}
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := c.config.HTTPClient.Do(req)
+		if attempt >= retry.MaxRetries {
+			return resp, attempt + 1, err
+		}
+		decision := classify(resp, err)
+		if !decision.Retry {
+			return resp, attempt + 1, err
+		}
+
+		delay := decision.Delay
+		if delay <= 0 && retry.HonorRetryAfter {
+			delay = retryAfter(resp)
+		}
+		if delay <= 0 {
+			delay = retry.backoff(attempt)
+		}
+		if !fitsDeadline(ctx, delay) || !rewindBody(req) {
+			return resp, attempt + 1, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt + 1, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
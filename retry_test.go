@@ -0,0 +1,212 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupRetryTestServer(retry openai.RetryConfig) (
+	client *openai.Client, server *test.ServerTest, teardown func(),
+) {
+	server = test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	teardown = ts.Close
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.Retry = retry
+	client = openai.NewClientWithConfig(config)
+	return
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(openai.RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+	})
+	defer teardown()
+
+	var attempts int
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 {
+			t.Errorf("attempt %d sent an empty body", attempts)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handleChatCompletionEndpoint(w, r)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model: openai.GPT3Dot5Turbo,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: "Hello!"},
+		},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(openai.RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+	})
+	defer teardown()
+
+	var attempts int
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.HasError(t, err, "ListModels should fail")
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(openai.RetryConfig{
+		MaxRetries:     5,
+		InitialBackoff: time.Second,
+	})
+	defer teardown()
+
+	var attempts int
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ListModels(ctx)
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("retry slept past the deadline budget: %s", elapsed)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt, got %d", attempts)
+	}
+
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) || reqErr.HTTPStatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the last HTTP error to be returned, got %v", err)
+	}
+}
+
+func TestRetryClassifier(t *testing.T) {
+	var classified []int
+	client, server, teardown := setupRetryTestServer(openai.RetryConfig{
+		MaxRetries:     3,
+		InitialBackoff: time.Hour,
+		Classifier: func(resp *http.Response, err error) openai.RetryDecision {
+			if err != nil {
+				return openai.DefaultRetryClassifier(resp, err)
+			}
+			classified = append(classified, resp.StatusCode)
+			// This gateway reports transient overload as 503 and permanent failures as 500.
+			return openai.RetryDecision{Retry: resp.StatusCode == http.StatusServiceUnavailable, Delay: time.Millisecond}
+		},
+	})
+	defer teardown()
+
+	var attempts int
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.HasError(t, err, "ListModels should fail")
+	if attempts != 2 || len(classified) != 2 || classified[1] != http.StatusInternalServerError {
+		t.Fatalf("expected the 500 to stop retries after 2 attempts, got %d attempts, classified %v",
+			attempts, classified)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(openai.RetryConfig{
+		MaxRetries:      1,
+		InitialBackoff:  time.Hour,
+		HonorRetryAfter: true,
+		Jitter:          true,
+	})
+	defer teardown()
+
+	var attempts int
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.ListModels(ctx)
+	checks.NoError(t, err, "ListModels should succeed after the Retry-After delay")
+	if elapsed := time.Since(start); attempts != 2 || elapsed < time.Second || elapsed > 3*time.Second {
+		t.Fatalf("expected a retry after about one second, got %d attempts in %s", attempts, elapsed)
+	}
+}
+
+func TestRetryErrorWrapsLastStatus(t *testing.T) {
+	client, server, teardown := setupRetryTestServer(openai.RetryConfig{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		Jitter:         true,
+	})
+	defer teardown()
+
+	var attempts int
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.ListModels(context.Background())
+	var retryErr *openai.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 || retryErr.HTTPStatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a RetryError after 3 attempts, got %v", err)
+	}
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) || reqErr.HTTPStatusCode != http.StatusBadGateway || attempts != 3 {
+		t.Fatalf("expected the last RequestError to be wrapped, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	_, err = client.ListModels(ctx)
+	checks.ErrorIs(t, err, context.Canceled, "expected the retry loop to stop on cancellation")
+}
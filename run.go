@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type Run struct {
@@ -41,6 +42,18 @@ type Run struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (r Run) CreatedTime() time.Time {
+	return time.Unix(r.CreatedAt, 0)
+}
+
+// EffectiveInstructions returns the instructions that were actually applied to the run.
+// The server resolves the assistant instructions, a per-run Instructions override and any
+// AdditionalInstructions into the run's instructions field, so the value reflects that result.
+func (r Run) EffectiveInstructions() string {
+	return r.Instructions
+}
+
 type RunStatus string
 
 const (
@@ -55,6 +68,69 @@ const (
 	RunStatusCancelled      RunStatus = "cancelled"
 )
 
+// runStatusTransitions lists the statuses a run may move to from a given status.
+// Terminal statuses have no outgoing transitions.
+var runStatusTransitions = map[RunStatus][]RunStatus{
+	RunStatusQueued: {
+		RunStatusInProgress, RunStatusCancelling, RunStatusFailed, RunStatusExpired,
+	},
+	RunStatusInProgress: {
+		RunStatusRequiresAction, RunStatusCompleted, RunStatusIncomplete,
+		RunStatusFailed, RunStatusCancelling, RunStatusExpired,
+	},
+	RunStatusRequiresAction: {
+		RunStatusInProgress, RunStatusCancelling, RunStatusFailed, RunStatusExpired,
+	},
+	RunStatusCancelling: {
+		RunStatusCancelled, RunStatusCompleted, RunStatusFailed, RunStatusExpired,
+	},
+}
+
+// IsTerminal reports whether the run has finished and will not change status again.
+func (s RunStatus) IsTerminal() bool {
+	switch s {
+	case RunStatusCompleted, RunStatusFailed, RunStatusCancelled, RunStatusExpired, RunStatusIncomplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsActive reports whether the run is being processed by the server.
+func (s RunStatus) IsActive() bool {
+	switch s {
+	case RunStatusQueued, RunStatusInProgress, RunStatusCancelling:
+		return true
+	default:
+		return false
+	}
+}
+
+// RequiresAction reports whether the run is waiting for tool outputs to be submitted.
+func (s RunStatus) RequiresAction() bool {
+	return s == RunStatusRequiresAction
+}
+
+// CanTransitionTo reports whether a run may move from s to next:
+//
+//	queued          -> in_progress, cancelling, failed, expired
+//	in_progress     -> requires_action, completed, incomplete, failed, cancelling, expired
+//	requires_action -> in_progress, cancelling, failed, expired
+//	cancelling      -> cancelled, completed, failed, expired
+//
+// Terminal statuses cannot transition. Staying in the same status is always allowed.
+func (s RunStatus) CanTransitionTo(next RunStatus) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range runStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type RunRequiredAction struct {
 	Type              RequiredActionType `json:"type"`
 	SubmitToolOutputs *SubmitToolOutputs `json:"submit_tool_outputs,omitempty"`
@@ -177,6 +253,7 @@ type RunStep struct {
 	Status      RunStepStatus  `json:"status"`
 	StepDetails StepDetails    `json:"step_details"`
 	LastError   *RunLastError  `json:"last_error,omitempty"`
+	StartedAt   *int64         `json:"started_at,omitempty"`
 	ExpiredAt   *int64         `json:"expired_at,omitempty"`
 	CancelledAt *int64         `json:"cancelled_at,omitempty"`
 	FailedAt    *int64         `json:"failed_at,omitempty"`
@@ -186,6 +263,60 @@ type RunStep struct {
 	httpHeader
 }
 
+// unixTime converts an optional Unix timestamp, returning the zero time when it is unset.
+func unixTime(timestamp *int64) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return time.Unix(*timestamp, 0)
+}
+
+// CreatedTime returns CreatedAt as a time.Time.
+func (s RunStep) CreatedTime() time.Time {
+	return time.Unix(s.CreatedAt, 0)
+}
+
+// StartedTime returns StartedAt as a time.Time, or the zero time when it is unset.
+func (s RunStep) StartedTime() time.Time {
+	return unixTime(s.StartedAt)
+}
+
+// CompletedTime returns CompletedAt as a time.Time, or the zero time when it is unset.
+func (s RunStep) CompletedTime() time.Time {
+	return unixTime(s.CompletedAt)
+}
+
+// FailedTime returns FailedAt as a time.Time, or the zero time when it is unset.
+func (s RunStep) FailedTime() time.Time {
+	return unixTime(s.FailedAt)
+}
+
+// CancelledTime returns CancelledAt as a time.Time, or the zero time when it is unset.
+func (s RunStep) CancelledTime() time.Time {
+	return unixTime(s.CancelledAt)
+}
+
+// ExpiredTime returns ExpiredAt as a time.Time, or the zero time when it is unset.
+func (s RunStep) ExpiredTime() time.Time {
+	return unixTime(s.ExpiredAt)
+}
+
+// Duration returns how long the step ran, from StartedAt, or CreatedAt when it is unset,
+// until it completed, failed, was cancelled or expired. It is zero while the step is
+// still in progress. Timestamps have a resolution of one second.
+func (s RunStep) Duration() time.Duration {
+	start := s.StartedAt
+	if start == nil {
+		start = &s.CreatedAt
+	}
+	for _, end := range []*int64{s.CompletedAt, s.FailedAt, s.CancelledAt, s.ExpiredAt} {
+		if end != nil {
+			return time.Duration(*end-*start) * time.Second
+		}
+	}
+	return 0
+}
+
 type RunStepStatus string
 
 const (
@@ -237,6 +368,14 @@ func (c *Client) CreateRun(
 	threadID string,
 	request RunRequest,
 ) (response Run, err error) {
+	request, err = c.mergeDefaultRunMetadata(request)
+	if err != nil {
+		return
+	}
+	if err = c.validateThreadMessages("additional_messages", request.AdditionalMessages); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs", threadID)
 	req, err := c.newRequest(
 		ctx,
@@ -249,6 +388,9 @@ func (c *Client) CreateRun(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.rememberRunThread(response)
+	}
 	return
 }
 
@@ -258,6 +400,10 @@ func (c *Client) RetrieveRun(
 	threadID string,
 	runID string,
 ) (response Run, err error) {
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s", threadID, runID)
 	req, err := c.newRequest(
 		ctx,
@@ -269,6 +415,9 @@ func (c *Client) RetrieveRun(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.rememberRunThread(response)
+	}
 	return
 }
 
@@ -279,6 +428,10 @@ func (c *Client) ModifyRun(
 	runID string,
 	request RunModifyRequest,
 ) (response Run, err error) {
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s", threadID, runID)
 	req, err := c.newRequest(
 		ctx,
@@ -291,6 +444,9 @@ func (c *Client) ModifyRun(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.rememberRunThread(response)
+	}
 	return
 }
 
@@ -330,6 +486,9 @@ func (c *Client) ListRuns(
 	}
 
 	err = c.sendRequest(req, &response)
+	for _, run := range response.Runs {
+		c.rememberRunThread(run)
+	}
 	return
 }
 
@@ -339,6 +498,10 @@ func (c *Client) SubmitToolOutputs(
 	threadID string,
 	runID string,
 	request SubmitToolOutputsRequest) (response Run, err error) {
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/submit_tool_outputs", threadID, runID)
 	req, err := c.newRequest(
 		ctx,
@@ -351,6 +514,9 @@ func (c *Client) SubmitToolOutputs(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.rememberRunThread(response)
+	}
 	return
 }
 
@@ -359,6 +525,10 @@ func (c *Client) CancelRun(
 	ctx context.Context,
 	threadID string,
 	runID string) (response Run, err error) {
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/cancel", threadID, runID)
 	req, err := c.newRequest(
 		ctx,
@@ -370,6 +540,9 @@ func (c *Client) CancelRun(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.rememberRunThread(response)
+	}
 	return
 }
 
@@ -377,6 +550,21 @@ func (c *Client) CancelRun(
 func (c *Client) CreateThreadAndRun(
 	ctx context.Context,
 	request CreateThreadAndRunRequest) (response Run, err error) {
+	request.RunRequest, err = c.mergeDefaultRunMetadata(request.RunRequest)
+	if err != nil {
+		return
+	}
+	request.Thread, err = c.mergeDefaultThreadMetadata(request.Thread)
+	if err != nil {
+		return
+	}
+	if err = c.validateThreadMessages("additional_messages", request.AdditionalMessages); err != nil {
+		return
+	}
+	if err = c.validateThreadMessages("thread.messages", request.Thread.Messages); err != nil {
+		return
+	}
+
 	urlSuffix := "/threads/runs"
 	req, err := c.newRequest(
 		ctx,
@@ -389,6 +577,9 @@ func (c *Client) CreateThreadAndRun(
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.rememberRunThread(response)
+	}
 	return
 }
 
@@ -399,6 +590,10 @@ func (c *Client) RetrieveRunStep(
 	runID string,
 	stepID string,
 ) (response RunStep, err error) {
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/steps/%s", threadID, runID, stepID)
 	req, err := c.newRequest(
 		ctx,
@@ -439,6 +634,10 @@ func (c *Client) ListRunSteps(
 		encodedValues = "?" + urlValues.Encode()
 	}
 
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/steps%s", threadID, runID, encodedValues)
 	req, err := c.newRequest(
 		ctx,
@@ -452,3 +651,50 @@ func (c *Client) ListRunSteps(
 	err = c.sendRequest(req, &response)
 	return
 }
+
+const defaultRunPollInterval = time.Second
+
+// WaitForRun polls the run every pollInterval until it reaches a terminal status or
+// requires action, and returns it. A non-positive pollInterval defaults to one second.
+// Polling stops with the context error when ctx is done.
+func (c *Client) WaitForRun(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	pollInterval time.Duration) (response Run, err error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultRunPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		response, err = c.RetrieveRun(ctx, threadID, runID)
+		if err != nil || response.Status.IsTerminal() || response.Status.RequiresAction() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SubmitToolOutputsAndWait submits tool outputs for a run which requires action and
+// waits with WaitForRun until it is terminal or requires action again, so a tool loop
+// only has to check the returned run's status.
+func (c *Client) SubmitToolOutputsAndWait(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	outputs []ToolOutput,
+	pollInterval time.Duration) (response Run, err error) {
+	response, err = c.SubmitToolOutputs(ctx, threadID, runID, SubmitToolOutputsRequest{ToolOutputs: outputs})
+	if err != nil || response.Status.IsTerminal() || response.Status.RequiresAction() {
+		return
+	}
+	return c.WaitForRun(ctx, threadID, runID, pollInterval)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 
 	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
 	"github.com/sashabaranov/go-openai/internal/test/checks"
 
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 )
 
 // TestAssistant Tests the assistant endpoint of the API using the mocked server.
@@ -235,3 +237,225 @@ func TestRun(t *testing.T) {
 	)
 	checks.NoError(t, err, "ListRunSteps error")
 }
+
+func TestRunStatusLifecycle(t *testing.T) {
+	if !openai.RunStatusCompleted.IsTerminal() || openai.RunStatusInProgress.IsTerminal() {
+		t.Fatalf("unexpected terminal classification")
+	}
+	if !openai.RunStatusQueued.IsActive() || openai.RunStatusRequiresAction.IsActive() {
+		t.Fatalf("unexpected active classification")
+	}
+	if !openai.RunStatusRequiresAction.RequiresAction() {
+		t.Fatalf("requires_action should require action")
+	}
+
+	testCases := []struct {
+		from, to openai.RunStatus
+		allowed  bool
+	}{
+		{openai.RunStatusQueued, openai.RunStatusInProgress, true},
+		{openai.RunStatusInProgress, openai.RunStatusRequiresAction, true},
+		{openai.RunStatusRequiresAction, openai.RunStatusInProgress, true},
+		{openai.RunStatusCancelling, openai.RunStatusCancelled, true},
+		{openai.RunStatusCompleted, openai.RunStatusInProgress, false},
+		{openai.RunStatusQueued, openai.RunStatusCompleted, false},
+	}
+	for _, tc := range testCases {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
+
+func TestRunEffectiveInstructions(t *testing.T) {
+	var run openai.Run
+	err := json.Unmarshal([]byte(`{"id":"run_abc123","instructions":"Be brief. Address the user as Jane."}`), &run)
+	checks.NoError(t, err, "Unmarshal error")
+	if run.EffectiveInstructions() != "Be brief. Address the user as Jane." {
+		t.Fatalf("unexpected effective instructions: %q", run.EffectiveInstructions())
+	}
+}
+
+func TestSubmitToolOutputsAndWait(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var submitted openai.SubmitToolOutputsRequest
+	server.RegisterHandler("/v1/threads/thread_abc123/runs/run_abc123/submit_tool_outputs",
+		func(w http.ResponseWriter, r *http.Request) {
+			err := json.NewDecoder(r.Body).Decode(&submitted)
+			checks.NoError(t, err, "Decode error")
+			fmt.Fprintln(w, `{"id":"run_abc123","status":"queued"}`)
+		})
+	polls := 0
+	server.RegisterHandler("/v1/threads/thread_abc123/runs/run_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		status := openai.RunStatusInProgress
+		if polls == 3 {
+			status = openai.RunStatusRequiresAction
+		}
+		resBytes, _ := json.Marshal(openai.Run{ID: "run_abc123", Status: status})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	run, err := client.SubmitToolOutputsAndWait(context.Background(), "thread_abc123", "run_abc123",
+		[]openai.ToolOutput{{ToolCallID: "call_abc123", Output: "42"}}, time.Millisecond)
+	checks.NoError(t, err, "SubmitToolOutputsAndWait error")
+	if !run.Status.RequiresAction() || polls != 3 {
+		t.Fatalf("unexpected run %+v after %d polls", run, polls)
+	}
+	if len(submitted.ToolOutputs) != 1 || submitted.ToolOutputs[0].Output != "42" {
+		t.Fatalf("unexpected submitted outputs: %+v", submitted)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.WaitForRun(ctx, "thread_abc123", "run_abc123", time.Millisecond)
+	checks.ErrorIs(t, err, context.Canceled, "expected context error")
+}
+
+func TestWaitForRunDeadline(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/threads/thread_abc123/runs/run_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"id":"run_abc123","status":"in_progress"}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.WaitForRun(ctx, "thread_abc123", "run_abc123", time.Hour)
+	checks.ErrorIs(t, err, context.DeadlineExceeded, "expected deadline error")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected WaitForRun to return at the deadline, took %v", elapsed)
+	}
+}
+
+func TestRunStepTiming(t *testing.T) {
+	var step openai.RunStep
+	err := json.Unmarshal([]byte(`{"id":"step_abc123","created_at":1700000000,"started_at":1700000002,
+		"completed_at":1700000012}`), &step)
+	checks.NoError(t, err, "Unmarshal error")
+	if step.Duration() != 10*time.Second {
+		t.Fatalf("expected a 10s step, got %s", step.Duration())
+	}
+	if !step.StartedTime().Equal(time.Unix(1700000002, 0)) || !step.FailedTime().IsZero() {
+		t.Fatalf("unexpected timestamps: started %s, failed %s", step.StartedTime(), step.FailedTime())
+	}
+
+	step.StartedAt, step.CompletedAt = nil, nil
+	if step.Duration() != 0 {
+		t.Fatalf("expected an unfinished step to have no duration, got %s", step.Duration())
+	}
+	failedAt := int64(1700000005)
+	step.FailedAt = &failedAt
+	if step.Duration() != 5*time.Second || !step.CreatedTime().Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("expected the duration to start at CreatedAt, got %s", step.Duration())
+	}
+}
+
+func TestCheckRunThreads(t *testing.T) {
+	ts := test.NewTestServer()
+	requests := 0
+	ts.RegisterHandler("/v1/threads/thread_abc123/runs", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"id":"run_abc123","thread_id":"thread_abc123","status":"queued"}`)
+	})
+	ts.RegisterHandler("/v1/threads/thread_other/runs/run_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"id":"run_abc123","thread_id":"thread_other"}`)
+	})
+	ts.RegisterHandler("/v1/threads/thread_other/runs/run_unknown/cancel", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		fmt.Fprintln(w, `{"id":"run_unknown","thread_id":"thread_other"}`)
+	})
+	server := ts.OpenAITestServer()
+	server.Start()
+	defer server.Close()
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = server.URL + "/v1"
+	config.CheckRunThreads = true
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+
+	_, err := client.CreateRun(ctx, "thread_abc123", openai.RunRequest{AssistantID: "asst_abc123"})
+	checks.NoError(t, err, "CreateRun error")
+
+	_, err = client.RetrieveRun(ctx, "thread_other", "run_abc123")
+	checks.ErrorIs(t, err, openai.ErrRunThreadMismatch, "RetrieveRun should detect the mismatch")
+	_, err = client.ListRunSteps(ctx, "thread_other", "run_abc123", openai.Pagination{})
+	checks.ErrorIs(t, err, openai.ErrRunThreadMismatch, "ListRunSteps should detect the mismatch")
+	if requests != 1 {
+		t.Fatalf("mismatched calls reached the server: %d requests", requests)
+	}
+
+	_, err = client.CancelRun(ctx, "thread_other", "run_unknown")
+	checks.NoError(t, err, "runs the client has not seen should not be checked")
+
+	config.CheckRunThreads = false
+	client = openai.NewClientWithConfig(config)
+	_, err = client.CreateRun(ctx, "thread_abc123", openai.RunRequest{AssistantID: "asst_abc123"})
+	checks.NoError(t, err, "CreateRun error")
+	_, err = client.RetrieveRun(ctx, "thread_other", "run_abc123")
+	checks.NoError(t, err, "the check should be disabled by default")
+}
+
+func TestRunAdditionalMessagesFromMessageRequests(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var body map[string]json.RawMessage
+	server.RegisterHandler("/v1/threads/thread_abc123/runs", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		fmt.Fprint(w, `{"id":"run_abc123","object":"thread.run","thread_id":"thread_abc123"}`)
+	})
+
+	seeds := []openai.MessageRequest{
+		openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "Here is the data."),
+		{
+			Role: string(openai.ThreadMessageRoleUser),
+			ContentParts: []openai.MessageContent{
+				openai.NewTextMessageContent("And the chart:"),
+				openai.NewImageFileMessageContent("file_chart"),
+			},
+			Attachments: []openai.ThreadAttachment{
+				openai.NewThreadAttachment("file_data", openai.AssistantToolTypeCodeInterpreter),
+			},
+			Metadata: map[string]any{"seed": "2"},
+		},
+	}
+	request := openai.RunRequest{AssistantID: "asst_abc123"}
+	for _, seed := range seeds {
+		request.AdditionalMessages = append(request.AdditionalMessages, seed.ThreadMessage())
+	}
+
+	_, err := client.CreateRun(context.Background(), "thread_abc123", request)
+	checks.NoError(t, err, "CreateRun error")
+	want := `[{"role":"user","content":"Here is the data."},` +
+		`{"role":"user","attachments":[{"file_id":"file_data","tools":[{"type":"code_interpreter"}]}],` +
+		`"metadata":{"seed":"2"},"content":[{"type":"text","text":"And the chart:"},` +
+		`{"type":"image_file","image_file":{"file_id":"file_chart"}}]}]`
+	if string(body["additional_messages"]) != want {
+		t.Fatalf("unexpected additional_messages %s", body["additional_messages"])
+	}
+
+	request.AdditionalMessages = append(request.AdditionalMessages,
+		openai.NewMessageRequest("system", "Be brief.").ThreadMessage())
+	body = nil
+	_, err = client.CreateRun(context.Background(), "thread_abc123", request)
+	checks.ErrorIs(t, err, openai.ErrMessageRequestInvalidRole, "expected the role check of CreateMessage")
+	if body != nil || err.Error() != `additional_messages[2]: message role must be either user or assistant, got "system"` {
+		t.Fatalf("expected the run to be rejected before it is sent, got %v", err)
+	}
+
+	_, err = client.CreateThreadAndRun(context.Background(), openai.CreateThreadAndRunRequest{
+		RunRequest: openai.RunRequest{AssistantID: "asst_abc123"},
+		Thread: openai.ThreadRequest{Messages: []openai.ThreadMessage{
+			openai.NewMessageRequest(string(openai.ThreadMessageRoleUser), "").ThreadMessage(),
+		}},
+	})
+	checks.ErrorIs(t, err, openai.ErrMessageRequestEmptyContent, "expected thread messages to be checked")
+}
@@ -0,0 +1,49 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// maxTrackedRuns bounds the memory used to remember which thread a run belongs to.
+const maxTrackedRuns = 10000
+
+var ErrRunThreadMismatch = errors.New("run belongs to a different thread")
+
+// runThreadRegistry remembers the thread of every run seen in a response.
+type runThreadRegistry struct {
+	mu      sync.Mutex
+	threads map[string]string
+}
+
+// rememberRunThread records the thread of run when CheckRunThreads is enabled.
+// Once maxTrackedRuns runs are known the registry starts over.
+func (c *Client) rememberRunThread(run Run) {
+	if !c.config.CheckRunThreads || run.ID == "" || run.ThreadID == "" {
+		return
+	}
+
+	c.runThreads.mu.Lock()
+	defer c.runThreads.mu.Unlock()
+	if c.runThreads.threads == nil || len(c.runThreads.threads) >= maxTrackedRuns {
+		c.runThreads.threads = make(map[string]string)
+	}
+	c.runThreads.threads[run.ID] = run.ThreadID
+}
+
+// checkRunThread returns ErrRunThreadMismatch when runID is known to belong to
+// a thread other than threadID. Unknown runs always pass.
+func (c *Client) checkRunThread(threadID, runID string) error {
+	if !c.config.CheckRunThreads {
+		return nil
+	}
+
+	c.runThreads.mu.Lock()
+	owner, ok := c.runThreads.threads[runID]
+	c.runThreads.mu.Unlock()
+	if ok && owner != threadID {
+		return fmt.Errorf("%w: run %s belongs to thread %s, not %s", ErrRunThreadMismatch, runID, owner, threadID)
+	}
+	return nil
+}
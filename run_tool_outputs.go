@@ -0,0 +1,132 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ToolCallResult is one tool call made during a run, with its inputs and outputs.
+type ToolCallResult struct {
+	StepID     string
+	ToolCallID string
+	Type       AssistantToolType
+
+	// Input, Logs and ImageFileIDs are set on code_interpreter calls.
+	Input        string
+	Logs         []string
+	ImageFileIDs []string
+
+	// FunctionName, Arguments and Output are set on function calls.
+	FunctionName string
+	Arguments    string
+	Output       string
+}
+
+type runStepCodeInterpreterOutput struct {
+	Type  string `json:"type"`
+	Logs  string `json:"logs,omitempty"`
+	Image *struct {
+		FileID string `json:"file_id"`
+	} `json:"image,omitempty"`
+}
+
+// runStepToolCall is a tool call as it appears in run step details, which carries
+// more than the chat ToolCall used by StepDetails.
+type runStepToolCall struct {
+	ID              string            `json:"id"`
+	Type            AssistantToolType `json:"type"`
+	CodeInterpreter *struct {
+		Input   string                         `json:"input"`
+		Outputs []runStepCodeInterpreterOutput `json:"outputs"`
+	} `json:"code_interpreter,omitempty"`
+	Function *struct {
+		Name      string  `json:"name"`
+		Arguments string  `json:"arguments"`
+		Output    *string `json:"output"`
+	} `json:"function,omitempty"`
+}
+
+type runStepToolCallsList struct {
+	Data []struct {
+		ID          string `json:"id"`
+		StepDetails struct {
+			ToolCalls []runStepToolCall `json:"tool_calls,omitempty"`
+		} `json:"step_details"`
+	} `json:"data"`
+	LastID  string `json:"last_id"`
+	HasMore bool   `json:"has_more"`
+
+	httpHeader
+}
+
+// CollectRunToolOutputs lists all steps of a run and returns its tool calls in execution
+// order, with code_interpreter logs and image file IDs and function arguments and outputs.
+func (c *Client) CollectRunToolOutputs(
+	ctx context.Context,
+	threadID string,
+	runID string,
+) (results []ToolCallResult, err error) {
+	if err = c.checkRunThread(threadID, runID); err != nil {
+		return
+	}
+
+	after := ""
+	for {
+		urlValues := url.Values{}
+		urlValues.Add("order", "asc")
+		if after != "" {
+			urlValues.Add("after", after)
+		}
+		urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/steps?%s", threadID, runID, urlValues.Encode())
+
+		var req *http.Request
+		req, err = c.newRequest(
+			ctx,
+			http.MethodGet,
+			c.fullURL(urlSuffix),
+			withBetaAssistantVersion(c.config.AssistantVersion))
+		if err != nil {
+			return
+		}
+
+		var steps runStepToolCallsList
+		if err = c.sendRequest(req, &steps); err != nil {
+			return
+		}
+		for _, step := range steps.Data {
+			for _, call := range step.StepDetails.ToolCalls {
+				results = append(results, call.result(step.ID))
+			}
+		}
+
+		if !steps.HasMore || steps.LastID == "" {
+			return
+		}
+		after = steps.LastID
+	}
+}
+
+func (call runStepToolCall) result(stepID string) ToolCallResult {
+	result := ToolCallResult{StepID: stepID, ToolCallID: call.ID, Type: call.Type}
+	if call.CodeInterpreter != nil {
+		result.Input = call.CodeInterpreter.Input
+		for _, output := range call.CodeInterpreter.Outputs {
+			switch {
+			case output.Logs != "":
+				result.Logs = append(result.Logs, output.Logs)
+			case output.Image != nil:
+				result.ImageFileIDs = append(result.ImageFileIDs, output.Image.FileID)
+			}
+		}
+	}
+	if call.Function != nil {
+		result.FunctionName = call.Function.Name
+		result.Arguments = call.Function.Arguments
+		if call.Function.Output != nil {
+			result.Output = *call.Function.Output
+		}
+	}
+	return result
+}
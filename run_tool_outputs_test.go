@@ -0,0 +1,51 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCollectRunToolOutputs(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/threads/thread_abc123/runs/run_abc123/steps", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("order") != "asc" {
+			t.Errorf("expected ascending order, got %q", r.URL.Query().Get("order"))
+		}
+		if r.URL.Query().Get("after") == "" {
+			fmt.Fprintln(w, `{"data":[
+				{"id":"step_1","step_details":{"type":"message_creation","message_creation":{"message_id":"msg_1"}}},
+				{"id":"step_2","step_details":{"type":"tool_calls","tool_calls":[{"id":"call_1","type":"code_interpreter",
+					"code_interpreter":{"input":"print(1)","outputs":[{"type":"logs","logs":"1\n"},
+					{"type":"image","image":{"file_id":"file_img"}}]}}]}}],
+				"last_id":"step_2","has_more":true}`)
+			return
+		}
+		fmt.Fprintln(w, `{"data":[{"id":"step_3","step_details":{"type":"tool_calls","tool_calls":[{"id":"call_2",
+			"type":"function","function":{"name":"lookup","arguments":"{\"q\":\"x\"}","output":"found"}}]}}],
+			"last_id":"step_3","has_more":false}`)
+	})
+
+	results, err := client.CollectRunToolOutputs(context.Background(), "thread_abc123", "run_abc123")
+	checks.NoError(t, err, "CollectRunToolOutputs error")
+	if len(results) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", results)
+	}
+
+	code := results[0]
+	if code.StepID != "step_2" || code.Type != openai.AssistantToolTypeCodeInterpreter || code.Input != "print(1)" ||
+		len(code.Logs) != 1 || code.Logs[0] != "1\n" || len(code.ImageFileIDs) != 1 || code.ImageFileIDs[0] != "file_img" {
+		t.Fatalf("unexpected code interpreter result: %+v", code)
+	}
+	function := results[1]
+	if function.ToolCallID != "call_2" || function.FunctionName != "lookup" ||
+		function.Arguments != `{"q":"x"}` || function.Output != "found" {
+		t.Fatalf("unexpected function result: %+v", function)
+	}
+}
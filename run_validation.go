@@ -0,0 +1,176 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrRunRequestInvalid            = errors.New("invalid run request")
+	ErrRunToolChoiceNotFound        = errors.New("forced tool is not available to the run")
+	ErrRunResponseFormatUnsupported = errors.New("response format is not supported by the model")
+)
+
+// RunRequestValidationError lists every problem ValidateRunRequest found in a run
+// request. errors.Is matches ErrRunRequestInvalid as well as the error of each problem.
+type RunRequestValidationError struct {
+	Problems []error
+}
+
+func (e *RunRequestValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = problem.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrRunRequestInvalid, strings.Join(problems, "; "))
+}
+
+func (e *RunRequestValidationError) Is(target error) bool {
+	if target == ErrRunRequestInvalid {
+		return true
+	}
+	for _, problem := range e.Problems {
+		if errors.Is(problem, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// modelsWithoutResponseFormat are the models which reject any response format
+// other than text.
+var modelsWithoutResponseFormat = []string{
+	GPT4, GPT40314, GPT40613, GPT432K, GPT432K0314, GPT432K0613,
+	GPT3Dot5Turbo0301, GPT3Dot5Turbo0613, GPT3Dot5Turbo16K, GPT3Dot5Turbo16K0613,
+}
+
+// ValidateRunRequest checks a run request against the assistant it targets without
+// creating the run: a forced tool choice must name one of the run's tools, the response
+// format must be supported by the run's model and metadata must respect the API limits.
+// Problems are returned together in a *RunRequestValidationError.
+func (c *Client) ValidateRunRequest(ctx context.Context, assistantID string, request RunRequest) error {
+	assistant, err := c.RetrieveAssistant(ctx, assistantID)
+	if err != nil {
+		return err
+	}
+
+	var problems []error
+	if err = validateRunToolChoice(request.ToolChoice, runToolSet(assistant, request)); err != nil {
+		problems = append(problems, err)
+	}
+
+	model := request.Model
+	if model == "" {
+		model = assistant.Model
+	}
+	if err = validateRunResponseFormat(request.ResponseFormat, model); err != nil {
+		problems = append(problems, err)
+	}
+
+	metadata, err := c.mergeDefaultMetadata(request.Metadata)
+	if err == nil {
+		err = validateMetadata(metadata)
+	}
+	if err != nil {
+		problems = append(problems, fmt.Errorf("metadata: %w", err))
+	}
+	for i, message := range request.AdditionalMessages {
+		if err = validateMetadata(message.Metadata); err != nil {
+			problems = append(problems, fmt.Errorf("additional_messages[%d] metadata: %w", i, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &RunRequestValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// runToolSet returns the names of the functions and the types of the other tools the
+// run can use. Tools of the request replace those of the assistant.
+func runToolSet(assistant Assistant, request RunRequest) map[string]bool {
+	tools := make(map[string]bool)
+	if request.Tools != nil {
+		for _, tool := range request.Tools {
+			if tool.Type == ToolTypeFunction && tool.Function != nil {
+				tools[tool.Function.Name] = true
+			} else {
+				tools[string(tool.Type)] = true
+			}
+		}
+		return tools
+	}
+	for _, tool := range assistant.Tools {
+		if tool.Type == AssistantToolTypeFunction && tool.Function != nil {
+			tools[tool.Function.Name] = true
+		} else {
+			tools[string(tool.Type)] = true
+		}
+	}
+	return tools
+}
+
+// runOptionObject is the object form of a run option such as tool_choice.
+type runOptionObject struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// decodeRunOption decodes a "string or object" run option into one of its forms.
+func decodeRunOption(option any) (value string, object runOptionObject, err error) {
+	data, err := json.Marshal(option)
+	if err != nil {
+		return
+	}
+	if json.Unmarshal(data, &value) == nil {
+		return
+	}
+	err = json.Unmarshal(data, &object)
+	return
+}
+
+func validateRunToolChoice(toolChoice any, tools map[string]bool) error {
+	if toolChoice == nil {
+		return nil
+	}
+	value, object, err := decodeRunOption(toolChoice)
+	if err != nil {
+		return fmt.Errorf("tool_choice: %w", err)
+	}
+
+	switch {
+	case value == "required" && len(tools) == 0:
+		return fmt.Errorf("%w: tool_choice is required but the run has no tools", ErrRunToolChoiceNotFound)
+	case value != "":
+		return nil
+	case object.Type == string(ToolTypeFunction):
+		if !tools[object.Function.Name] {
+			return fmt.Errorf("%w: function %q", ErrRunToolChoiceNotFound, object.Function.Name)
+		}
+	case object.Type != "" && !tools[object.Type]:
+		return fmt.Errorf("%w: %s", ErrRunToolChoiceNotFound, object.Type)
+	}
+	return nil
+}
+
+func validateRunResponseFormat(responseFormat any, model string) error {
+	if responseFormat == nil {
+		return nil
+	}
+	value, object, err := decodeRunOption(responseFormat)
+	if err != nil {
+		return fmt.Errorf("response_format: %w", err)
+	}
+	if value == "auto" || object.Type == "" || object.Type == string(ChatCompletionResponseFormatTypeText) {
+		return nil
+	}
+	for _, unsupported := range modelsWithoutResponseFormat {
+		if model == unsupported {
+			return fmt.Errorf("%w: %s with %s", ErrRunResponseFormatUnsupported, object.Type, model)
+		}
+	}
+	return nil
+}
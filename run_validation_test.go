@@ -0,0 +1,59 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestValidateRunRequest(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/assistants/asst_abc123", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"id":"asst_abc123","model":"gpt-4o","tools":[{"type":"code_interpreter"},
+			{"type":"function","function":{"name":"lookup"}}]}`)
+	})
+	ctx := context.Background()
+
+	err := client.ValidateRunRequest(ctx, "asst_abc123", openai.RunRequest{
+		AssistantID:    "asst_abc123",
+		ToolChoice:     openai.ToolChoice{Type: openai.ToolTypeFunction, Function: openai.ToolFunction{Name: "lookup"}},
+		ResponseFormat: openai.ReponseFormat{Type: "json_object"},
+		Metadata:       map[string]any{"tenant": "acme"},
+	})
+	checks.NoError(t, err, "expected a valid run request")
+
+	err = client.ValidateRunRequest(ctx, "asst_abc123", openai.RunRequest{
+		AssistantID:    "asst_abc123",
+		Model:          openai.GPT4,
+		ToolChoice:     map[string]any{"type": "file_search"},
+		ResponseFormat: openai.ReponseFormat{Type: "json_object"},
+		AdditionalMessages: []openai.ThreadMessage{{
+			Role:     openai.ThreadMessageRoleUser,
+			Content:  "Hello",
+			Metadata: map[string]any{"note": strings.Repeat("x", 513)},
+		}},
+	})
+	checks.ErrorIs(t, err, openai.ErrRunRequestInvalid, "expected invalid run request")
+	checks.ErrorIs(t, err, openai.ErrRunToolChoiceNotFound, "expected missing forced tool")
+	checks.ErrorIs(t, err, openai.ErrRunResponseFormatUnsupported, "expected unsupported response format")
+	checks.ErrorIs(t, err, openai.ErrMetadataValueTooLong, "expected metadata limit error")
+	var validationErr *openai.RunRequestValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 3 {
+		t.Fatalf("expected 3 aggregated problems, got %v", err)
+	}
+
+	err = client.ValidateRunRequest(ctx, "asst_abc123", openai.RunRequest{
+		AssistantID: "asst_abc123",
+		Tools:       []openai.Tool{},
+		ToolChoice:  "required",
+	})
+	checks.ErrorIs(t, err, openai.ErrRunToolChoiceNotFound, "expected required tool choice without tools to fail")
+}
@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 )
 
@@ -25,6 +26,7 @@ const (
 	VoiceFable   SpeechVoice = "fable"
 	VoiceOnyx    SpeechVoice = "onyx"
 	VoiceNova    SpeechVoice = "nova"
+	VoiceSage    SpeechVoice = "sage"
 	VoiceShimmer SpeechVoice = "shimmer"
 	VoiceVerse   SpeechVoice = "verse"
 )
@@ -49,7 +51,31 @@ type CreateSpeechRequest struct {
 	Speed          float64              `json:"speed,omitempty"`           // Optional, default to 1.0
 }
 
+// validate rejects voices and response formats which are not one of the SpeechVoice
+// and SpeechResponseFormat values.
+func (r CreateSpeechRequest) validate() error {
+	switch r.Voice {
+	case VoiceAlloy, VoiceAsh, VoiceBallad, VoiceCoral, VoiceEcho, VoiceFable,
+		VoiceOnyx, VoiceNova, VoiceSage, VoiceShimmer, VoiceVerse:
+	default:
+		return fmt.Errorf("%w: voice %q", ErrAudioValueUnknown, r.Voice)
+	}
+	switch r.ResponseFormat {
+	case "", SpeechResponseFormatMp3, SpeechResponseFormatOpus, SpeechResponseFormatAac,
+		SpeechResponseFormatFlac, SpeechResponseFormatWav, SpeechResponseFormatPcm:
+	default:
+		return fmt.Errorf("%w: response format %q", ErrAudioValueUnknown, r.ResponseFormat)
+	}
+	return nil
+}
+
 func (c *Client) CreateSpeech(ctx context.Context, request CreateSpeechRequest) (response RawResponse, err error) {
+	if !c.config.AllowUnknownAudioValues {
+		if err = request.validate(); err != nil {
+			return
+		}
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -94,3 +94,39 @@ func TestSpeechIntegration(t *testing.T) {
 		checks.NoError(t, err, "Create error")
 	})
 }
+
+func TestSpeechUnknownValues(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	var sent openai.CreateSpeechRequest
+	server.RegisterHandler("/v1/audio/speech", func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&sent)
+		checks.NoError(t, err, "Decode error")
+		fmt.Fprint(w, "audio")
+	})
+
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	client := openai.NewClientWithConfig(config)
+	ctx := context.Background()
+
+	_, err := client.CreateSpeech(ctx, openai.CreateSpeechRequest{Model: openai.TTSModel1, Voice: "alloi"})
+	checks.ErrorIs(t, err, openai.ErrAudioValueUnknown, "expected misspelled voice to be rejected")
+	_, err = client.CreateSpeech(ctx, openai.CreateSpeechRequest{
+		Model:          openai.TTSModel1,
+		Voice:          openai.VoiceSage,
+		ResponseFormat: "mp4",
+	})
+	checks.ErrorIs(t, err, openai.ErrAudioValueUnknown, "expected unknown response format to be rejected")
+
+	config.AllowUnknownAudioValues = true
+	client = openai.NewClientWithConfig(config)
+	res, err := client.CreateSpeech(ctx, openai.CreateSpeechRequest{Model: openai.TTSModel1, Voice: "marin"})
+	checks.NoError(t, err, "unknown voices should be sent in forward compatible mode")
+	res.Close()
+	if sent.Voice != "marin" {
+		t.Fatalf("expected the unknown voice to be sent, got %q", sent.Voice)
+	}
+}
@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 )
 
@@ -14,6 +15,15 @@ type CompletionStream struct {
 	*streamReader[CompletionResponse]
 }
 
+// NewCompletionStreamFromReader returns a CompletionStream reading a raw
+// server-sent events stream from r, using the framing described on
+// NewChatCompletionStreamFromReader.
+func NewCompletionStreamFromReader(r io.Reader) *CompletionStream {
+	return &CompletionStream{
+		streamReader: newStreamReaderFromReader[CompletionResponse](r),
+	}
+}
+
 // CreateCompletionStream — API call to create a completion w/ streaming
 // support. It sets whether to stream back partial progress. If set, tokens will be
 // sent as data-only server-sent events as they become available, with the
@@ -21,6 +31,7 @@ type CompletionStream struct {
 func (c *Client) CreateCompletionStream(
 	ctx context.Context,
 	request CompletionRequest,
+	opts ...StreamOption,
 ) (stream *CompletionStream, err error) {
 	urlSuffix := "/completions"
 	if !checkEndpointSupportsModel(urlSuffix, request.Model) {
@@ -44,7 +55,7 @@ func (c *Client) CreateCompletionStream(
 		return nil, err
 	}
 
-	resp, err := sendRequestStream[CompletionResponse](c, req)
+	resp, err := sendRequestStream[CompletionResponse](c, req, opts...)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,73 @@
+package openai
+
+import (
+	"io"
+	"sync"
+)
+
+// StreamGroup tracks the open streams created with WithStreamGroup, so they can all be
+// closed at once, for example on graceful server shutdown. The zero value is not
+// usable; create groups with NewStreamGroup.
+type StreamGroup struct {
+	mu      sync.Mutex
+	streams map[io.Closer]struct{}
+	closed  bool
+}
+
+// NewStreamGroup returns an empty StreamGroup.
+func NewStreamGroup() *StreamGroup {
+	return &StreamGroup{streams: make(map[io.Closer]struct{})}
+}
+
+// WithStreamGroup registers the stream in g when it is created. The stream is removed
+// from g when it is closed.
+func WithStreamGroup(g *StreamGroup) StreamOption {
+	return func(o *streamOptions) {
+		o.group = g
+	}
+}
+
+// Len returns the number of open streams in the group.
+func (g *StreamGroup) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.streams)
+}
+
+// CloseAll closes every open stream of the group and returns the first error.
+// Streams created with the group afterwards are closed as soon as they are opened.
+func (g *StreamGroup) CloseAll() error {
+	g.mu.Lock()
+	streams := make([]io.Closer, 0, len(g.streams))
+	for stream := range g.streams {
+		streams = append(streams, stream)
+	}
+	g.streams = make(map[io.Closer]struct{})
+	g.closed = true
+	g.mu.Unlock()
+
+	var err error
+	for _, stream := range streams {
+		if closeErr := stream.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// add registers stream and reports false if the group was already closed.
+func (g *StreamGroup) add(stream io.Closer) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.streams[stream] = struct{}{}
+	return true
+}
+
+func (g *StreamGroup) remove(stream io.Closer) {
+	g.mu.Lock()
+	delete(g.streams, stream)
+	g.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestStreamGroupCloseAll(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	group := openai.NewStreamGroup()
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT3Dot5Turbo,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	}
+	open := func() *openai.ChatCompletionStream {
+		stream, err := client.CreateChatCompletionStream(context.Background(), request, openai.WithStreamGroup(group))
+		checks.NoError(t, err, "CreateChatCompletionStream error")
+		return stream
+	}
+
+	first, second, third := open(), open(), open()
+	if group.Len() != 3 {
+		t.Fatalf("expected 3 open streams, got %d", group.Len())
+	}
+	first.Close()
+	if group.Len() != 2 {
+		t.Fatalf("expected closed stream to be deregistered, got %d open", group.Len())
+	}
+
+	_, err := second.Recv()
+	checks.NoError(t, err, "Recv error")
+	checks.NoError(t, group.CloseAll(), "CloseAll error")
+	if group.Len() != 0 {
+		t.Fatalf("expected no open streams, got %d", group.Len())
+	}
+	for _, stream := range []*openai.ChatCompletionStream{second, third} {
+		if _, err = stream.Recv(); err == nil || errors.Is(err, io.EOF) {
+			t.Fatalf("expected closed stream to fail, got %v", err)
+		}
+	}
+
+	late := open()
+	defer late.Close()
+	if _, err = late.Recv(); err == nil || group.Len() != 0 {
+		t.Fatalf("expected stream opened after CloseAll to be closed, got %v", err)
+	}
+}
@@ -3,21 +3,33 @@ package openai
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	utils "github.com/sashabaranov/go-openai/internal"
 )
 
+const defaultStreamChannelBuffer = 16
+
+var ErrStreamIdleTimeout = errors.New("stream idle timeout: no data received")
+
 var (
 	headerData  = regexp.MustCompile(`^data:\s*`)
 	errorPrefix = regexp.MustCompile(`^data:\s*{"error":`)
+	// eventField matches the SSE fields other than data, such as the "event:" line the
+	// Responses API sends before each event.
+	eventField = regexp.MustCompile(`^(event|id|retry):`)
 )
 
 type streamable interface {
-	ChatCompletionStreamResponse | CompletionResponse
+	ChatCompletionStreamResponse | CompletionResponse | ImageStreamEvent | ResponseStreamEvent | MessageDeltaEvent
 }
 
 type streamReader[T streamable] struct {
@@ -28,12 +40,23 @@ type streamReader[T streamable] struct {
 	response       *http.Response
 	errAccumulator utils.ErrorAccumulator
 	unmarshaler    utils.Unmarshaler
+	capture        *streamCapture
+	group          *StreamGroup
+	// accept, when set, drops the events it returns false for from Recv.
+	accept func(raw []byte) bool
+
+	idleTimeout time.Duration
+	idleTimer   *time.Timer
+	idleExpired int32
 
 	httpHeader
 }
 
 func (stream *streamReader[T]) Recv() (response T, err error) {
 	rawLine, err := stream.RecvRaw()
+	for err == nil && stream.accept != nil && !stream.accept(rawLine) {
+		rawLine, err = stream.RecvRaw()
+	}
 	if err != nil {
 		return
 	}
@@ -50,7 +73,57 @@ func (stream *streamReader[T]) RecvRaw() ([]byte, error) {
 		return nil, io.EOF
 	}
 
-	return stream.processLines()
+	if stream.idleTimeout > 0 {
+		stream.idleTimer = time.AfterFunc(stream.idleTimeout, func() {
+			atomic.StoreInt32(&stream.idleExpired, 1)
+			stream.response.Body.Close()
+		})
+		defer stream.idleTimer.Stop()
+	}
+
+	line, err := stream.processLines()
+	if err != nil && atomic.LoadInt32(&stream.idleExpired) == 1 {
+		return nil, ErrStreamIdleTimeout
+	}
+	return line, err
+}
+
+// idleResetReader restarts the idle timer of its stream whenever data arrives.
+type idleResetReader[T streamable] struct {
+	r      io.Reader
+	stream *streamReader[T]
+}
+
+func (r *idleResetReader[T]) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 && r.stream.idleTimer != nil {
+		r.stream.idleTimer.Reset(r.stream.idleTimeout)
+	}
+	return n, err
+}
+
+// applyOptions wires the stream options into the body reader of the stream.
+func (stream *streamReader[T]) applyOptions(options streamOptions) {
+	if options.group != nil {
+		stream.group = options.group
+		if !stream.group.add(stream) {
+			stream.Close()
+		}
+	}
+	if options.idleTimeout <= 0 && options.capture == nil {
+		return
+	}
+
+	var body io.Reader = stream.response.Body
+	if options.idleTimeout > 0 {
+		stream.idleTimeout = options.idleTimeout
+		body = &idleResetReader[T]{r: body, stream: stream}
+	}
+	if options.capture != nil {
+		stream.capture = newStreamCapture(options.capture)
+		body = io.TeeReader(body, stream.capture)
+	}
+	stream.reader = bufio.NewReader(body)
 }
 
 //nolint:gocognit
@@ -62,6 +135,11 @@ func (stream *streamReader[T]) processLines() ([]byte, error) {
 
 	for {
 		rawLine, readErr := stream.reader.ReadBytes('\n')
+		if errors.Is(readErr, io.EOF) && len(rawLine) > 0 {
+			// Process a last line without a line break, such as a final "data: [DONE]",
+			// and report the end of the body on the next read.
+			readErr = nil
+		}
 		if readErr != nil || hasErrorPrefix {
 			respErr := stream.unmarshalError()
 			if respErr != nil {
@@ -71,6 +149,11 @@ func (stream *streamReader[T]) processLines() ([]byte, error) {
 		}
 
 		noSpaceLine := bytes.TrimSpace(rawLine)
+		// SSE comments, such as keep-alive pings, carry no event and must not be
+		// mistaken for an error body or count as empty messages.
+		if bytes.HasPrefix(noSpaceLine, []byte(":")) {
+			continue
+		}
 		if errorPrefix.Match(noSpaceLine) {
 			hasErrorPrefix = true
 		}
@@ -78,9 +161,12 @@ func (stream *streamReader[T]) processLines() ([]byte, error) {
 			if hasErrorPrefix {
 				noSpaceLine = headerData.ReplaceAll(noSpaceLine, nil)
 			}
-			writeErr := stream.errAccumulator.Write(noSpaceLine)
-			if writeErr != nil {
-				return nil, writeErr
+			// The event, id and retry fields still count as empty messages, but they are
+			// no part of an error body.
+			if !eventField.Match(noSpaceLine) {
+				if writeErr := stream.errAccumulator.Write(noSpaceLine); writeErr != nil {
+					return nil, writeErr
+				}
 			}
 			emptyMessagesCount++
 			if emptyMessagesCount > stream.emptyMessagesLimit {
@@ -114,6 +200,218 @@ func (stream *streamReader[T]) unmarshalError() (errResp *ErrorResponse) {
 	return
 }
 
+// Done reports whether the stream ended with the "data: [DONE]" sentinel. Recv returns
+// io.EOF both then and when the body ends without it, for instance because the
+// connection was closed early; Done tells the two apart.
+func (stream *streamReader[T]) Done() bool {
+	return stream.isFinished
+}
+
 func (stream *streamReader[T]) Close() error {
-	return stream.response.Body.Close()
+	err := stream.response.Body.Close()
+	if stream.capture != nil {
+		stream.capture.close()
+	}
+	if stream.group != nil {
+		stream.group.remove(stream)
+	}
+	return err
+}
+
+// newStreamReaderFromReader parses a previously captured stream from r instead
+// of a network response. Closing the stream closes r if it is an io.Closer.
+func newStreamReaderFromReader[T streamable](r io.Reader) *streamReader[T] {
+	body, ok := r.(io.ReadCloser)
+	if !ok {
+		body = io.NopCloser(r)
+	}
+	return &streamReader[T]{
+		emptyMessagesLimit: defaultEmptyMessagesLimit,
+		reader:             bufio.NewReader(body),
+		response:           &http.Response{Body: body, Header: http.Header{}},
+		errAccumulator:     utils.NewErrorAccumulator(),
+		unmarshaler:        &utils.JSONUnmarshaler{},
+		httpHeader:         httpHeader{},
+	}
+}
+
+// StreamEvent is a single item delivered by Events: either a decoded response or
+// the error which ended the stream.
+type StreamEvent[T streamable] struct {
+	Response T
+	Err      error
+}
+
+type streamOptions struct {
+	channelBuffer int
+	capture       io.Writer
+	idleTimeout   time.Duration
+	group         *StreamGroup
+
+	responseDecoder bool
+}
+
+// StreamOption configures a stream.
+type StreamOption func(*streamOptions)
+
+func newStreamOptions(opts []StreamOption) streamOptions {
+	options := streamOptions{channelBuffer: defaultStreamChannelBuffer}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithStreamResponseDecoder decodes the events of the stream with the configured
+// ClientConfig.ResponseDecoder instead of json.Unmarshal. It has no effect when the
+// client has no ResponseDecoder.
+func WithStreamResponseDecoder() StreamOption {
+	return func(o *streamOptions) {
+		o.responseDecoder = true
+	}
+}
+
+// WithStreamCapture copies the raw bytes of the HTTP response body to w as they
+// arrive, so a stream can be saved and replayed into the parser later.
+// Writes happen on a background goroutine and never block parsing; write errors
+// stop the capture without affecting the stream. Closing the stream waits until
+// everything received has been written to w.
+func WithStreamCapture(w io.Writer) StreamOption {
+	return func(o *streamOptions) {
+		o.capture = w
+	}
+}
+
+// WithStreamIdleTimeout makes Recv fail with ErrStreamIdleTimeout when no bytes
+// arrive for d while it waits for the next event, closing the connection. The timer
+// restarts on every received chunk and only runs while Recv is waiting, so a slow
+// consumer is not mistaken for a hung stream. It complements the context deadline,
+// which bounds the whole stream.
+func WithStreamIdleTimeout(d time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.idleTimeout = d
+	}
+}
+
+// WithStreamChannelBuffer sets how many events Events buffers ahead of the consumer.
+// Values below zero are treated as zero, making the channel unbuffered.
+func WithStreamChannelBuffer(n int) StreamOption {
+	return func(o *streamOptions) {
+		if n < 0 {
+			n = 0
+		}
+		o.channelBuffer = n
+	}
+}
+
+// Events reads the stream in a background goroutine and delivers the responses on
+// the returned channel, which is closed once the stream ends or ctx is done.
+// A clean end of stream is not reported as an event; any other error is sent as
+// the last event. When ctx is done the response body is closed, so that a Recv
+// waiting on an idle connection returns; the stream still has to be closed.
+//
+// The channel is bounded: when it is full the goroutine stops reading from the
+// connection until the consumer catches up. A very slow consumer may therefore
+// cause the server to time out the stream.
+func (stream *streamReader[T]) Events(ctx context.Context, opts ...StreamOption) <-chan StreamEvent[T] {
+	options := newStreamOptions(opts)
+	events := make(chan StreamEvent[T], options.channelBuffer)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.response.Body.Close()
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(events)
+		defer close(done)
+		for {
+			response, err := stream.Recv()
+			if errors.Is(err, io.EOF) || (err != nil && ctx.Err() != nil) {
+				return
+			}
+			select {
+			case events <- StreamEvent[T]{Response: response, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// streamCapture queues chunks written to it and forwards them to w from a
+// background goroutine, so that a slow writer cannot stall the stream.
+type streamCapture struct {
+	w io.Writer
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  [][]byte
+	closed bool
+	done   chan struct{}
+}
+
+func newStreamCapture(w io.Writer) *streamCapture {
+	c := &streamCapture{w: w, done: make(chan struct{})}
+	c.cond = sync.NewCond(&c.mu)
+	go c.run()
+	return c
+}
+
+func (c *streamCapture) Write(p []byte) (int, error) {
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+
+	c.mu.Lock()
+	if !c.closed {
+		c.queue = append(c.queue, chunk)
+		c.cond.Signal()
+	}
+	c.mu.Unlock()
+	return len(p), nil
+}
+
+func (c *streamCapture) run() {
+	defer close(c.done)
+	var failed bool
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+		queue := c.queue
+		c.queue = nil
+		closed := c.closed
+		c.mu.Unlock()
+
+		for _, chunk := range queue {
+			if !failed {
+				_, err := c.w.Write(chunk)
+				failed = err != nil
+			}
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+// close stops accepting chunks and waits until the queued ones are written.
+func (c *streamCapture) close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		<-c.done
+		return
+	}
+	c.closed = true
+	c.cond.Signal()
+	c.mu.Unlock()
+	<-c.done
 }
@@ -3,8 +3,11 @@ package openai //nolint:testpackage // testing private field
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
 	utils "github.com/sashabaranov/go-openai/internal"
 	"github.com/sashabaranov/go-openai/internal/test"
@@ -52,6 +55,92 @@ func TestStreamReaderReturnsErrTooManyEmptyStreamMessages(t *testing.T) {
 	checks.ErrorIs(t, err, ErrTooManyEmptyStreamMessages, "Did not return error when recv failed", err.Error())
 }
 
+func TestStreamReaderSkipsComments(t *testing.T) {
+	stream := &streamReader[ChatCompletionStreamResponse]{
+		emptyMessagesLimit: 1,
+		reader: bufio.NewReader(bytes.NewReader([]byte(": keep-alive\n: keep-alive\n: keep-alive\n\n" +
+			"data: {\"id\":\"1\"}\n\n: PROCESSING\n:\ndata: {\"id\":\"2\"}\n\n: done soon\ndata: [DONE]\n\n"))),
+		errAccumulator: utils.NewErrorAccumulator(),
+		unmarshaler:    &utils.JSONUnmarshaler{},
+	}
+
+	var ids []string
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "Recv should skip comment lines")
+		ids = append(ids, response.ID)
+	}
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("expected the two data events only, got %v", ids)
+	}
+	if len(stream.errAccumulator.Bytes()) != 0 {
+		t.Fatalf("comment lines should not be accumulated as an error body, got %q", stream.errAccumulator.Bytes())
+	}
+}
+
+func TestStreamReaderSkipsEventFields(t *testing.T) {
+	stream := &streamReader[ChatCompletionStreamResponse]{
+		emptyMessagesLimit: 10,
+		reader: bufio.NewReader(bytes.NewReader([]byte("event: chunk\nid: 1\nretry: 1000\ndata: {\"id\":\"1\"}\n\n" +
+			"event: error\nid: 2\ndata: {\"error\":{\"message\":\"server overloaded\"}}\n\n"))),
+		errAccumulator: utils.NewErrorAccumulator(),
+		unmarshaler:    &utils.JSONUnmarshaler{},
+	}
+
+	response, err := stream.Recv()
+	checks.NoError(t, err, "Recv should read data after event fields")
+	if response.ID != "1" {
+		t.Fatalf("unexpected response id %q", response.ID)
+	}
+	_, err = stream.Recv()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "server overloaded" {
+		t.Fatalf("expected the API error without the event fields, got %v", err)
+	}
+	if bytes.Contains(stream.errAccumulator.Bytes(), []byte("event")) {
+		t.Fatalf("event fields should not be accumulated as an error body, got %q", stream.errAccumulator.Bytes())
+	}
+}
+
+func TestStreamReaderDone(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		done bool
+	}{
+		{"done", "data: {\"id\":\"1\"}\n\ndata: [DONE]\n\n", true},
+		{"trailing whitespace", "data: {\"id\":\"1\"}\n\ndata: [DONE]  \t\n\n", true},
+		{"crlf", "data: {\"id\":\"1\"}\r\n\r\ndata:[DONE]\r\n\r\n", true},
+		{"no final line break", "data: {\"id\":\"1\"}\n\ndata: [DONE]", true},
+		{"truncated", "data: {\"id\":\"1\"}\n\n", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &streamReader[ChatCompletionStreamResponse]{
+				emptyMessagesLimit: 10,
+				reader:             bufio.NewReader(bytes.NewReader([]byte(tc.body))),
+				errAccumulator:     utils.NewErrorAccumulator(),
+				unmarshaler:        &utils.JSONUnmarshaler{},
+			}
+			response, err := stream.Recv()
+			checks.NoError(t, err, "Recv error")
+			if response.ID != "1" || stream.Done() {
+				t.Fatalf("unexpected first response %+v, done %v", response, stream.Done())
+			}
+			_, err = stream.Recv()
+			checks.ErrorIs(t, err, io.EOF, "expected io.EOF at the end of the stream")
+			if stream.Done() != tc.done {
+				t.Fatalf("expected Done to be %v", tc.done)
+			}
+			_, err = stream.Recv()
+			checks.ErrorIs(t, err, io.EOF, "expected io.EOF after the end of the stream")
+		})
+	}
+}
+
 func TestStreamReaderReturnsErrTestErrorAccumulatorWriteFailed(t *testing.T) {
 	stream := &streamReader[ChatCompletionStreamResponse]{
 		reader: bufio.NewReader(bytes.NewReader([]byte("\n"))),
@@ -76,3 +165,66 @@ func TestStreamReaderRecvRaw(t *testing.T) {
 		t.Fatalf("Did not return raw line: %v", string(rawLine))
 	}
 }
+
+func TestStreamReaderEvents(t *testing.T) {
+	data := "data: {\"id\":\"1\"}\n\ndata: {\"id\":\"2\"}\n\ndata: [DONE]\n\n"
+	stream := &streamReader[ChatCompletionStreamResponse]{
+		emptyMessagesLimit: 5,
+		reader:             bufio.NewReader(bytes.NewReader([]byte(data))),
+		errAccumulator:     utils.NewErrorAccumulator(),
+		unmarshaler:        &utils.JSONUnmarshaler{},
+	}
+
+	events := stream.Events(context.Background(), WithStreamChannelBuffer(1))
+	if cap(events) != 1 {
+		t.Fatalf("expected a channel buffer of 1, got %d", cap(events))
+	}
+	var ids []string
+	for event := range events {
+		checks.NoError(t, event.Err, "unexpected stream error")
+		ids = append(ids, event.Response.ID)
+	}
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("unexpected events: %v", ids)
+	}
+}
+
+func TestStreamReaderEventsCloseOnCancel(t *testing.T) {
+	body, upstream := io.Pipe()
+	defer upstream.Close()
+	stream := newStreamReaderFromReader[ChatCompletionStreamResponse](body)
+	defer stream.Close()
+	go upstream.Write([]byte("data: {\"id\":\"1\"}\n\n")) //nolint:errcheck // the test reads the event
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := stream.Events(ctx)
+	if event := <-events; event.Err != nil || event.Response.ID != "1" {
+		t.Fatalf("unexpected first event: %+v", event)
+	}
+
+	// The upstream stays idle, so only the cancellation can end Recv.
+	cancel()
+	select {
+	case event, ok := <-events:
+		if ok {
+			t.Fatalf("expected no event after cancellation, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the channel to be closed after cancellation")
+	}
+}
+
+func TestStreamReaderEventsReportsError(t *testing.T) {
+	stream := &streamReader[ChatCompletionStreamResponse]{
+		emptyMessagesLimit: 1,
+		reader:             bufio.NewReader(bytes.NewReader([]byte("\n\n\n"))),
+		errAccumulator:     utils.NewErrorAccumulator(),
+		unmarshaler:        &utils.JSONUnmarshaler{},
+	}
+
+	var last StreamEvent[ChatCompletionStreamResponse]
+	for event := range stream.Events(context.Background()) {
+		last = event
+	}
+	checks.ErrorIs(t, last.Err, ErrTooManyEmptyStreamMessages, "expected the stream error as the last event")
+}
@@ -2,7 +2,10 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 )
 
 const (
@@ -19,6 +22,11 @@ type Thread struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (t Thread) CreatedTime() time.Time {
+	return time.Unix(t.CreatedAt, 0)
+}
+
 type ThreadRequest struct {
 	Messages      []ThreadMessage       `json:"messages,omitempty"`
 	Metadata      map[string]any        `json:"metadata,omitempty"`
@@ -88,11 +96,72 @@ const (
 )
 
 type ThreadMessage struct {
-	Role        ThreadMessageRole  `json:"role"`
-	Content     string             `json:"content"`
-	FileIDs     []string           `json:"file_ids,omitempty"`
-	Attachments []ThreadAttachment `json:"attachments,omitempty"`
-	Metadata    map[string]any     `json:"metadata,omitempty"`
+	Role    ThreadMessageRole `json:"role"`
+	Content string            `json:"content"`
+	// ContentParts replaces Content to send text, image_file, image_url and video_url
+	// parts. Only one of Content and ContentParts may be set.
+	ContentParts []MessageContent   `json:"-"`
+	FileIDs      []string           `json:"file_ids,omitempty"`
+	Attachments  []ThreadAttachment `json:"attachments,omitempty"`
+	Metadata     map[string]any     `json:"metadata,omitempty"`
+}
+
+// MarshalJSON sends Content, or a single text part, as a string and other ContentParts
+// as an array of parts.
+func (m ThreadMessage) MarshalJSON() ([]byte, error) {
+	content, err := messageRequestContent(m.Content, m.ContentParts)
+	if err != nil {
+		return nil, err
+	}
+	type alias ThreadMessage
+	return json.Marshal(struct {
+		alias
+		Content any `json:"content"`
+	}{alias(m), content})
+}
+
+// ThreadMessage returns the request as a ThreadMessage, to send the messages created
+// with CreateMessage in a single request instead, as the messages of a thread or the
+// additional messages of a run. FileIds are copied unchanged to FileIDs.
+func (r MessageRequest) ThreadMessage() ThreadMessage {
+	return ThreadMessage{
+		Role:         ThreadMessageRole(r.Role),
+		Content:      r.Content,
+		ContentParts: r.ContentParts,
+		FileIDs:      r.FileIds,
+		Attachments:  r.Attachments,
+		Metadata:     r.Metadata,
+	}
+}
+
+func (m ThreadMessage) messageRequest() MessageRequest {
+	return MessageRequest{
+		Role:         string(m.Role),
+		Content:      m.Content,
+		ContentParts: m.ContentParts,
+		FileIds:      m.FileIDs,
+		Attachments:  m.Attachments,
+		Metadata:     m.Metadata,
+	}
+}
+
+// validateThreadMessages applies the checks of CreateMessage to messages sent together,
+// message by message, the field name and index of the message prefixing the error.
+func (c *Client) validateThreadMessages(field string, messages []ThreadMessage) error {
+	for i, message := range messages {
+		request := message.messageRequest()
+		err := request.validate()
+		if err == nil {
+			err = c.validateContentParts(request.ContentParts)
+		}
+		if err == nil {
+			err = c.validateMessageMetadata(request.Metadata)
+		}
+		if err != nil {
+			return fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+	}
+	return nil
 }
 
 type ThreadAttachment struct {
@@ -104,6 +173,16 @@ type ThreadAttachmentTool struct {
 	Type string `json:"type"`
 }
 
+// NewThreadAttachment attaches fileID to a message and routes it to the given tools,
+// typically AssistantToolTypeFileSearch or AssistantToolTypeCodeInterpreter.
+func NewThreadAttachment(fileID string, tools ...AssistantToolType) ThreadAttachment {
+	attachment := ThreadAttachment{FileID: fileID, Tools: make([]ThreadAttachmentTool, len(tools))}
+	for i, tool := range tools {
+		attachment.Tools[i] = ThreadAttachmentTool{Type: string(tool)}
+	}
+	return attachment
+}
+
 type ThreadDeleteResponse struct {
 	ID      string `json:"id"`
 	Object  string `json:"object"`
@@ -114,6 +193,11 @@ type ThreadDeleteResponse struct {
 
 // CreateThread creates a new thread.
 func (c *Client) CreateThread(ctx context.Context, request ThreadRequest) (response Thread, err error) {
+	request, err = c.mergeDefaultThreadMetadata(request)
+	if err != nil {
+		return
+	}
+
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(threadsSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
 	if err != nil {
@@ -0,0 +1,49 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrDuplicateToolName = errors.New("duplicate tool function name")
+
+// checkDuplicateToolNames returns ErrDuplicateToolName naming the first function
+// name which appears more than once.
+func checkDuplicateToolNames(names []string) error {
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("%w: %q", ErrDuplicateToolName, name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+func validateChatToolNames(request ChatCompletionRequest) error {
+	names := make([]string, 0, len(request.Tools))
+	for _, tool := range request.Tools {
+		if tool.Function != nil {
+			names = append(names, tool.Function.Name)
+		}
+	}
+	if err := checkDuplicateToolNames(names); err != nil {
+		return err
+	}
+
+	names = names[:0]
+	for _, function := range request.Functions {
+		names = append(names, function.Name)
+	}
+	return checkDuplicateToolNames(names)
+}
+
+func validateAssistantToolNames(tools []AssistantTool) error {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function != nil {
+			names = append(names, tool.Function.Name)
+		}
+	}
+	return checkDuplicateToolNames(names)
+}
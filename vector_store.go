@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
@@ -36,6 +37,11 @@ type VectorStore struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (v VectorStore) CreatedTime() time.Time {
+	return time.Unix(v.CreatedAt, 0)
+}
+
 type VectorStoreExpires struct {
 	Anchor string `json:"anchor"`
 	Days   int    `json:"days"`
@@ -77,6 +83,11 @@ type VectorStoreFile struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (f VectorStoreFile) CreatedTime() time.Time {
+	return time.Unix(f.CreatedAt, 0)
+}
+
 type VectorStoreFileRequest struct {
 	FileID string `json:"file_id"`
 }
@@ -101,6 +112,11 @@ type VectorStoreFileBatch struct {
 	httpHeader
 }
 
+// CreatedTime returns CreatedAt as a time.Time.
+func (b VectorStoreFileBatch) CreatedTime() time.Time {
+	return time.Unix(b.CreatedAt, 0)
+}
+
 type VectorStoreFileBatchRequest struct {
 	FileIDs []string `json:"file_ids"`
 }
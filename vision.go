@@ -0,0 +1,117 @@
+package openai
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrImageInputInvalid = errors.New("invalid image input")
+
+// visionImageTypes are the image MIME types accepted by vision models.
+var visionImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ImageInput is an image attached to a vision chat message: either a URL, which may
+// be a base64 data URL, or the ID of an uploaded file. Use NewImageURLInput,
+// NewImageDataInput or NewImageFileInput to create one.
+//
+// Chat completions have no image part referring to an uploaded file, so a FileID is
+// sent as a file part, which models read as a document such as a PDF rather than as an
+// image. Use a URL or a data URL for images the model should see.
+type ImageInput struct {
+	URL    string
+	FileID string
+	// Detail applies to URL images.
+	Detail ImageURLDetail
+}
+
+// NewImageURLInput returns an image referenced by an http(s) or data URL.
+func NewImageURLInput(imageURL string, detail ImageURLDetail) ImageInput {
+	return ImageInput{URL: imageURL, Detail: detail}
+}
+
+// NewImageDataInput returns an image embedded as a base64 data URL.
+func NewImageDataInput(mimeType string, data []byte, detail ImageURLDetail) ImageInput {
+	return ImageInput{
+		URL:    "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data),
+		Detail: detail,
+	}
+}
+
+// NewImageFileInput returns a file uploaded with the files API, sent as a file part
+// rather than an image part; see ImageInput.
+func NewImageFileInput(fileID string) ImageInput {
+	return ImageInput{FileID: fileID}
+}
+
+func (in ImageInput) validate() error {
+	switch {
+	case in.URL != "" && in.FileID != "":
+		return fmt.Errorf("%w: set either URL or FileID", ErrImageInputInvalid)
+	case in.FileID != "":
+		if in.Detail != "" {
+			return fmt.Errorf("%w: detail is only supported for URL images", ErrImageInputInvalid)
+		}
+		return nil
+	case in.URL == "":
+		return fmt.Errorf("%w: URL or FileID is required", ErrImageInputInvalid)
+	}
+
+	if !in.Detail.valid() {
+		return fmt.Errorf("%w: unsupported detail %q", ErrImageInputInvalid, in.Detail)
+	}
+
+	if strings.HasPrefix(in.URL, "data:") {
+		mimeType, _, ok := strings.Cut(strings.TrimPrefix(in.URL, "data:"), ";base64,")
+		if !ok || !visionImageTypes[strings.ToLower(mimeType)] {
+			return fmt.Errorf("%w: data URL must be a base64 png, jpeg, gif or webp image", ErrImageInputInvalid)
+		}
+		return nil
+	}
+
+	// The format of an http(s) image is only known from the response, so it is left to
+	// the API instead of being guessed from the URL path.
+	parsed, err := url.Parse(in.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: %q is not an http(s) or data URL", ErrImageInputInvalid, in.URL)
+	}
+	return nil
+}
+
+// NewVisionUserMessage builds a user message whose content is text followed by images,
+// in the order given. Empty text is left out. URL images become image_url parts, and
+// the format of data URLs is checked from their MIME type; file inputs become file parts.
+func NewVisionUserMessage(text string, images ...ImageInput) (message ChatCompletionMessage, err error) {
+	message.Role = ChatMessageRoleUser
+	if text != "" {
+		message.MultiContent = append(message.MultiContent, ChatMessagePart{
+			Type: ChatMessagePartTypeText,
+			Text: text,
+		})
+	}
+	for i, image := range images {
+		if err = image.validate(); err != nil {
+			err = fmt.Errorf("images[%d]: %w", i, err)
+			return
+		}
+		if image.FileID != "" {
+			message.MultiContent = append(message.MultiContent, ChatMessagePart{
+				Type: ChatMessagePartTypeFile,
+				File: &ChatMessageFile{FileID: image.FileID},
+			})
+			continue
+		}
+		message.MultiContent = append(message.MultiContent, ChatMessagePart{
+			Type:     ChatMessagePartTypeImageURL,
+			ImageURL: &ChatMessageImageURL{URL: image.URL, Detail: image.Detail},
+		})
+	}
+	return
+}
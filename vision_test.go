@@ -0,0 +1,57 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestNewVisionUserMessage(t *testing.T) {
+	message, err := openai.NewVisionUserMessage("What is in these images?",
+		openai.NewImageURLInput("https://example.com/cat.png", openai.ImageURLDetailLow),
+		openai.NewImageURLInput("https://cdn.example.com/img.PNG?sig=abc", ""),
+		openai.NewImageURLInput("https://example.com/images/1234", ""),
+		openai.NewImageDataInput("image/jpeg", []byte("jpeg"), ""),
+		openai.NewImageFileInput("file-abc123"),
+	)
+	checks.NoError(t, err, "NewVisionUserMessage error")
+
+	data, err := json.Marshal(message)
+	checks.NoError(t, err, "Marshal error")
+	expected := `{"role":"user","content":[{"type":"text","text":"What is in these images?"},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"low"}},` +
+		`{"type":"image_url","image_url":{"url":"https://cdn.example.com/img.PNG?sig=abc"}},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/images/1234"}},` +
+		`{"type":"image_url","image_url":{"url":"data:image/jpeg;base64,anBlZw=="}},` +
+		`{"type":"file","file":{"file_id":"file-abc123"}}]}`
+	if string(data) != expected {
+		t.Fatalf("unexpected message:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestNewVisionUserMessageInvalidImages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		image openai.ImageInput
+	}{
+		{"empty", openai.ImageInput{}},
+		{"url and file", openai.ImageInput{URL: "https://example.com/a.png", FileID: "file-abc123"}},
+		{"detail on file", openai.ImageInput{FileID: "file-abc123", Detail: openai.ImageURLDetailHigh}},
+		{"unknown detail", openai.NewImageURLInput("https://example.com/a.png", "ultra")},
+		{"unsupported scheme", openai.NewImageURLInput("ftp://example.com/a.png", "")},
+		{"unsupported data type", openai.NewImageDataInput("image/svg+xml", []byte("<svg/>"), "")},
+		{"data URL without base64", openai.NewImageURLInput("data:image/png,abc", "")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := openai.NewVisionUserMessage("Describe", tc.image)
+			checks.ErrorIs(t, err, openai.ErrImageInputInvalid, "expected invalid image input")
+			if !strings.HasPrefix(err.Error(), "images[0]: ") {
+				t.Fatalf("expected the image index in the error, got %v", err)
+			}
+		})
+	}
+}